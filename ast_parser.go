@@ -3,19 +3,34 @@ package openapi
 import (
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/parser"
 	"go/token"
+	"os"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
 func extractDTOsFromFile(path string) (map[string]dtoSchema, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+	return extractDTOs(path, src)
+}
+
+// extractDTOs parses the DTOs of the Go source of filename.
+func extractDTOs(filename string, src []byte) (map[string]dtoSchema, error) {
 	fs := token.NewFileSet()
-	node, err := parser.ParseFile(fs, path, nil, parser.AllErrors)
+	node, err := parser.ParseFile(fs, filename, src, parser.AllErrors|parser.ParseComments)
 	if err != nil {
 		return nil, fmt.Errorf("parse error: %w", err)
 	}
 
 	dtos := make(map[string]dtoSchema)
+	interfaces := localInterfaceTypes(node)
+	structs := localStructTypes(node)
 
 	for _, decl := range node.Decls {
 		gen, ok := decl.(*ast.GenDecl)
@@ -38,10 +53,17 @@ func extractDTOsFromFile(path string) (map[string]dtoSchema, error) {
 				continue
 			}
 
-			fields := extractStructFieldsFromAST(st)
+			fields := resolveLocalEmbedded(extractStructFieldsFromAST(st), structs, map[string]bool{ts.Name.Name: true})
+			for i := range fields {
+				// Named interfaces serialize as arbitrary JSON, same as interface{}
+				if interfaces[fields[i].Type] {
+					fields[i].Type = "interface{}"
+				}
+			}
 			dtos[ts.Name.Name] = dtoSchema{
-				Name:   ts.Name.Name,
-				Fields: fields,
+				Name:        ts.Name.Name,
+				Description: typeDocComment(gen, ts),
+				Fields:      fields,
 			}
 		}
 	}
@@ -49,72 +71,316 @@ func extractDTOsFromFile(path string) (map[string]dtoSchema, error) {
 	return dtos, nil
 }
 
-func extractStructFieldsFromAST(st *ast.StructType) []structField {
-	var fields []structField
+// typeDocComment returns the doc comment of a type declaration, which sits on
+// the TypeSpec in a grouped declaration and on the GenDecl otherwise.
+func typeDocComment(gen *ast.GenDecl, ts *ast.TypeSpec) string {
+	doc := ts.Doc
+	if doc == nil && len(gen.Specs) == 1 {
+		doc = gen.Doc
+	}
+	return strings.TrimSpace(doc.Text())
+}
 
-	for _, field := range st.Fields.List {
-		if len(field.Names) == 0 {
+// localInterfaceTypes returns the names of the interface types declared in the
+// parsed file, so fields typed with them can be documented as objects.
+func localInterfaceTypes(node *ast.File) map[string]bool {
+	interfaces := make(map[string]bool)
+	for _, decl := range node.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
 			continue
 		}
+		for _, spec := range gen.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok {
+				if _, ok := ts.Type.(*ast.InterfaceType); ok {
+					interfaces[ts.Name.Name] = true
+				}
+			}
+		}
+	}
+	return interfaces
+}
 
-		fieldName := field.Names[0].Name
-		fieldType := ""
-		isPointer := false
+// localNamedTypes returns the types declared in the parsed file on top of
+// another type (type Status int, type Tags []string), by name, along with the
+// fields of the inline struct they hold (type Lines []struct{...}). Structs
+// and interfaces are left out, as DTOs and arbitrary JSON cover them.
+func localNamedTypes(node *ast.File) map[string]namedType {
+	types := make(map[string]namedType)
+	for _, decl := range node.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.TypeParams != nil {
+				continue
+			}
+			underlying, fields := astTypeName(ts.Type)
+			if underlying == "" || underlying == "struct" || underlying == "interface{}" {
+				continue
+			}
+			types[ts.Name.Name] = namedType{Underlying: underlying, Fields: fields}
+		}
+	}
+	return types
+}
 
-		switch t := field.Type.(type) {
-		case *ast.Ident:
-			fieldType = t.Name
-		case *ast.StarExpr:
-			isPointer = true
-			if ident, ok := t.X.(*ast.Ident); ok {
-				fieldType = ident.Name
-			} else if sel, ok := t.X.(*ast.SelectorExpr); ok {
-				if pkg, ok := sel.X.(*ast.Ident); ok {
-					fieldType = pkg.Name + "." + sel.Sel.Name
+// localConstEnums returns the values of the constants declared in the parsed
+// file with a local type (StatusActive Status = "active", or Status("active")),
+// by type name. Constants without a type or value repeat the previous ones of
+// their group, the way iota enums are written.
+func localConstEnums(node *ast.File) map[string][]interface{} {
+	enums := make(map[string][]interface{})
+	for _, decl := range node.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+
+		var typ ast.Expr
+		var values []ast.Expr
+		for iota, spec := range gen.Specs {
+			vs, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			if vs.Type != nil || len(vs.Values) > 0 {
+				typ, values = vs.Type, vs.Values
+			}
+
+			for i, name := range vs.Names {
+				if name.Name == "_" || i >= len(values) {
+					continue
+				}
+				typeName, expr := constType(typ, values[i])
+				if typeName == "" {
+					continue
+				}
+				if value, ok := constValue(expr, iota); ok {
+					enums[typeName] = append(enums[typeName], value)
 				}
 			}
-		case *ast.SelectorExpr:
-			if pkg, ok := t.X.(*ast.Ident); ok {
-				fieldType = pkg.Name + "." + t.Sel.Name
+		}
+	}
+	return enums
+}
+
+// constType returns the local type of a constant and the expression of its
+// value, unwrapping a conversion (Status("active")). The type is empty for
+// untyped constants and types of other packages.
+func constType(typ, value ast.Expr) (string, ast.Expr) {
+	if typ == nil {
+		call, ok := value.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return "", nil
+		}
+		typ, value = call.Fun, call.Args[0]
+	}
+	if ident, ok := typ.(*ast.Ident); ok {
+		return ident.Name, value
+	}
+	return "", nil
+}
+
+// constValue evaluates a constant expression made of literals, iota and
+// operators, returning its value as a string, int64, float64 or bool.
+func constValue(expr ast.Expr, iota int) (result interface{}, ok bool) {
+	// Files are not type-checked: go/constant panics on an invalid
+	// expression (division by zero, mismatched operands), which is skipped
+	defer func() {
+		if recover() != nil {
+			result, ok = nil, false
+		}
+	}()
+
+	return constantValue(evalConst(expr, iota))
+}
+
+// constantValue converts a constant to a string, int64, float64 or bool.
+func constantValue(value constant.Value) (interface{}, bool) {
+	switch value.Kind() {
+	case constant.String:
+		return constant.StringVal(value), true
+	case constant.Int:
+		if v, exact := constant.Int64Val(value); exact {
+			return v, true
+		}
+	case constant.Float:
+		v, _ := constant.Float64Val(value)
+		return v, true
+	case constant.Bool:
+		return constant.BoolVal(value), true
+	}
+	return nil, false
+}
+
+func evalConst(expr ast.Expr, iota int) constant.Value {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return constant.MakeFromLiteral(e.Value, e.Kind, 0)
+	case *ast.Ident:
+		switch e.Name {
+		case "iota":
+			return constant.MakeInt64(int64(iota))
+		case "true", "false":
+			return constant.MakeBool(e.Name == "true")
+		}
+	case *ast.ParenExpr:
+		return evalConst(e.X, iota)
+	case *ast.UnaryExpr:
+		x := evalConst(e.X, iota)
+		if x.Kind() != constant.Unknown {
+			return constant.UnaryOp(e.Op, x, 0)
+		}
+	case *ast.BinaryExpr:
+		x, y := evalConst(e.X, iota), evalConst(e.Y, iota)
+		if x.Kind() == constant.Unknown || y.Kind() == constant.Unknown {
+			break
+		}
+		switch e.Op {
+		case token.SHL, token.SHR:
+			if s, ok := constant.Uint64Val(y); ok {
+				return constant.Shift(x, e.Op, uint(s))
+			}
+		case token.QUO:
+			// Integer operands divide as integers, the way Go evaluates them
+			if x.Kind() == constant.Int && y.Kind() == constant.Int {
+				return constant.BinaryOp(x, token.QUO_ASSIGN, y)
 			}
-		case *ast.InterfaceType:
-			fieldType = "interface{}"
-		case *ast.MapType:
-			fieldType = "map[string]interface{}"
+			return constant.BinaryOp(x, e.Op, y)
+		default:
+			return constant.BinaryOp(x, e.Op, y)
 		}
+	}
+	return constant.MakeUnknown()
+}
 
-		jsonTag := ""
-		dbTag := ""
-		dtoTag := ""
-		if field.Tag != nil {
-			tag := field.Tag.Value
-			jsonTag = extractTag(tag, "json")
-			jsonTag = strings.Split(jsonTag, ",")[0]
-			dbTag = extractTag(tag, "db")
-			dtoTag = extractTag(tag, "dto")
+// localStructTypes returns the struct types declared in the parsed file, by
+// name, so embedded fields can be resolved to their members.
+func localStructTypes(node *ast.File) map[string]*ast.StructType {
+	structs := make(map[string]*ast.StructType)
+	for _, decl := range node.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
 		}
+		for _, spec := range gen.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok {
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					structs[ts.Name.Name] = st
+				}
+			}
+		}
+	}
+	return structs
+}
 
-		fields = append(fields, structField{
+// resolveLocalEmbedded fills the members of embedded fields whose struct is
+// declared in the same file, recursively. visiting holds the structs being
+// resolved so a cycle stops instead of looping.
+func resolveLocalEmbedded(fields []structField, structs map[string]*ast.StructType, visiting map[string]bool) []structField {
+	for i := range fields {
+		field := &fields[i]
+		st, ok := structs[field.Type]
+		if !field.Embedded || field.Fields != nil || !ok || visiting[field.Type] {
+			continue
+		}
+		visiting[field.Type] = true
+		field.Fields = resolveLocalEmbedded(extractStructFieldsFromAST(st), structs, visiting)
+		delete(visiting, field.Type)
+	}
+	return fields
+}
+
+func extractStructFieldsFromAST(st *ast.StructType) []structField {
+	var fields []structField
+
+	for _, field := range st.Fields.List {
+		isPointer := false
+
+		typeExpr := field.Type
+		if star, ok := typeExpr.(*ast.StarExpr); ok {
+			isPointer = true
+			typeExpr = star.X
+		}
+		fieldType, nested := astTypeName(typeExpr)
+
+		// An embedded field is named after its type, without the package
+		embedded := len(field.Names) == 0
+		var fieldName string
+		if embedded {
+			fieldName = fieldType[strings.LastIndex(fieldType, ".")+1:]
+		} else {
+			fieldName = field.Names[0].Name
+		}
+
+		parsed := structField{
 			Name:      fieldName,
 			Type:      fieldType,
-			JSONTag:   jsonTag,
-			DBTag:     dbTag,
-			DTOTag:    dtoTag,
 			IsPointer: isPointer,
-		})
+			Fields:    nested,
+		}
+		if field.Tag != nil {
+			applyFieldTags(&parsed, field.Tag.Value)
+		}
+		// A json name makes encoding/json treat an embedded struct as a
+		// regular field
+		parsed.Embedded = embedded && parsed.JSONTag == ""
+
+		fields = append(fields, parsed)
 	}
 
 	return fields
 }
 
-func extractTag(tagString, key string) string {
-	tagString = strings.Trim(tagString, "`")
-	for _, tag := range strings.Fields(tagString) {
-		if strings.HasPrefix(tag, key+":") {
-			value := strings.TrimPrefix(tag, key+":")
-			value = strings.Trim(value, `"`)
-			return value
+// applyFieldTags fills the attributes of field read from its struct tag.
+func applyFieldTags(field *structField, tag string) {
+	field.JSONTag = strings.Split(extractTag(tag, "json"), ",")[0]
+	field.DBTag = extractTag(tag, "db")
+	field.DTOTag = extractTag(tag, "dto")
+	field.OpenAPITag = extractTag(tag, "openapi")
+	field.ValidateTag = extractTag(tag, "validate")
+	field.DescriptionTag = extractTag(tag, "description")
+	field.Deprecated, _ = strconv.ParseBool(extractTag(tag, "deprecated"))
+	field.DefaultTag = extractTag(tag, "default")
+	field.XMLTag = extractTag(tag, "xml")
+	field.EnumLabelsTag = extractTag(tag, "enum_labels")
+	field.UnionKeyword, field.UnionTypes = unionTag(extractTag(tag, oneOfTypesTag), extractTag(tag, anyOfTypesTag))
+}
+
+// astTypeName renders a field type expression as the type string understood by
+// the schema builder. Slices are prefixed with "[]", maps keep their key and
+// value types (map[string]int64) and inline struct types are reported as
+// "struct" together with their own fields.
+func astTypeName(expr ast.Expr) (string, []structField) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name, nil
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok {
+			return pkg.Name + "." + t.Sel.Name, nil
 		}
+	case *ast.StarExpr:
+		return astTypeName(t.X)
+	case *ast.ArrayType:
+		elem, nested := astTypeName(t.Elt)
+		return "[]" + elem, nested
+	case *ast.StructType:
+		return "struct", extractStructFieldsFromAST(t)
+	case *ast.InterfaceType:
+		return "interface{}", nil
+	case *ast.MapType:
+		key, _ := astTypeName(t.Key)
+		value, nested := astTypeName(t.Value)
+		return "map[" + key + "]" + value, nested
 	}
-	return ""
+	return "", nil
+}
+
+// extractTag returns the value of key in a struct tag literal, with or
+// without its backquotes. Values may hold spaces (description:"Sign-in email").
+func extractTag(tagString, key string) string {
+	return reflect.StructTag(strings.Trim(tagString, "`")).Get(key)
 }
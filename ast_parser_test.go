@@ -1,6 +1,9 @@
 package openapi
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -62,6 +65,12 @@ func TestExtractTag(t *testing.T) {
 			key:       "json",
 			want:      "-",
 		},
+		{
+			name:      "tag value with spaces",
+			tagString: "`json:\"email\" description:\"Sign-in email, unique\"`",
+			key:       "description",
+			want:      "Sign-in email, unique",
+		},
 		{
 			name:      "tag without backticks",
 			tagString: "json:\"name\"",
@@ -237,6 +246,77 @@ type ConfigDTO struct {
 			},
 			wantErr: false,
 		},
+		{
+			name:     "DTO with named interface field declared in file",
+			fileName: "event_payload.go",
+			fileContent: `package dto
+
+type Payload interface {
+	Kind() string
+}
+
+type EventPayloadDTO struct {
+	Kind    string  ` + "`json:\"kind\"`" + `
+	Payload Payload ` + "`json:\"payload\"`" + `
+}`,
+			wantDTOs: map[string]dtoSchema{
+				"EventPayloadDTO": {
+					Name: "EventPayloadDTO",
+					Fields: []structField{
+						{Name: "Kind", Type: "string", JSONTag: "kind", IsPointer: false},
+						{Name: "Payload", Type: "interface{}", JSONTag: "payload", IsPointer: false},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "DTO with slice of inline struct field",
+			fileName: "order.go",
+			fileContent: `package dto
+
+type OrderDTO struct {
+	Lines []struct {
+		Qty int    ` + "`json:\"qty\"`" + `
+		SKU string ` + "`json:\"sku\"`" + `
+	} ` + "`json:\"lines\"`" + `
+}`,
+			wantDTOs: map[string]dtoSchema{
+				"OrderDTO": {
+					Name: "OrderDTO",
+					Fields: []structField{
+						{
+							Name:    "Lines",
+							Type:    "[]struct",
+							JSONTag: "lines",
+							Fields: []structField{
+								{Name: "Qty", Type: "int", JSONTag: "qty"},
+								{Name: "SKU", Type: "string", JSONTag: "sku"},
+							},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:     "DTO with openapi tag",
+			fileName: "parcel.go",
+			fileContent: `package dto
+
+type ParcelDTO struct {
+	WeightGrams int ` + "`json:\"weightGrams\" openapi:\"unit=grams\"`" + `
+}`,
+			wantDTOs: map[string]dtoSchema{
+				"ParcelDTO": {
+					Name: "ParcelDTO",
+					Fields: []structField{
+						{Name: "WeightGrams", Type: "int", JSONTag: "weightGrams", OpenAPITag: "unit=grams"},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name:     "invalid Go file returns error",
 			fileName: "invalid.go",
@@ -298,10 +378,181 @@ type InvalidDTO struct {
 	}
 }
 
+func TestExtractDTOsFromFile_DocComment(t *testing.T) {
+	fileContent := `package dto
+
+// UserDTO is the public view of a registered account.
+type UserDTO struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}
+
+type (
+	// CreateUserDTO carries the fields accepted on sign-up.
+	CreateUserDTO struct {
+		Name string ` + "`json:\"name\"`" + `
+	}
+	UpdateUserDTO struct {
+		Name string ` + "`json:\"name\"`" + `
+	}
+)`
+	filePath := filepath.Join(t.TempDir(), "user.go")
+	if err := os.WriteFile(filePath, []byte(fileContent), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
+	}
+
+	got, err := extractDTOsFromFile(filePath)
+	if err != nil {
+		t.Fatalf("extractDTOsFromFile() error = %v", err)
+	}
+
+	tests := []struct {
+		dto  string
+		want string
+	}{
+		{dto: "UserDTO", want: "UserDTO is the public view of a registered account."},
+		{dto: "CreateUserDTO", want: "CreateUserDTO carries the fields accepted on sign-up."},
+		{dto: "UpdateUserDTO", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dto, func(t *testing.T) {
+			if got := got[tt.dto].Description; got != tt.want {
+				t.Errorf("%s Description = %q, want %q", tt.dto, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAstTypeName_Maps(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: "map[string]interface{}", want: "map[string]interface{}"},
+		{src: "map[string]int64", want: "map[string]int64"},
+		{src: "map[string]AddressDTO", want: "map[string]AddressDTO"},
+		{src: "map[string][]string", want: "map[string][]string"},
+		{src: "map[string]map[string]bool", want: "map[string]map[string]bool"},
+		{src: "map[string]*time.Time", want: "map[string]time.Time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := parser.ParseExpr(tt.src)
+			if err != nil {
+				t.Fatalf("ParseExpr() error = %v", err)
+			}
+			if got, _ := astTypeName(expr); got != tt.want {
+				t.Errorf("astTypeName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalNamedTypes(t *testing.T) {
+	src := `package dto
+
+type Email string
+type Status int
+type Tags []string
+type Stamp = time.Time
+type Set[T comparable] map[T]bool
+type Address struct{ City string }
+type Reader interface{ Read() }
+type Lines []struct {
+	SKU string ` + "`json:\"sku\"`" + `
+}
+`
+	node, err := parser.ParseFile(token.NewFileSet(), "types.go", src, 0)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+
+	want := map[string]namedType{
+		"Email":  {Underlying: "string"},
+		"Status": {Underlying: "int"},
+		"Tags":   {Underlying: "[]string"},
+		"Stamp":  {Underlying: "time.Time"},
+		"Lines":  {Underlying: "[]struct", Fields: []structField{{Name: "SKU", Type: "string", JSONTag: "sku"}}},
+	}
+	if got := localNamedTypes(node); !reflect.DeepEqual(got, want) {
+		t.Errorf("localNamedTypes() = %v, want %v", got, want)
+	}
+}
+
+func TestLocalConstEnums(t *testing.T) {
+	src := `package dto
+
+type Status string
+type Priority int
+type Flag uint
+
+const (
+	StatusActive   Status = "active"
+	StatusArchived Status = "archived"
+	defaultLimit          = 20
+)
+
+const StatusPending = Status("pending")
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityHigh
+	_
+	PriorityUrgent
+)
+
+const (
+	FlagRead Flag = 1 << iota
+	FlagWrite
+	FlagBroken Flag = 1 / 0
+)
+
+const Timeout time.Duration = 5
+`
+	node, err := parser.ParseFile(token.NewFileSet(), "enums.go", src, 0)
+	if err != nil {
+		t.Fatalf("ParseFile() error = %v", err)
+	}
+
+	want := map[string][]interface{}{
+		"Status":   {"active", "archived", "pending"},
+		"Priority": {int64(1), int64(2), int64(4)},
+		"Flag":     {int64(1), int64(2)},
+	}
+	if got := localConstEnums(node); !reflect.DeepEqual(got, want) {
+		t.Errorf("localConstEnums() = %v, want %v", got, want)
+	}
+}
+
 func TestExtractStructFieldsFromAST(t *testing.T) {
 	// This is tested indirectly through TestExtractDTOsFromFile
 	// We can add specific edge case tests here if needed
-	t.Run("embedded fields are skipped", func(t *testing.T) {
+	t.Run("description, deprecated and default tags", func(t *testing.T) {
+		expr, err := parser.ParseExpr("struct {\n\tNickname string `json:\"nickname\" description:\"Display name\" deprecated:\"true\" default:\"anonymous\"`\n}")
+		if err != nil {
+			t.Fatalf("ParseExpr() error = %v", err)
+		}
+
+		fields := extractStructFieldsFromAST(expr.(*ast.StructType))
+		if len(fields) != 1 || fields[0].DescriptionTag != "Display name" || !fields[0].Deprecated || fields[0].DefaultTag != "anonymous" {
+			t.Errorf("Expected a deprecated field described 'Display name' defaulting to 'anonymous', got %+v", fields)
+		}
+	})
+
+	t.Run("enum labels tag", func(t *testing.T) {
+		expr, err := parser.ParseExpr("struct {\n\tStatus string `json:\"status\" enum_labels:\"draft=Draft,live=Published\"`\n}")
+		if err != nil {
+			t.Fatalf("ParseExpr() error = %v", err)
+		}
+
+		fields := extractStructFieldsFromAST(expr.(*ast.StructType))
+		if len(fields) != 1 || fields[0].EnumLabelsTag != "draft=Draft,live=Published" {
+			t.Errorf("Expected the enum_labels tag to be read, got %+v", fields)
+		}
+	})
+
+	t.Run("embedded fields are resolved", func(t *testing.T) {
 		tempDir := t.TempDir()
 		fileContent := `package dto
 
@@ -329,13 +580,46 @@ type EmbeddedDTO struct {
 			t.Fatal("EmbeddedDTO not found")
 		}
 
-		// Should only have the Name field, Base is embedded (no field name)
-		if len(dto.Fields) != 1 {
-			t.Errorf("Expected 1 field (embedded fields skipped), got %d", len(dto.Fields))
+		// Base is kept as an embedded field carrying the members of its struct
+		if len(dto.Fields) != 2 {
+			t.Fatalf("Expected 2 fields, got %d", len(dto.Fields))
+		}
+		base := dto.Fields[0]
+		if !base.Embedded || base.Name != "Base" || base.Type != "Base" {
+			t.Errorf("Expected embedded Base field, got %+v", base)
+		}
+		if len(base.Fields) != 1 || base.Fields[0].Name != "ID" {
+			t.Errorf("Expected Base to resolve to its ID field, got %+v", base.Fields)
+		}
+		if dto.Fields[1].Name != "Name" || dto.Fields[1].Embedded {
+			t.Errorf("Expected regular field 'Name', got %+v", dto.Fields[1])
+		}
+	})
+
+	t.Run("embedded fields with a json name stay regular fields", func(t *testing.T) {
+		tempDir := t.TempDir()
+		fileContent := `package dto
+
+type Audit struct {
+	By string
+}
+
+type TaggedDTO struct {
+	*Audit ` + "`json:\"audit\"`" + `
+}`
+		filePath := filepath.Join(tempDir, "tagged.go")
+		if err := os.WriteFile(filePath, []byte(fileContent), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		got, err := extractDTOsFromFile(filePath)
+		if err != nil {
+			t.Fatalf("extractDTOsFromFile() error = %v", err)
 		}
 
-		if len(dto.Fields) > 0 && dto.Fields[0].Name != "Name" {
-			t.Errorf("Expected field name 'Name', got %q", dto.Fields[0].Name)
+		field := got["TaggedDTO"].Fields[0]
+		if field.Embedded || field.JSONTag != "audit" || !field.IsPointer {
+			t.Errorf("Expected a regular pointer field named audit, got %+v", field)
 		}
 	})
 }
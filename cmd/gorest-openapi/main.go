@@ -0,0 +1,70 @@
+// Command gorest-openapi prints the OpenAPI spec of a DTOs directory, with no
+// running server, for build-time generation and CI pipelines.
+//
+//	gorest-openapi -dtos ./dtos -routes routes.json -format yaml > openapi.yaml
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	openapi "github.com/nicolasbonnici/gorest-openapi"
+)
+
+func main() {
+	dtosDir := flag.String("dtos", "", "DTOs directory to document")
+	routesFile := flag.String("routes", "", "optional JSON route manifest: [{\"method\":\"GET\",\"path\":\"/health\"}]")
+	format := flag.String("format", "json", "output format: json or yaml")
+	specVersion := flag.String("spec-version", "3.0", "OpenAPI version: 3.0 or 3.1")
+	title := flag.String("title", "GoREST API", "API title")
+	version := flag.String("version", "1.0.0", "API version")
+	description := flag.String("description", "Auto-generated REST API with full CRUD operations", "API description")
+	serverURL := flag.String("server-url", "http://localhost:8000", "server URL")
+	paginationLimit := flag.Int("pagination-limit", 20, "default page size")
+	paginationMaxLimit := flag.Int("pagination-max-limit", 100, "maximum page size")
+	internal := flag.Bool("internal", false, "keep operations, schemas and properties flagged x-internal")
+	resolveImports := flag.Bool("resolve-imports", true, "document types the DTOs import from other packages (runs go list)")
+	flag.Parse()
+
+	if err := run(*dtosDir, *routesFile, *format, *specVersion, *internal, openapi.GeneratorConfig{
+		PaginationLimit:      *paginationLimit,
+		PaginationMaxLimit:   *paginationMaxLimit,
+		ServerURL:            *serverURL,
+		Title:                *title,
+		Version:              *version,
+		Description:          *description,
+		ResolveImportedTypes: *resolveImports,
+	}); err != nil {
+		fmt.Fprintln(os.Stderr, "gorest-openapi:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dtosDir, routesFile, format, specVersion string, internal bool, cfg openapi.GeneratorConfig) error {
+	cfg.DTOsDirectory = dtosDir
+
+	var routes []openapi.Route
+	if routesFile != "" {
+		var err error
+		if routes, err = openapi.LoadRouteManifest(routesFile); err != nil {
+			return err
+		}
+	}
+
+	doc, err := openapi.Generate(cfg, routes)
+	if err != nil {
+		return err
+	}
+	if !internal {
+		doc = openapi.PublicSpec(doc)
+	}
+
+	raw, err := openapi.Render(doc, format, specVersion)
+	if err != nil {
+		return err
+	}
+
+	_, err = os.Stdout.Write(raw)
+	return err
+}
@@ -0,0 +1,344 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/nicolasbonnici/gorest/plugin"
+)
+
+// configKind is the expected shape of an Initialize config value.
+type configKind struct {
+	name  string
+	check func(value interface{}) bool
+}
+
+var (
+	kindString = configKind{"a string", func(v interface{}) bool { _, ok := v.(string); return ok }}
+	kindInt    = configKind{"an integer", func(v interface{}) bool { _, ok := v.(int); return ok }}
+	kindBool   = configKind{"a boolean", func(v interface{}) bool { _, ok := v.(bool); return ok }}
+	kindMap    = configKind{"a map", func(v interface{}) bool { _, ok := v.(map[string]interface{}); return ok }}
+	kindList   = configKind{"a list", func(v interface{}) bool { _, ok := v.([]interface{}); return ok }}
+
+	kindStringList = configKind{"a list of strings", func(v interface{}) bool {
+		list, ok := v.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range list {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	}}
+	kindStringMap = configKind{"a map of strings", func(v interface{}) bool {
+		entries, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, value := range entries {
+			if _, ok := value.(string); !ok {
+				return false
+			}
+		}
+		return true
+	}}
+	kindBoolMap = configKind{"a map of booleans", func(v interface{}) bool {
+		entries, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, value := range entries {
+			if _, ok := value.(bool); !ok {
+				return false
+			}
+		}
+		return true
+	}}
+
+	kindPluginRegistry = configKind{"a *plugin.PluginRegistry", func(v interface{}) bool { _, ok := v.(*plugin.PluginRegistry); return ok }}
+	kindFS             = configKind{"an fs.FS", func(v interface{}) bool { _, ok := v.(fs.FS); return ok }}
+	kindUIRenderer     = configKind{"a UIRenderer", func(v interface{}) bool { _, ok := v.(UIRenderer); return ok }}
+	kindHandler        = configKind{"a fiber.Handler", func(v interface{}) bool { _, ok := v.(fiber.Handler); return ok }}
+)
+
+// configOption is a key of the Initialize config: the shape of its value and
+// how a value of that shape sets the options.
+type configOption struct {
+	kind configKind
+	set  func(o *Options, value interface{})
+}
+
+func stringOption(field func(*Options) *string) configOption {
+	return configOption{kindString, func(o *Options, v interface{}) { *field(o) = v.(string) }}
+}
+
+func intOption(field func(*Options) *int) configOption {
+	return configOption{kindInt, func(o *Options, v interface{}) { *field(o) = v.(int) }}
+}
+
+func boolOption(field func(*Options) *bool) configOption {
+	return configOption{kindBool, func(o *Options, v interface{}) { *field(o) = v.(bool) }}
+}
+
+func mapOption(field func(*Options) *map[string]interface{}) configOption {
+	return configOption{kindMap, func(o *Options, v interface{}) { *field(o) = v.(map[string]interface{}) }}
+}
+
+func stringListOption(field func(*Options) *[]string) configOption {
+	return configOption{kindStringList, func(o *Options, v interface{}) { *field(o) = stringList(v.([]interface{})) }}
+}
+
+func stringMapOption(field func(*Options) *map[string]string) configOption {
+	return configOption{kindStringMap, func(o *Options, v interface{}) { *field(o) = stringMap(v.(map[string]interface{})) }}
+}
+
+func boolMapOption(field func(*Options) *map[string]bool) configOption {
+	return configOption{kindBoolMap, func(o *Options, v interface{}) {
+		entries := v.(map[string]interface{})
+		values := make(map[string]bool, len(entries))
+		for key, value := range entries {
+			values[key] = value.(bool)
+		}
+		*field(o) = values
+	}}
+}
+
+func stringListMapOption(field func(*Options) *map[string][]string) configOption {
+	return configOption{kindMap, func(o *Options, v interface{}) { *field(o) = stringListMap(v.(map[string]interface{})) }}
+}
+
+// configKeys lists every key Initialize reads, with the shape it expects and
+// the option it sets.
+var configKeys = map[string]configOption{
+	"dtos_directory":           stringOption(func(o *Options) *string { return &o.DTOsDirectory }),
+	"pagination_limit":         intOption(func(o *Options) *int { return &o.PaginationLimit }),
+	"pagination_max_limit":     intOption(func(o *Options) *int { return &o.PaginationMaxLimit }),
+	"offset_max":               intOption(func(o *Options) *int { return &o.OffsetMax }),
+	"plugin_registry":          {kindPluginRegistry, func(o *Options, v interface{}) { o.PluginRegistry = v.(*plugin.PluginRegistry) }},
+	"ui_renderer":              {kindUIRenderer, func(o *Options, v interface{}) { o.UIRenderer = v.(UIRenderer) }},
+	"docs_guard":               {kindHandler, func(o *Options, v interface{}) { o.DocsGuard = v.(fiber.Handler) }},
+	"title":                    stringOption(func(o *Options) *string { return &o.Title }),
+	"version":                  stringOption(func(o *Options) *string { return &o.Version }),
+	"description":              stringOption(func(o *Options) *string { return &o.Description }),
+	"hide_on_production":       boolOption(func(o *Options) *bool { return &o.HideOnProduction }),
+	"environment":              stringOption(func(o *Options) *string { return &o.Environment }),
+	"health_schema":            mapOption(func(o *Options) *map[string]interface{} { return &o.HealthSchema }),
+	"main_dto":                 stringMapOption(func(o *Options) *map[string]string { return &o.MainDTO }),
+	"count_head":               boolOption(func(o *Options) *bool { return &o.CountHead }),
+	"item_head":                boolOption(func(o *Options) *bool { return &o.ItemHead }),
+	"hide_fields":              stringListOption(func(o *Options) *[]string { return &o.HideFields }),
+	"response_content_types":   stringListOption(func(o *Options) *[]string { return &o.ResponseContentTypes }),
+	"accept_header":            boolOption(func(o *Options) *bool { return &o.AcceptHeader }),
+	"load_examples":            boolOption(func(o *Options) *bool { return &o.LoadExamples }),
+	"request_body_description": stringOption(func(o *Options) *string { return &o.RequestBodyDescription }),
+	"collection_format":        stringOption(func(o *Options) *string { return &o.CollectionFormat }),
+	"conditional_get":          boolOption(func(o *Options) *bool { return &o.ConditionalGet }),
+	"shared_examples":          boolOption(func(o *Options) *bool { return &o.SharedExamples }),
+	"id_param_description":     stringOption(func(o *Options) *string { return &o.IDParamDescription }),
+	"index_page":               boolOption(func(o *Options) *bool { return &o.IndexPage }),
+	"booleans_optional":        boolOption(func(o *Options) *bool { return &o.BooleansOptional }),
+	"max_request_body_size":    intOption(func(o *Options) *int { return &o.MaxRequestBodySize }),
+	"batch_create":             stringListOption(func(o *Options) *[]string { return &o.BatchCreate }),
+	"dtos_base_directory":      stringOption(func(o *Options) *string { return &o.DTOsBaseDirectory }),
+	"unauthorized_response":    boolOption(func(o *Options) *bool { return &o.UnauthorizedResponse }),
+	"error_schema":             mapOption(func(o *Options) *map[string]interface{} { return &o.ErrorSchema }),
+	"tag_descriptions":         stringMapOption(func(o *Options) *map[string]string { return &o.TagDescriptions }),
+	"idempotent_overrides":     boolMapOption(func(o *Options) *map[string]bool { return &o.IdempotentOverrides }),
+	"interface_schema":         stringOption(func(o *Options) *string { return &o.InterfaceSchema }),
+	"recursive_dtos":           boolOption(func(o *Options) *bool { return &o.RecursiveDTOs }),
+	"directory_tags":           boolOption(func(o *Options) *bool { return &o.DirectoryTags }),
+	"security":                 stringListOption(func(o *Options) *[]string { return &o.Security }),
+	"api_key_header":           stringOption(func(o *Options) *string { return &o.APIKeyHeader }),
+	"operation_security":       stringListMapOption(func(o *Options) *map[string][]string { return &o.OperationSecurity }),
+	"security_scopes":          stringListMapOption(func(o *Options) *map[string][]string { return &o.SecurityScopes }),
+	"operation_scopes":         stringListMapOption(func(o *Options) *map[string][]string { return &o.OperationScopes }),
+	"synthesize_examples":      boolOption(func(o *Options) *bool { return &o.SynthesizeExamples }),
+	"dto_variants":             stringMapOption(func(o *Options) *map[string]string { return &o.DTOVariants }),
+	"output_file":              stringOption(func(o *Options) *string { return &o.OutputFile }),
+	"output_server_url":        stringOption(func(o *Options) *string { return &o.OutputServerURL }),
+	"docs_path":                stringOption(func(o *Options) *string { return &o.DocsPath }),
+	"spec_path":                stringOption(func(o *Options) *string { return &o.SpecPath }),
+	"docs_basic_auth": {kindStringMap, func(o *Options, v interface{}) {
+		auth := v.(map[string]interface{})
+		o.DocsBasicAuth.Username, _ = auth["username"].(string)
+		o.DocsBasicAuth.Password, _ = auth["password"].(string)
+	}},
+	"docs_token":             stringOption(func(o *Options) *string { return &o.DocsToken }),
+	"enabled":                {kindBool, func(o *Options, v interface{}) { o.Disabled = !v.(bool) }},
+	"environments":           stringListOption(func(o *Options) *[]string { return &o.Environments }),
+	"json_only_environments": stringListOption(func(o *Options) *[]string { return &o.JSONOnlyEnvironments }),
+	"internal_operations":    stringListOption(func(o *Options) *[]string { return &o.InternalOperations }),
+	"internal_schemas":       stringListOption(func(o *Options) *[]string { return &o.InternalSchemas }),
+	"internal_spec_path":     stringOption(func(o *Options) *string { return &o.InternalSpecPath }),
+	"internal_docs_token":    stringOption(func(o *Options) *string { return &o.InternalDocsToken }),
+	"contact":                stringMapOption(func(o *Options) *map[string]string { return &o.Contact }),
+	"license":                stringMapOption(func(o *Options) *map[string]string { return &o.License }),
+	"terms_of_service":       stringOption(func(o *Options) *string { return &o.TermsOfService }),
+	"servers": {kindList, func(o *Options, v interface{}) {
+		o.Servers = nil
+		for _, entry := range v.([]interface{}) {
+			if server, ok := entry.(map[string]interface{}); ok {
+				o.Servers = append(o.Servers, parseServer(server))
+			}
+		}
+	}},
+	"base_path": stringOption(func(o *Options) *string { return &o.BasePath }),
+	"tag_external_docs": {kindMap, func(o *Options, v interface{}) {
+		externalDocs := v.(map[string]interface{})
+		o.TagExternalDocs = make(map[string]ExternalDocs, len(externalDocs))
+		for tag, value := range externalDocs {
+			if docs, ok := value.(map[string]interface{}); ok {
+				url, _ := docs["url"].(string)
+				description, _ := docs["description"].(string)
+				o.TagExternalDocs[tag] = ExternalDocs{URL: url, Description: description}
+			}
+		}
+	}},
+	"tag_order": stringListOption(func(o *Options) *[]string { return &o.TagOrder }),
+	"extensions": {kindMap, func(o *Options, v interface{}) {
+		extensions := v.(map[string]interface{})
+		o.Extensions.Root, _ = extensions["root"].(map[string]interface{})
+		o.Extensions.Info, _ = extensions["info"].(map[string]interface{})
+		o.Extensions.Paths = extensionsByKey(extensions["paths"])
+		o.Extensions.Operations = extensionsByKey(extensions["operations"])
+	}},
+	"security_schemes": {kindMap, func(o *Options, v interface{}) {
+		schemes := v.(map[string]interface{})
+		o.SecuritySchemes = make(map[string]SecurityScheme, len(schemes))
+		for name, value := range schemes {
+			if raw, ok := value.(map[string]interface{}); ok {
+				o.SecuritySchemes[name] = parseSecurityScheme(raw)
+			}
+		}
+	}},
+	"no_global_security":    boolOption(func(o *Options) *bool { return &o.NoGlobalSecurity }),
+	"server_url":            stringOption(func(o *Options) *string { return &o.ServerURL }),
+	"operation_id_strategy": stringOption(func(o *Options) *string { return &o.OperationIDStrategy }),
+	"operation_texts": {kindMap, func(o *Options, v interface{}) {
+		texts := v.(map[string]interface{})
+		o.OperationTexts = make(map[string]OperationText, len(texts))
+		for operation, value := range texts {
+			if templates, ok := value.(map[string]interface{}); ok {
+				text := OperationText{}
+				text.Summary, _ = templates["summary"].(string)
+				text.Description, _ = templates["description"].(string)
+				o.OperationTexts[operation] = text
+			}
+		}
+	}},
+	"locale":  stringOption(func(o *Options) *string { return &o.Locale }),
+	"locales": stringListOption(func(o *Options) *[]string { return &o.Locales }),
+	"translations": {kindMap, func(o *Options, v interface{}) {
+		translations := v.(map[string]interface{})
+		o.Translations = make(map[string]map[string]string, len(translations))
+		for locale, value := range translations {
+			if messages, ok := value.(map[string]interface{}); ok {
+				o.Translations[locale] = stringMap(messages)
+			}
+		}
+	}},
+	"plural_overrides":       stringMapOption(func(o *Options) *map[string]string { return &o.PluralOverrides }),
+	"path_style":             stringOption(func(o *Options) *string { return &o.PathStyle }),
+	"singular_paths":         boolOption(func(o *Options) *bool { return &o.SingularPaths }),
+	"resource_paths":         stringMapOption(func(o *Options) *map[string]string { return &o.ResourcePaths }),
+	"embedded_structs":       stringOption(func(o *Options) *string { return &o.EmbeddedStructs }),
+	"type_formats":           stringMapOption(func(o *Options) *map[string]string { return &o.TypeFormats }),
+	"deprecated_operations":  stringListOption(func(o *Options) *[]string { return &o.DeprecatedOperations }),
+	"dtos_fs":                {kindFS, func(o *Options, v interface{}) { o.DTOsFS = v.(fs.FS) }},
+	"xml_content":            boolOption(func(o *Options) *bool { return &o.XMLContent }),
+	"main_dto_patterns":      stringListOption(func(o *Options) *[]string { return &o.MainDTOPatterns }),
+	"resource_names":         stringMapOption(func(o *Options) *map[string]string { return &o.ResourceNames }),
+	"watch_dtos":             boolOption(func(o *Options) *bool { return &o.WatchDTOs }),
+	"group_tags":             stringMapOption(func(o *Options) *map[string]string { return &o.GroupTags }),
+	"include_routes":         stringListOption(func(o *Options) *[]string { return &o.IncludeRoutes }),
+	"exclude_routes":         stringListOption(func(o *Options) *[]string { return &o.ExcludeRoutes }),
+	"discover_methods":       boolMapOption(func(o *Options) *map[string]bool { return &o.DiscoverMethods }),
+	"resolve_imported_types": boolOption(func(o *Options) *bool { return &o.ResolveImportedTypes }),
+}
+
+// hostConfigKeys are injected by gorest into every plugin config and are not
+// options of this plugin.
+var hostConfigKeys = map[string]bool{
+	"database":                   true,
+	"config":                     true,
+	"server_scheme":              true,
+	"server_host":                true,
+	"server_port":                true,
+	plugin.ConfigKeyVersion:      true,
+	plugin.ConfigKeyDependencies: true,
+}
+
+// validateConfig reports every unknown key and every value of the wrong
+// shape in cfg, in key order. Nil values count as unset.
+func validateConfig(cfg map[string]interface{}) error {
+	var errs []error
+	for _, key := range sortedKeys(cfg) {
+		value := cfg[key]
+		if value == nil || hostConfigKeys[key] {
+			continue
+		}
+
+		option, ok := configKeys[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown config key %q", key))
+			continue
+		}
+		if !option.kind.check(value) {
+			errs = append(errs, fmt.Errorf("config key %q must be %s, got %T", key, option.kind.name, value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// decodeConfig sets the options of the keys of cfg holding a value of the
+// expected shape; validateConfig reports the others.
+func (o *Options) decodeConfig(cfg map[string]interface{}) {
+	for _, key := range sortedKeys(cfg) {
+		option, ok := configKeys[key]
+		if ok && cfg[key] != nil && option.kind.check(cfg[key]) {
+			option.set(o, cfg[key])
+		}
+	}
+}
+
+// stringList reads a list of strings, skipping other items.
+func stringList(items []interface{}) []string {
+	var list []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// stringMap reads a map of strings, skipping other values.
+func stringMap(entries map[string]interface{}) map[string]string {
+	values := make(map[string]string, len(entries))
+	for key, value := range entries {
+		if s, ok := value.(string); ok {
+			values[key] = s
+		}
+	}
+	return values
+}
+
+// stringListMap reads a map of string lists, e.g. the security schemes per
+// "METHOD /path" operation key. An entry that is not a list is read as empty.
+func stringListMap(raw map[string]interface{}) map[string][]string {
+	lists := make(map[string][]string, len(raw))
+	for key, value := range raw {
+		items, _ := value.([]interface{})
+		lists[key] = []string{}
+		for _, item := range items {
+			if s, ok := item.(string); ok {
+				lists[key] = append(lists[key], s)
+			}
+		}
+	}
+	return lists
+}
@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/nicolasbonnici/gorest/plugin"
+)
+
+// TestConfigKeys_Decode checks that every config key sets an option.
+func TestConfigKeys_Decode(t *testing.T) {
+	samples := map[string][]interface{}{
+		kindString.name:         {"value"},
+		kindInt.name:            {7},
+		kindBool.name:           {true, false},
+		kindMap.name:            {map[string]interface{}{"root": map[string]interface{}{"x-value": "value"}}},
+		kindList.name:           {[]interface{}{map[string]interface{}{"url": "https://api.example.com"}}},
+		kindStringList.name:     {[]interface{}{"value"}},
+		kindStringMap.name:      {map[string]interface{}{"username": "value"}},
+		kindBoolMap.name:        {map[string]interface{}{"value": true}},
+		kindPluginRegistry.name: {&plugin.PluginRegistry{}},
+		kindFS.name:             {fstest.MapFS{}},
+		kindUIRenderer.name:     {stubUIRenderer{}},
+		kindHandler.name:        {fiber.Handler(func(c fiber.Ctx) error { return nil })},
+	}
+
+	for _, key := range sortedKeys(configKeys) {
+		option := configKeys[key]
+		values, ok := samples[option.kind.name]
+		if !ok {
+			t.Errorf("no sample value for %q (%s)", key, option.kind.name)
+			continue
+		}
+
+		set := false
+		for _, value := range values {
+			if !option.kind.check(value) {
+				t.Fatalf("sample %v of %q is not %s", value, key, option.kind.name)
+			}
+			opts := DefaultOptions()
+			opts.decodeConfig(map[string]interface{}{key: value})
+			set = set || !reflect.DeepEqual(opts, DefaultOptions())
+		}
+		if !set {
+			t.Errorf("config key %q sets no option", key)
+		}
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     map[string]interface{}
+		wantErr string
+	}{
+		{name: "empty", cfg: map[string]interface{}{}},
+		{name: "nil value", cfg: map[string]interface{}{"title": nil}},
+		{name: "host key", cfg: map[string]interface{}{"__dependencies": map[string]interface{}{}}},
+		{name: "bool map", cfg: map[string]interface{}{"idempotent_overrides": map[string]interface{}{"POST /users": "yes"}}, wantErr: `config key "idempotent_overrides" must be a map of booleans, got map[string]interface {}`},
+		{name: "list", cfg: map[string]interface{}{"servers": map[string]interface{}{}}, wantErr: `config key "servers" must be a list, got map[string]interface {}`},
+		{name: "unknown", cfg: map[string]interface{}{"dto_directory": "./dtos"}, wantErr: `unknown config key "dto_directory"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateConfig(tt.cfg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateConfig() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("validateConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package openapi
+
+// xmlMediaType is the media type offered alongside JSON with XMLContent.
+const xmlMediaType = "application/xml"
+
+// applyContentNegotiation offers every JSON response body under the configured
+// extra media types, and request bodies as well as responses as XML with
+// XMLContent. Operations that end up with more than one response media
+// type document a Vary: Accept response header and, when enabled, the Accept
+// request header.
+func applyContentNegotiation(paths map[string]interface{}, cfg GeneratorConfig) {
+	if len(cfg.ResponseContentTypes) == 0 && !cfg.AcceptHeader && !cfg.XMLContent {
+		return
+	}
+
+	forEachOperation(paths, func(_, _ string, op map[string]interface{}) {
+		for _, mediaType := range cfg.ResponseContentTypes {
+			offerResponseMediaType(op, mediaType)
+		}
+		if cfg.XMLContent {
+			offerResponseMediaType(op, xmlMediaType)
+			offerRequestMediaType(op, xmlMediaType)
+		}
+
+		mediaTypes := responseMediaTypes(op)
+		if len(mediaTypes) < 2 {
+			return
+		}
+
+		addVaryHeader(op)
+
+		if !cfg.AcceptHeader {
+			return
+		}
+
+		params, _ := op["parameters"].([]map[string]interface{})
+		op["parameters"] = append(params, map[string]interface{}{
+			"name":        "Accept",
+			"in":          "header",
+			"description": "Preferred response media type",
+			"schema": map[string]interface{}{
+				"type":    "string",
+				"enum":    mediaTypes,
+				"default": mediaTypes[0],
+			},
+		})
+	})
+}
+
+// addVaryHeader documents Vary: Accept on the responses carrying a body, since
+// their representation depends on the negotiated media type.
+func addVaryHeader(op map[string]interface{}) {
+	responses, _ := op["responses"].(map[string]interface{})
+	for _, response := range responses {
+		r, _ := response.(map[string]interface{})
+		if _, ok := r["content"].(map[string]interface{}); !ok {
+			continue
+		}
+		headers, _ := r["headers"].(map[string]interface{})
+		if headers == nil {
+			headers = make(map[string]interface{})
+			r["headers"] = headers
+		}
+		headers["Vary"] = map[string]interface{}{
+			"description": "The response representation depends on the Accept request header",
+			"schema":      map[string]interface{}{"type": "string", "example": "Accept"},
+		}
+	}
+}
+
+// offerRequestMediaType accepts the JSON request body of op under mediaType
+// as well.
+func offerRequestMediaType(op map[string]interface{}, mediaType string) {
+	body, _ := op["requestBody"].(map[string]interface{})
+	content, ok := body["content"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if jsonBody, ok := content["application/json"]; ok {
+		if _, exists := content[mediaType]; !exists {
+			content[mediaType] = jsonBody
+		}
+	}
+}
+
+func offerResponseMediaType(op map[string]interface{}, mediaType string) {
+	responses, _ := op["responses"].(map[string]interface{})
+	for _, response := range responses {
+		r, _ := response.(map[string]interface{})
+		content, ok := r["content"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if jsonBody, ok := content["application/json"]; ok {
+			if _, exists := content[mediaType]; !exists {
+				content[mediaType] = jsonBody
+			}
+		}
+	}
+}
+
+// responseMediaTypes lists the distinct media types an operation can respond
+// with, application/json first and the rest in sorted order.
+func responseMediaTypes(op map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	responses, _ := op["responses"].(map[string]interface{})
+	for _, response := range responses {
+		r, _ := response.(map[string]interface{})
+		content, ok := r["content"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for mediaType := range content {
+			seen[mediaType] = true
+		}
+	}
+
+	var mediaTypes []string
+	if seen["application/json"] {
+		mediaTypes = append(mediaTypes, "application/json")
+		delete(seen, "application/json")
+	}
+	return append(mediaTypes, sortedKeys(seen)...)
+}
@@ -0,0 +1,159 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyContentNegotiation(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+
+	tests := []struct {
+		name       string
+		cfg        GeneratorConfig
+		wantAccept []string
+	}{
+		{
+			name:       "single media type has no Accept header",
+			cfg:        GeneratorConfig{AcceptHeader: true},
+			wantAccept: nil,
+		},
+		{
+			name: "multiple media types document Accept",
+			cfg: GeneratorConfig{
+				AcceptHeader:         true,
+				ResponseContentTypes: []string{"application/ld+json"},
+			},
+			wantAccept: []string{"application/json", "application/ld+json"},
+		},
+		{
+			name: "extra media types without the flag add no header",
+			cfg: GeneratorConfig{
+				ResponseContentTypes: []string{"application/ld+json"},
+			},
+			wantAccept: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := map[string]interface{}{
+				"/users": buildCollectionEndpoints(resource, "User", tt.cfg),
+			}
+
+			applyContentNegotiation(paths, tt.cfg)
+
+			list := paths["/users"].(map[string]interface{})["get"].(map[string]interface{})
+			got := acceptHeaderEnum(list)
+			if !reflect.DeepEqual(got, tt.wantAccept) {
+				t.Errorf("Accept enum = %v, want %v", got, tt.wantAccept)
+			}
+		})
+	}
+}
+
+func TestApplyContentNegotiation_OnlyOnMultiContentOperations(t *testing.T) {
+	cfg := GeneratorConfig{
+		AcceptHeader:         true,
+		ResponseContentTypes: []string{"application/xml"},
+	}
+	paths := map[string]interface{}{
+		"/users/{id}": buildItemEndpoints(resourceDTOs{Name: "user", PluralName: "users"}, "User", cfg),
+	}
+
+	applyContentNegotiation(paths, cfg)
+
+	item := paths["/users/{id}"].(map[string]interface{})
+	if got := acceptHeaderEnum(item["get"].(map[string]interface{})); len(got) != 2 {
+		t.Errorf("GET Accept enum = %v, want 2 media types", got)
+	}
+	if got := acceptHeaderEnum(item["delete"].(map[string]interface{})); got != nil {
+		t.Errorf("DELETE has no response body, Accept enum = %v, want none", got)
+	}
+}
+
+func TestApplyContentNegotiation_VaryHeader(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+
+	tests := []struct {
+		name     string
+		cfg      GeneratorConfig
+		wantVary bool
+	}{
+		{
+			name:     "single media type has no Vary header",
+			cfg:      GeneratorConfig{AcceptHeader: true},
+			wantVary: false,
+		},
+		{
+			name:     "multiple media types document Vary",
+			cfg:      GeneratorConfig{ResponseContentTypes: []string{"application/xml"}},
+			wantVary: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := map[string]interface{}{
+				"/users/{id}": buildItemEndpoints(resource, "User", tt.cfg),
+			}
+
+			applyContentNegotiation(paths, tt.cfg)
+
+			item := paths["/users/{id}"].(map[string]interface{})
+			get := item["get"].(map[string]interface{})["responses"].(map[string]interface{})
+			if got := hasResponseHeader(get["200"], "Vary"); got != tt.wantVary {
+				t.Errorf("GET 200 Vary present = %v, want %v", got, tt.wantVary)
+			}
+			if hasResponseHeader(get["404"], "Vary") {
+				t.Error("GET 404 has no body and should not document Vary")
+			}
+
+			del := item["delete"].(map[string]interface{})["responses"].(map[string]interface{})
+			if hasResponseHeader(del["204"], "Vary") {
+				t.Error("DELETE 204 has no body and should not document Vary")
+			}
+		})
+	}
+}
+
+func hasResponseHeader(response interface{}, name string) bool {
+	headers, _ := response.(map[string]interface{})["headers"].(map[string]interface{})
+	_, ok := headers[name]
+	return ok
+}
+
+func acceptHeaderEnum(op map[string]interface{}) []string {
+	params, _ := op["parameters"].([]map[string]interface{})
+	for _, param := range params {
+		if param["name"] == "Accept" && param["in"] == "header" {
+			return param["schema"].(map[string]interface{})["enum"].([]string)
+		}
+	}
+	return nil
+}
+
+func TestApplyContentNegotiation_XMLContent(t *testing.T) {
+	cfg := GeneratorConfig{XMLContent: true}
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+	paths := map[string]interface{}{
+		"/users": buildCollectionEndpoints(resource, "User", cfg),
+	}
+
+	applyContentNegotiation(paths, cfg)
+
+	create := paths["/users"].(map[string]interface{})["post"].(map[string]interface{})
+	requestContent := create["requestBody"].(map[string]interface{})["content"].(map[string]interface{})
+	if !reflect.DeepEqual(requestContent[xmlMediaType], requestContent["application/json"]) {
+		t.Errorf("request content = %v, want the JSON body offered as %s", requestContent, xmlMediaType)
+	}
+	if got := responseMediaTypes(create); !reflect.DeepEqual(got, []string{"application/json", xmlMediaType}) {
+		t.Errorf("response media types = %v, want JSON and XML", got)
+	}
+
+	list := paths["/users"].(map[string]interface{})["get"].(map[string]interface{})
+	headers := list["responses"].(map[string]interface{})["200"].(map[string]interface{})["headers"].(map[string]interface{})
+	if _, ok := headers["Vary"]; !ok {
+		t.Error("XML responses must document Vary: Accept")
+	}
+}
@@ -0,0 +1,78 @@
+package openapi
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// docsRealm is the realm of the basic auth challenge protecting the docs.
+const docsRealm = "API documentation"
+
+// tokenGuard only lets requests sending "Authorization: Bearer <token>"
+// through.
+func tokenGuard(token string) fiber.Handler {
+	return credentialsGuard(BasicAuth{}, token)
+}
+
+// credentialsGuard lets requests through when their Authorization header
+// carries either the basic auth credentials or the bearer token, whichever
+// are configured. Both travel in the same header, so requiring both would
+// lock every client out. Other requests are challenged with a 401 offering
+// each configured scheme.
+func credentialsGuard(basic BasicAuth, token string) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		header := c.Get(fiber.HeaderAuthorization)
+		if basic.Username != "" {
+			user, pass, ok := parseBasicAuth(header)
+			if ok && secureEqual(user, basic.Username) && secureEqual(pass, basic.Password) {
+				return c.Next()
+			}
+		}
+		if token != "" {
+			scheme, credentials, _ := strings.Cut(header, " ")
+			if strings.EqualFold(scheme, "Bearer") && secureEqual(strings.TrimSpace(credentials), token) {
+				return c.Next()
+			}
+		}
+
+		var challenges []string
+		if basic.Username != "" {
+			challenges = append(challenges, `Basic realm="`+docsRealm+`"`)
+		}
+		if token != "" {
+			challenges = append(challenges, `Bearer realm="`+docsRealm+`"`)
+		}
+		c.Set(fiber.HeaderWWWAuthenticate, strings.Join(challenges, ", "))
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+}
+
+func parseBasicAuth(header string) (username, password string, ok bool) {
+	scheme, encoded, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Basic") {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decoded), ":")
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// getGuarded registers a GET route running every guard before handler.
+func getGuarded(router fiber.Router, path string, guards []fiber.Handler, handler fiber.Handler) {
+	chain := make([]any, 0, len(guards)+1)
+	for _, guard := range guards {
+		chain = append(chain, guard)
+	}
+	chain = append(chain, handler)
+	router.Get(path, chain[0], chain[1:]...)
+}
@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func basicAuthHeader(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+func TestCredentialsGuard(t *testing.T) {
+	basic := BasicAuth{Username: "docs", Password: "secret"}
+	basicChallenge := `Basic realm="API documentation"`
+	bothChallenges := `Basic realm="API documentation", Bearer realm="API documentation"`
+
+	tests := []struct {
+		name          string
+		basic         BasicAuth
+		token         string
+		authorization string
+		wantStatus    int
+		wantChallenge string
+	}{
+		{name: "basic auth: valid credentials", basic: basic, authorization: basicAuthHeader("docs", "secret"), wantStatus: 200},
+		{name: "basic auth: wrong password", basic: basic, authorization: basicAuthHeader("docs", "nope"), wantStatus: 401, wantChallenge: basicChallenge},
+		{name: "basic auth: wrong user", basic: basic, authorization: basicAuthHeader("admin", "secret"), wantStatus: 401, wantChallenge: basicChallenge},
+		{name: "basic auth: missing header", basic: basic, wantStatus: 401, wantChallenge: basicChallenge},
+		{name: "basic auth: bearer scheme", basic: basic, authorization: "Bearer secret", wantStatus: 401, wantChallenge: basicChallenge},
+		{name: "basic auth: malformed base64", basic: basic, authorization: "Basic !!!", wantStatus: 401, wantChallenge: basicChallenge},
+		{name: "both: basic credentials", basic: basic, token: "s3cr3t", authorization: basicAuthHeader("docs", "secret"), wantStatus: 200},
+		{name: "both: bearer token", basic: basic, token: "s3cr3t", authorization: "Bearer s3cr3t", wantStatus: 200},
+		{name: "both: wrong password", basic: basic, token: "s3cr3t", authorization: basicAuthHeader("docs", "nope"), wantStatus: 401, wantChallenge: bothChallenges},
+		{name: "both: wrong token", basic: basic, token: "s3cr3t", authorization: "Bearer other", wantStatus: 401, wantChallenge: bothChallenges},
+		{name: "both: missing header", basic: basic, token: "s3cr3t", wantStatus: 401, wantChallenge: bothChallenges},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/docs", credentialsGuard(tt.basic, tt.token), func(c fiber.Ctx) error {
+				return c.SendString("ok")
+			})
+
+			req := httptest.NewRequest("GET", "/docs", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Test request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status code = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+			if got := resp.Header.Get("WWW-Authenticate"); got != tt.wantChallenge {
+				t.Errorf("WWW-Authenticate = %q, want %q", got, tt.wantChallenge)
+			}
+		})
+	}
+}
+
+func TestTokenGuard(t *testing.T) {
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "valid token", authorization: "Bearer s3cr3t", wantStatus: 200},
+		{name: "scheme is case-insensitive", authorization: "bearer s3cr3t", wantStatus: 200},
+		{name: "wrong token", authorization: "Bearer other", wantStatus: 401},
+		{name: "missing header", authorization: "", wantStatus: 401},
+		{name: "token without scheme", authorization: "s3cr3t", wantStatus: 401},
+	}
+
+	app := fiber.New()
+	app.Get("/docs", tokenGuard("s3cr3t"), func(c fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/docs", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Test request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status code = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
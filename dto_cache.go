@@ -0,0 +1,68 @@
+package openapi
+
+import (
+	"io/fs"
+	"slices"
+	"sync"
+	"time"
+)
+
+// dtoFileCache memoises the DTOs parsed from each file on disk, so repeated
+// spec generations only re-parse the files whose modification time or size
+// changed since. Callers get their own copy, free to resolve embedded fields.
+type dtoFileCache struct {
+	mu      sync.Mutex
+	entries map[string]dtoFileEntry
+}
+
+type dtoFileEntry struct {
+	modTime time.Time
+	size    int64
+	dtos    map[string]dtoSchema
+}
+
+// parsedDTOFiles is the cache shared by every spec generation.
+var parsedDTOFiles = &dtoFileCache{entries: make(map[string]dtoFileEntry)}
+
+// extract returns the DTOs of filename, whose content is src and stat info,
+// parsing it unless the cached entry matches its modification time and size.
+func (c *dtoFileCache) extract(filename string, info fs.FileInfo, src []byte) (map[string]dtoSchema, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[filename]
+	c.mu.Unlock()
+	if ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return cloneDTOs(entry.dtos), nil
+	}
+
+	dtos, err := extractDTOs(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[filename] = dtoFileEntry{modTime: info.ModTime(), size: info.Size(), dtos: dtos}
+	c.mu.Unlock()
+	return cloneDTOs(dtos), nil
+}
+
+// cloneDTOs deep-copies parsed DTOs, nested fields included.
+func cloneDTOs(dtos map[string]dtoSchema) map[string]dtoSchema {
+	cloned := make(map[string]dtoSchema, len(dtos))
+	for name, dto := range dtos {
+		dto.Fields = cloneFields(dto.Fields)
+		cloned[name] = dto
+	}
+	return cloned
+}
+
+func cloneFields(fields []structField) []structField {
+	if fields == nil {
+		return nil
+	}
+	cloned := slices.Clone(fields)
+	for i := range cloned {
+		cloned[i].Fields = cloneFields(cloned[i].Fields)
+		cloned[i].UnionTypes = slices.Clone(cloned[i].UnionTypes)
+	}
+	return cloned
+}
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDTOFileCache_Extract(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "user.go")
+	modTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	write := func(content string, modTime time.Time) ([]byte, os.FileInfo) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write user.go: %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set user.go times: %v", err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Failed to stat user.go: %v", err)
+		}
+		return []byte(content), info
+	}
+	cache := &dtoFileCache{entries: make(map[string]dtoFileEntry)}
+
+	tests := []struct {
+		name    string
+		content string
+		modTime time.Time
+		want    string
+	}{
+		{name: "parsed on first use", content: "package dto\n\ntype UserDTO struct {\n\tName string `json:\"name\"`\n}\n", modTime: modTime, want: "name"},
+		{name: "unchanged stat served from cache", content: "package dto\n\ntype UserDTO struct {\n\tMail string `json:\"mail\"`\n}\n", modTime: modTime, want: "name"},
+		{name: "new modification time re-parsed", content: "package dto\n\ntype UserDTO struct {\n\tMail string `json:\"mail\"`\n}\n", modTime: modTime.Add(time.Second), want: "mail"},
+		{name: "new size re-parsed", content: "package dto\n\ntype UserDTO struct {\n\tEmail string `json:\"email\"`\n}\n", modTime: modTime.Add(time.Second), want: "email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, info := write(tt.content, tt.modTime)
+			dtos, err := cache.extract(path, info, src)
+			if err != nil {
+				t.Fatalf("extract() error = %v", err)
+			}
+			fields := dtos["UserDTO"].Fields
+			if len(fields) != 1 || fields[0].JSONTag != tt.want {
+				t.Fatalf("extract() UserDTO fields = %+v, want %s", fields, tt.want)
+			}
+			// Callers own their copy
+			fields[0].Fields = []structField{{Name: "Leak"}}
+		})
+	}
+
+	if cached := cache.entries[path].dtos["UserDTO"].Fields[0]; cached.Fields != nil {
+		t.Errorf("cached field = %+v, want it untouched by callers", cached)
+	}
+}
@@ -1,45 +1,329 @@
 package openapi
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
-func loadResourceDTOs(dtosDir string) (map[string]resourceDTOs, error) {
-	if _, err := os.Stat(dtosDir); os.IsNotExist(err) {
-		return nil, fmt.Errorf("DTOs directory not found: %s", dtosDir)
+// resolveDTOsDirectory turns a configured DTOs directory into an absolute
+// path. Relative paths are joined to baseDir when set, and to the process
+// working directory otherwise.
+func resolveDTOsDirectory(dtosDir, baseDir string) string {
+	if !filepath.IsAbs(dtosDir) && baseDir != "" {
+		dtosDir = filepath.Join(baseDir, dtosDir)
 	}
+	if abs, err := filepath.Abs(dtosDir); err == nil {
+		return abs
+	}
+	return filepath.Clean(dtosDir)
+}
+
+// dtoSource is where the DTO files are read from: the directory dir of fsys.
+// diskDir is that directory on disk, and stays empty for sources embedded in
+// the binary.
+type dtoSource struct {
+	fsys    fs.FS
+	dir     string
+	diskDir string
+}
+
+// diskDTOSource reads the DTO files of a directory on disk.
+func diskDTOSource(dtosDir string) dtoSource {
+	dtosDir = resolveDTOsDirectory(dtosDir, "")
+	return dtoSource{fsys: os.DirFS(dtosDir), dir: ".", diskDir: dtosDir}
+}
+
+// dtoSourceFor returns the DTO files configured by cfg: DTOsDirectory within
+// DTOsFS (its root when empty) when set, the DTOsDirectory on disk otherwise.
+func dtoSourceFor(cfg GeneratorConfig) dtoSource {
+	if cfg.DTOsFS != nil {
+		return dtoSource{fsys: cfg.DTOsFS, dir: path.Clean(filepath.ToSlash(cfg.DTOsDirectory))}
+	}
+	return diskDTOSource(resolveDTOsDirectory(cfg.DTOsDirectory, cfg.DTOsBaseDirectory))
+}
+
+func (s dtoSource) String() string {
+	if s.diskDir != "" {
+		return s.diskDir
+	}
+	return s.dir
+}
+
+// filename names a file, given relative to the source directory, in parser
+// positions: its path on disk when there is one.
+func (s dtoSource) filename(rel string) string {
+	if s.diskDir != "" {
+		return filepath.Join(s.diskDir, filepath.FromSlash(rel))
+	}
+	return path.Join(s.dir, rel)
+}
 
-	files, err := os.ReadDir(dtosDir)
+// extractDTOs parses the DTOs of a file of the source, through the parse cache
+// for files on disk.
+func (s dtoSource) extractDTOs(rel string, info fs.FileInfo, src []byte) (map[string]dtoSchema, error) {
+	if s.diskDir == "" {
+		return extractDTOs(s.filename(rel), src)
+	}
+	return parsedDTOFiles.extract(s.filename(rel), info, src)
+}
+
+// walkGoFiles calls fn with the path, relative to the source directory, the
+// stat info and the content of each Go file, descending into subdirectories
+// when recursive. Unreadable files are skipped.
+func (s dtoSource) walkGoFiles(recursive bool, fn func(rel string, info fs.FileInfo, src []byte)) error {
+	return fs.WalkDir(s.fsys, s.dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if name != s.dir && !recursive {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(entry.Name(), ".go") {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		src, err := fs.ReadFile(s.fsys, name)
+		if err != nil {
+			return nil
+		}
+		rel := name
+		if s.dir != "." {
+			rel = strings.TrimPrefix(name, s.dir+"/")
+		}
+		fn(rel, info, src)
+		return nil
+	})
+}
+
+// loadResourceDTOsFrom parses every Go file of source into a resource named
+// after the file or its DTO types (see resourceName). With cfg.RecursiveDTOs,
+// subdirectories are scanned as well and each resource records the
+// subdirectory it was found in; files of different directories naming the
+// same resource (user.go and billing/user.go) are reported as an error.
+// PluralOverrides replaces the generated plural of the resources it names,
+// ResourceNames names the resources of the files it lists, and the
+// DTOVariants and MainDTOPatterns pick the main DTO a resource is named after.
+func loadResourceDTOsFrom(source dtoSource, cfg GeneratorConfig) (map[string]resourceDTOs, error) {
+	if _, err := fs.Stat(source.fsys, source.dir); errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("DTOs directory not found: %s", source)
+	}
+
+	var files []resourceDTOs
+	err := source.walkGoFiles(cfg.RecursiveDTOs, func(rel string, info fs.FileInfo, src []byte) {
+		dtos, err := source.extractDTOs(rel, info, src)
+		if err != nil || len(dtos) == 0 {
+			return
+		}
+
+		dir := path.Dir(rel)
+		if dir == "." {
+			dir = ""
+		}
+		files = append(files, resourceDTOs{Name: strings.TrimSuffix(path.Base(rel), ".go"), Dir: dir, DTOs: dtos})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to read dtos directory: %w", err)
 	}
 
-	resources := make(map[string]resourceDTOs)
+	variants := dtoVariantPatterns(cfg.DTOVariants)
+	names := make([]string, len(files))
+	claims := make(map[string]int)
+	for i, file := range files {
+		if name, ok := cfg.ResourceNames[path.Join(file.Dir, file.Name)]; ok {
+			names[i] = name
+		} else {
+			names[i] = resourceName(file.Name, file.DTOs, cfg.ResourceNames, variants, cfg.MainDTOPatterns)
+		}
+		claims[names[i]]++
+	}
 
-	for _, file := range files {
-		if !strings.HasSuffix(file.Name(), ".go") {
+	resources := make(map[string]resourceDTOs)
+	declaredIn := make(map[string]string)
+	var errs []error
+	for i, file := range files {
+		name := names[i]
+		// A name derived by several files is ambiguous, they keep their own
+		if claims[name] > 1 && name != file.Name {
+			name = file.Name
+		}
+		rel := path.Join(file.Dir, file.Name) + ".go"
+		if other, ok := declaredIn[name]; ok {
+			errs = append(errs, fmt.Errorf("DTO files %s and %s both declare resource %q, rename one with resource_names", other, rel, name))
 			continue
 		}
+		declaredIn[name] = rel
+		file.Name = name
+		file.PluralName = pluralize(name, cfg.PluralOverrides)
+		resources[name] = file
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	resolveEmbeddedDTOs(resources)
+	return resources, nil
+}
+
+// resourceName names the resource of a DTO file: its nameOverrides entry when
+// set, the file name when one of its DTOs is named after it (user.go
+// declaring UserResponseDTO), and otherwise its main DTO type name, picked
+// with the variant and main DTO patterns, without the DTO suffix, in
+// snake_case (user_dtos.go declaring UserDTO -> user). Files without a main
+// DTO keep their name.
+func resourceName(fileName string, dtos map[string]dtoSchema, nameOverrides, variants map[string]string, mainPatterns []string) string {
+	if name, ok := nameOverrides[fileName]; ok {
+		return name
+	}
+
+	stem := strings.Join(nameWords(fileName), "")
+	for name := range dtos {
+		if strings.HasPrefix(strings.ToLower(name), stem) {
+			return fileName
+		}
+	}
+
+	resource := resourceDTOs{DTOs: dtos}
+	mainDTO := resource.resolveMainDTO("", variants, mainPatterns)
+	if mainDTO == nil {
+		return fileName
+	}
+	typeName := strings.TrimSuffix(strings.TrimSuffix(mainDTO.Name, "DTO"), "Dto")
+	if typeName == "" {
+		return fileName
+	}
+	return strings.Join(nameWords(typeName), "_")
+}
 
-		filePath := filepath.Join(dtosDir, file.Name())
-		resourceName := strings.TrimSuffix(file.Name(), ".go")
+// loadNamedTypesFrom collects the named types declared across the Go files of
+// source (and its subdirectories when recursive), so fields typed with them
+// are documented as their underlying type, with the values of the constants
+// declared with them as enum. When resolveImports is set, types imported from
+// other packages are resolved by type-checking each directory's package; only
+// sources on disk, within their Go module, are resolved.
+func loadNamedTypesFrom(source dtoSource, recursive, resolveImports bool) (map[string]namedType, error) {
+	types := make(map[string]namedType)
+	enums := make(map[string][]interface{})
+	fset := token.NewFileSet()
+	packages := make(map[string][]*ast.File)
 
-		dtos, err := extractDTOsFromFile(filePath)
+	err := source.walkGoFiles(recursive, func(rel string, _ fs.FileInfo, src []byte) {
+		node, err := parser.ParseFile(fset, source.filename(rel), src, 0)
 		if err != nil {
-			continue
+			return
+		}
+		dir := path.Dir(rel)
+		packages[dir] = append(packages[dir], node)
+		maps.Copy(types, localNamedTypes(node))
+		for name, values := range localConstEnums(node) {
+			enums[name] = append(enums[name], values...)
 		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dtos directory: %w", err)
+	}
 
-		if len(dtos) > 0 {
-			resources[resourceName] = resourceDTOs{
-				Name:       resourceName,
-				PluralName: pluralize(resourceName),
-				DTOs:       dtos,
+	for name, values := range enums {
+		if named, ok := types[name]; ok {
+			named.Enum = values
+			types[name] = named
+		}
+	}
+	if resolveImports && source.diskDir != "" {
+		for _, dir := range sortedKeys(packages) {
+			resolveImportedTypes(fset, packages[dir], types)
+		}
+	}
+	return types, nil
+}
+
+// resolveEmbeddedDTOs fills the members of embedded fields referring to a
+// DTO declared in another file of the directory.
+func resolveEmbeddedDTOs(resources map[string]resourceDTOs) {
+	dtos := make(map[string]dtoSchema)
+	for _, resource := range resources {
+		for name, dto := range resource.DTOs {
+			dtos[name] = dto
+		}
+	}
+
+	var resolve func(fields []structField, visiting map[string]bool)
+	resolve = func(fields []structField, visiting map[string]bool) {
+		for i := range fields {
+			field := &fields[i]
+			dto, ok := dtos[field.Type]
+			if !field.Embedded || field.Fields != nil || !ok || visiting[field.Type] {
+				continue
 			}
+			visiting[field.Type] = true
+			field.Fields = slices.Clone(dto.Fields)
+			resolve(field.Fields, visiting)
+			delete(visiting, field.Type)
+		}
+	}
+	for _, resource := range resources {
+		for name, dto := range resource.DTOs {
+			resolve(dto.Fields, map[string]bool{name: true})
 		}
 	}
+}
 
-	return resources, nil
+// resolveEmbeddedImports fills the members of embedded fields referring to a
+// struct imported from another package, once types are resolved.
+func resolveEmbeddedImports(resources map[string]resourceDTOs, types map[string]namedType) {
+	var resolve func(fields []structField)
+	resolve = func(fields []structField) {
+		for i := range fields {
+			field := &fields[i]
+			if !field.Embedded {
+				continue
+			}
+			if field.Fields == nil {
+				field.Fields = types[field.Type].Fields
+				continue
+			}
+			resolve(field.Fields)
+		}
+	}
+	for _, resource := range resources {
+		for _, dto := range resource.DTOs {
+			resolve(dto.Fields)
+		}
+	}
+}
+
+// loadResourceExampleFrom reads the optional <resource>.example.json companion
+// of a DTO file found in the subdirectory dir of source. A missing file is not
+// an error and yields a nil example.
+func loadResourceExampleFrom(source dtoSource, dir, resourceName string) (interface{}, error) {
+	raw, err := fs.ReadFile(source.fsys, path.Join(source.dir, dir, resourceName+".example.json"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s example: %w", resourceName, err)
+	}
+
+	var example interface{}
+	if err := json.Unmarshal(raw, &example); err != nil {
+		return nil, fmt.Errorf("invalid %s example JSON: %w", resourceName, err)
+	}
+	return example, nil
 }
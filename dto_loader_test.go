@@ -3,7 +3,10 @@ package openapi
 import (
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"testing/fstest"
 )
 
 func TestLoadResourceDTOs(t *testing.T) {
@@ -69,15 +72,15 @@ func TestLoadResourceDTOs(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			dtosDir := tt.setupFunc(t)
 
-			got, err := loadResourceDTOs(dtosDir)
+			got, err := loadResourceDTOsFrom(diskDTOSource(dtosDir), GeneratorConfig{})
 			if (err != nil) != tt.wantErr {
-				t.Errorf("loadResourceDTOs() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("loadResourceDTOsFrom() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
 			if !tt.wantErr {
 				if len(got) != tt.wantCount {
-					t.Errorf("loadResourceDTOs() returned %d resources, want %d", len(got), tt.wantCount)
+					t.Errorf("loadResourceDTOsFrom() returned %d resources, want %d", len(got), tt.wantCount)
 				}
 
 				if tt.validate != nil {
@@ -287,3 +290,355 @@ func validatePluralization(t *testing.T, resources map[string]resourceDTOs) {
 		t.Errorf("category.PluralName = %v, want categories", category.PluralName)
 	}
 }
+
+func TestLoadResourceExampleFrom(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		file    string
+		content string
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			name:    "valid example file",
+			file:    "dtos/user.example.json",
+			content: `{"id": 1, "name": "Ada"}`,
+			want:    map[string]interface{}{"id": float64(1), "name": "Ada"},
+		},
+		{
+			name:    "example in a subdirectory",
+			dir:     "billing",
+			file:    "dtos/billing/user.example.json",
+			content: `{"id": 2}`,
+			want:    map[string]interface{}{"id": float64(2)},
+		},
+		{
+			name: "missing example file",
+			want: nil,
+		},
+		{
+			name:    "invalid JSON",
+			file:    "dtos/user.example.json",
+			content: `{"id":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"dtos/user.go": {Data: []byte("package dtos\n")}}
+			if tt.file != "" {
+				fsys[tt.file] = &fstest.MapFile{Data: []byte(tt.content)}
+			}
+
+			got, err := loadResourceExampleFrom(dtoSource{fsys: fsys, dir: "dtos"}, tt.dir, "user")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadResourceExampleFrom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadResourceExampleFrom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveDTOsDirectory(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Getwd() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		dtosDir string
+		baseDir string
+		want    string
+	}{
+		{
+			name:    "absolute directory is kept",
+			dtosDir: "/srv/app/dtos",
+			baseDir: "/ignored",
+			want:    "/srv/app/dtos",
+		},
+		{
+			name:    "relative directory resolved against base",
+			dtosDir: "./dtos",
+			baseDir: "/srv/app",
+			want:    "/srv/app/dtos",
+		},
+		{
+			name:    "relative directory resolved against working directory",
+			dtosDir: "dtos",
+			want:    filepath.Join(wd, "dtos"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveDTOsDirectory(tt.dtosDir, tt.baseDir); got != tt.want {
+				t.Errorf("resolveDTOsDirectory(%q, %q) = %v, want %v", tt.dtosDir, tt.baseDir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadResourceDTOs_NotFoundShowsResolvedPath(t *testing.T) {
+	_, err := loadResourceDTOsFrom(diskDTOSource("missing-dtos"), GeneratorConfig{})
+	if err == nil {
+		t.Fatal("loadResourceDTOsFrom() expected error for missing directory")
+	}
+
+	want := resolveDTOsDirectory("missing-dtos", "")
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("loadResourceDTOsFrom() error = %v, want it to mention %s", err, want)
+	}
+}
+
+func TestLoadResourceDTOs_Recursive(t *testing.T) {
+	dtosDir := t.TempDir()
+	files := map[string]string{
+		"user.go":            "UserDTO",
+		"billing/invoice.go": "InvoiceDTO",
+		"billing/tax/vat.go": "VatDTO",
+	}
+	for name, dto := range files {
+		path := filepath.Join(dtosDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		content := "package dto\n\ntype " + dto + " struct {\n\tID int64 `json:\"id\"`\n}\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		recursive bool
+		wantDirs  map[string]string
+	}{
+		{
+			name:      "top level only",
+			recursive: false,
+			wantDirs:  map[string]string{"user": ""},
+		},
+		{
+			name:      "recursive records subdirectories",
+			recursive: true,
+			wantDirs:  map[string]string{"user": "", "invoice": "billing", "vat": "billing/tax"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadResourceDTOsFrom(diskDTOSource(dtosDir), GeneratorConfig{RecursiveDTOs: tt.recursive})
+			if err != nil {
+				t.Fatalf("loadResourceDTOsFrom() error = %v", err)
+			}
+
+			gotDirs := make(map[string]string, len(got))
+			for name, resource := range got {
+				gotDirs[name] = resource.Dir
+			}
+			if !reflect.DeepEqual(gotDirs, tt.wantDirs) {
+				t.Errorf("loadResourceDTOsFrom() dirs = %v, want %v", gotDirs, tt.wantDirs)
+			}
+		})
+	}
+}
+
+func TestLoadNamedTypes(t *testing.T) {
+	dtosDir := t.TempDir()
+	files := map[string]string{
+		"user.go":          "package dto\n\ntype Email string\n\ntype UserDTO struct {\n\tEmail Email `json:\"email\"`\n}\n",
+		"types.go":         "package dto\n\ntype Status int\n",
+		"status.go":        "package dto\n\nconst (\n\tStatusActive Status = iota\n\tStatusClosed\n)\n",
+		"billing/money.go": "package billing\n\ntype Cents int64\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dtosDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		recursive bool
+		want      map[string]namedType
+	}{
+		{
+			name:      "top level only",
+			recursive: false,
+			want:      map[string]namedType{"Email": {Underlying: "string"}, "Status": {Underlying: "int", Enum: []interface{}{int64(0), int64(1)}}},
+		},
+		{
+			name:      "recursive",
+			recursive: true,
+			want: map[string]namedType{
+				"Email":  {Underlying: "string"},
+				"Status": {Underlying: "int", Enum: []interface{}{int64(0), int64(1)}},
+				"Cents":  {Underlying: "int64"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadNamedTypesFrom(diskDTOSource(dtosDir), tt.recursive, true)
+			if err != nil {
+				t.Fatalf("loadNamedTypesFrom() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadNamedTypesFrom() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadResourceDTOsFrom_DuplicateNames(t *testing.T) {
+	fsys := fstest.MapFS{
+		"dtos/user.go":         {Data: []byte("package dto\n\ntype UserDTO struct {\n\tName string `json:\"name\"`\n}\n")},
+		"dtos/billing/user.go": {Data: []byte("package billing\n\ntype UserDTO struct {\n\tIBAN string `json:\"iban\"`\n}\n")},
+	}
+	source := dtoSourceFor(GeneratorConfig{DTOsFS: fsys, DTOsDirectory: "dtos"})
+
+	_, err := loadResourceDTOsFrom(source, GeneratorConfig{RecursiveDTOs: true})
+	if want := `DTO files billing/user.go and user.go both declare resource "user"`; err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("loadResourceDTOsFrom() error = %v, want it to contain %q", err, want)
+	}
+
+	resources, err := loadResourceDTOsFrom(source, GeneratorConfig{RecursiveDTOs: true, ResourceNames: map[string]string{"billing/user": "billing_user"}})
+	if err != nil {
+		t.Fatalf("loadResourceDTOsFrom() error = %v", err)
+	}
+	if got, want := sortedKeys(resources), []string{"billing_user", "user"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("loadResourceDTOsFrom() resources = %v, want %v", got, want)
+	}
+	if resources["billing_user"].Dir != "billing" {
+		t.Errorf("billing_user dir = %q, want billing", resources["billing_user"].Dir)
+	}
+}
+
+func TestLoadResourceDTOsFrom_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"dtos/user.go":            {Data: []byte("package dto\n\ntype UserDTO struct {\n\tRole Role `json:\"role\"`\n}\n\ntype Role string\n\nconst RoleAdmin Role = \"admin\"\n")},
+		"dtos/user.example.json":  {Data: []byte(`{"role":"admin"}`)},
+		"dtos/billing/invoice.go": {Data: []byte("package billing\n\ntype InvoiceDTO struct {\n\tTotal int `json:\"total\"`\n}\n")},
+		"dtos/billing/README.md":  {Data: []byte("not go")},
+		"other/ignored.go":        {Data: []byte("package other\n\ntype IgnoredDTO struct{}\n")},
+	}
+
+	tests := []struct {
+		name      string
+		dir       string
+		recursive bool
+		want      map[string]string
+		wantErr   bool
+	}{
+		{name: "directory within the file system", dir: "dtos", want: map[string]string{"user": ""}},
+		{name: "recursive", dir: "./dtos", recursive: true, want: map[string]string{"user": "", "invoice": "billing"}},
+		{name: "root of the file system", dir: "", recursive: true, want: map[string]string{"user": "dtos", "invoice": "dtos/billing", "ignored": "other"}},
+		{name: "missing directory", dir: "missing", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := dtoSourceFor(GeneratorConfig{DTOsFS: fsys, DTOsDirectory: tt.dir})
+			resources, err := loadResourceDTOsFrom(source, GeneratorConfig{RecursiveDTOs: tt.recursive})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadResourceDTOsFrom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := make(map[string]string)
+			for name, resource := range resources {
+				got[name] = resource.Dir
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadResourceDTOsFrom() resource dirs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	source := dtoSourceFor(GeneratorConfig{DTOsFS: fsys, DTOsDirectory: "dtos"})
+	types, err := loadNamedTypesFrom(source, false, true)
+	if err != nil {
+		t.Fatalf("loadNamedTypesFrom() error = %v", err)
+	}
+	if want := (namedType{Underlying: "string", Enum: []interface{}{"admin"}}); !reflect.DeepEqual(types["Role"], want) {
+		t.Errorf("loadNamedTypesFrom() Role = %+v, want %+v", types["Role"], want)
+	}
+
+	example, err := loadResourceExampleFrom(source, "", "user")
+	if err != nil {
+		t.Fatalf("loadResourceExampleFrom() error = %v", err)
+	}
+	if want := map[string]interface{}{"role": "admin"}; !reflect.DeepEqual(example, want) {
+		t.Errorf("loadResourceExampleFrom() = %v, want %v", example, want)
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileName  string
+		dtos      []string
+		overrides map[string]string
+		variants  map[string]string
+		patterns  []string
+		want      string
+	}{
+		{name: "file named after its DTOs", fileName: "user", dtos: []string{"UserDTO", "CreateUserDTO"}, want: "user"},
+		{name: "file name prefixing a response DTO", fileName: "user", dtos: []string{"UserResponseDTO"}, want: "user"},
+		{name: "snake_case file", fileName: "order_item", dtos: []string{"OrderItemDTO"}, want: "order_item"},
+		{name: "derived from the DTO type", fileName: "user_dtos", dtos: []string{"UserDTO", "CreateUserDTO"}, want: "user"},
+		{name: "derived in snake_case", fileName: "models", dtos: []string{"OrderItemDTO"}, want: "order_item"},
+		{name: "Dto suffix", fileName: "models", dtos: []string{"InvoiceDto"}, want: "invoice"},
+		{name: "variants only keep the file name", fileName: "requests", dtos: []string{"CreateUserDTO", "UpdateUserDTO"}, want: "requests"},
+		{name: "override", fileName: "user_dtos", dtos: []string{"UserDTO"}, overrides: map[string]string{"user_dtos": "member"}, want: "member"},
+		{name: "default variants", fileName: "models", dtos: []string{"AccountAddDTO", "MemberDTO"}, want: "account_add"},
+		{name: "configured variants", fileName: "models", dtos: []string{"AccountAddDTO", "MemberDTO"}, variants: map[string]string{DTOVariantCreate: "*Add*"}, want: "member"},
+		{name: "main DTO patterns", fileName: "models", dtos: []string{"AddressDTO", "CustomerResponseDTO"}, patterns: []string{"*ResponseDTO"}, want: "customer_response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dtos := make(map[string]dtoSchema)
+			for _, name := range tt.dtos {
+				dtos[name] = dtoSchema{Name: name}
+			}
+			if got := resourceName(tt.fileName, dtos, tt.overrides, dtoVariantPatterns(tt.variants), tt.patterns); got != tt.want {
+				t.Errorf("resourceName(%q) = %q, want %q", tt.fileName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadResourceDTOs_TypeNamedResources(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"user_dtos.go":   "package dto\n\ntype UserDTO struct {\n\tName string `json:\"name\"`\n}\n",
+		"order.go":       "package dto\n\ntype OrderDTO struct {\n\tTotal int `json:\"total\"`\n}\n",
+		"billing.go":     "package dto\n\ntype InvoiceDTO struct {\n\tTotal int `json:\"total\"`\n}\n",
+		"invoices_v2.go": "package dto\n\ntype InvoiceDTO struct {\n\tAmount int `json:\"amount\"`\n}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	resources, err := loadResourceDTOsFrom(diskDTOSource(dir), GeneratorConfig{})
+	if err != nil {
+		t.Fatalf("loadResourceDTOsFrom() error = %v", err)
+	}
+	// Both files deriving invoice keep their file name
+	if got, want := sortedKeys(resources), []string{"billing", "invoices_v2", "order", "user"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("loadResourceDTOsFrom() resources = %v, want %v", got, want)
+	}
+	if resources["user"].PluralName != "users" {
+		t.Errorf("user plural = %q, want users", resources["user"].PluralName)
+	}
+}
@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/nicolasbonnici/gorest/logger"
+)
+
+// dtoWatchDebounce groups the events of a single save (editors often write,
+// rename and chmod in a row) and of bulk changes like a git checkout into one
+// rebuild.
+const dtoWatchDebounce = 100 * time.Millisecond
+
+// dtoWatcher watches a DTOs directory, and its subdirectories when recursive,
+// calling onChange once the DTO files stop changing.
+type dtoWatcher struct {
+	watcher   *fsnotify.Watcher
+	recursive bool
+	onChange  func()
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// watchDTODirectory starts watching dir in the background.
+func watchDTODirectory(dir string, recursive bool, onChange func()) (*dtoWatcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &dtoWatcher{
+		watcher:   watcher,
+		recursive: recursive,
+		onChange:  onChange,
+		done:      make(chan struct{}),
+	}
+	if err := w.add(dir); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// add watches dir, along with its subdirectories when recursive. fsnotify
+// does not watch directories recursively by itself.
+func (w *dtoWatcher) add(dir string) error {
+	if !w.recursive {
+		return w.watcher.Add(dir)
+	}
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		return w.watcher.Add(path)
+	})
+}
+
+func (w *dtoWatcher) run() {
+	defer close(w.done)
+
+	var rebuild <-chan time.Time
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			if w.handle(event) {
+				rebuild = time.After(dtoWatchDebounce)
+			}
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			logger.Log.Warn("DTO watcher error", "error", err)
+		case <-rebuild:
+			rebuild = nil
+			w.onChange()
+		}
+	}
+}
+
+// handle reacts to a file system event, reporting whether it changes the
+// DTOs. Directories created below a recursively watched one are watched in
+// turn, and removed entries may have held DTO files.
+func (w *dtoWatcher) handle(event fsnotify.Event) bool {
+	if event.Has(fsnotify.Create) && w.recursive {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err := w.add(event.Name); err != nil {
+				logger.Log.Warn("Failed to watch DTO directory", "directory", event.Name, "error", err)
+			}
+			return true
+		}
+	}
+	if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+		return true
+	}
+	if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+		return false
+	}
+	return isDTOSourceFile(event.Name)
+}
+
+// isDTOSourceFile reports whether a file is read when loading DTOs: a Go
+// source or a resource example.
+func isDTOSourceFile(name string) bool {
+	return strings.HasSuffix(name, ".go") || strings.HasSuffix(name, ".example.json")
+}
+
+// Close stops watching, waiting for a rebuild in progress to finish.
+func (w *dtoWatcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		err = w.watcher.Close()
+		<-w.done
+	})
+	return err
+}
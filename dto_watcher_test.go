@@ -0,0 +1,148 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsDTOSourceFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"user.go", true},
+		{"user.example.json", true},
+		{"user.json", false},
+		{".user.go.swp", false},
+		{"README.md", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDTOSourceFile(tt.name); got != tt.want {
+				t.Errorf("isDTOSourceFile(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchDTODirectory(t *testing.T) {
+	tests := []struct {
+		name      string
+		recursive bool
+		// path of the written DTO file below a new subdirectory
+		file string
+	}{
+		{name: "top level", file: "user.go"},
+		{name: "new subdirectory", recursive: true, file: "billing/invoice.go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			changed := make(chan struct{}, 1)
+			watcher, err := watchDTODirectory(dir, tt.recursive, func() {
+				select {
+				case changed <- struct{}{}:
+				default:
+				}
+			})
+			if err != nil {
+				t.Fatalf("watchDTODirectory() error = %v", err)
+			}
+			t.Cleanup(func() { watcher.Close() })
+
+			path := filepath.Join(dir, filepath.FromSlash(tt.file))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			// Let the watcher pick up the new subdirectory before writing in it
+			time.Sleep(2 * dtoWatchDebounce)
+			if err := os.WriteFile(path, []byte("package dto\n"), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", tt.file, err)
+			}
+
+			select {
+			case <-changed:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("no change reported after writing %s", tt.file)
+			}
+		})
+	}
+}
+
+func TestOpenAPIPlugin_WatchDTOs(t *testing.T) {
+	plugin, app := setupOpenAPIJSONTest(t)
+	plugin.opts.WatchDTOs = true
+	plugin.opts.OutputFile = filepath.Join(t.TempDir(), "openapi.json")
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+	t.Cleanup(func() { plugin.Close() })
+
+	watcher := plugin.dtoWatcher
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("second SetupEndpoints() error = %v", err)
+	}
+	if plugin.dtoWatcher != watcher {
+		t.Error("a second SetupEndpoints should not start another watcher")
+	}
+
+	hasProducts := func() bool {
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+		req.Host = "localhost"
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		var spec map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+			t.Fatalf("Failed to parse JSON response: %v", err)
+		}
+		_, ok := spec["paths"].(map[string]interface{})["/products"]
+		return ok
+	}
+
+	if hasProducts() {
+		t.Fatal("/products should not be documented before its DTO exists")
+	}
+
+	dtoContent := "package dto\n\ntype ProductDTO struct {\n\tID int64 `json:\"id\"`\n}\n"
+	if err := os.WriteFile(filepath.Join(plugin.opts.DTOsDirectory, "product.go"), []byte(dtoContent), 0644); err != nil {
+		t.Fatalf("Failed to create test DTO: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !hasProducts() {
+		if time.Now().After(deadline) {
+			t.Fatal("the spec should be rebuilt once the DTO file is written")
+		}
+		time.Sleep(dtoWatchDebounce)
+	}
+
+	for {
+		written, err := os.ReadFile(plugin.opts.OutputFile)
+		if err != nil {
+			t.Fatalf("Failed to read output file: %v", err)
+		}
+		if strings.Contains(string(written), `"/products"`) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("output_file should be rewritten once the spec is rebuilt")
+		}
+		time.Sleep(dtoWatchDebounce)
+	}
+}
+
+func TestOpenAPIPlugin_Close(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Close(); err != nil {
+		t.Errorf("Close() without a watcher error = %v, want nil", err)
+	}
+}
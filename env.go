@@ -0,0 +1,64 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// applyEnv overrides options from OPENAPI_* environment variables, so a
+// single build serves the right server URL, version and access settings per
+// environment. Unset variables leave the options untouched; every invalid
+// one is reported, joined.
+func (o *Options) applyEnv(lookup func(string) (string, bool)) error {
+	texts := map[string]*string{
+		"OPENAPI_TITLE":               &o.Title,
+		"OPENAPI_VERSION":             &o.Version,
+		"OPENAPI_DESCRIPTION":         &o.Description,
+		"OPENAPI_ENVIRONMENT":         &o.Environment,
+		"OPENAPI_BASE_PATH":           &o.BasePath,
+		"OPENAPI_TERMS_OF_SERVICE":    &o.TermsOfService,
+		"OPENAPI_DOCS_TOKEN":          &o.DocsToken,
+		"OPENAPI_INTERNAL_DOCS_TOKEN": &o.InternalDocsToken,
+		"OPENAPI_OUTPUT_FILE":         &o.OutputFile,
+		"OPENAPI_OUTPUT_SERVER_URL":   &o.OutputServerURL,
+		"OPENAPI_LOCALE":              &o.Locale,
+	}
+	for name, target := range texts {
+		if value, ok := lookup(name); ok {
+			*target = value
+		}
+	}
+
+	if serverURL, ok := lookup("OPENAPI_SERVER_URL"); ok {
+		o.ServerURL = serverURL
+		o.Servers = nil
+	}
+
+	ints := map[string]*int{
+		"OPENAPI_PAGINATION_LIMIT":     &o.PaginationLimit,
+		"OPENAPI_PAGINATION_MAX_LIMIT": &o.PaginationMaxLimit,
+	}
+	var errs []error
+	for _, name := range sortedKeys(ints) {
+		if value, ok := lookup(name); ok {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s %q: %w", name, value, err))
+				continue
+			}
+			*ints[name] = n
+		}
+	}
+
+	if value, ok := lookup("OPENAPI_ENABLED"); ok {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid OPENAPI_ENABLED %q: %w", value, err))
+		} else {
+			o.Disabled = !enabled
+		}
+	}
+
+	return errors.Join(errs...)
+}
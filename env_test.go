@@ -0,0 +1,137 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOptions_ApplyEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    func(o *Options)
+		wantErr string
+	}{
+		{
+			name: "no variables",
+			env:  map[string]string{},
+			want: func(o *Options) {},
+		},
+		{
+			name: "strings",
+			env: map[string]string{
+				"OPENAPI_TITLE":       "Orders API",
+				"OPENAPI_VERSION":     "2.3.1",
+				"OPENAPI_BASE_PATH":   "/api/v2",
+				"OPENAPI_ENVIRONMENT": "staging",
+				"OPENAPI_DOCS_TOKEN":  "s3cret",
+			},
+			want: func(o *Options) {
+				o.Title = "Orders API"
+				o.Version = "2.3.1"
+				o.BasePath = "/api/v2"
+				o.Environment = "staging"
+				o.DocsToken = "s3cret"
+			},
+		},
+		{
+			name: "server url",
+			env:  map[string]string{"OPENAPI_SERVER_URL": "https://staging.example.com"},
+			want: func(o *Options) {
+				o.ServerURL = "https://staging.example.com"
+			},
+		},
+		{
+			name: "numbers and booleans",
+			env: map[string]string{
+				"OPENAPI_PAGINATION_LIMIT":     "25",
+				"OPENAPI_PAGINATION_MAX_LIMIT": "250",
+				"OPENAPI_ENABLED":              "false",
+			},
+			want: func(o *Options) {
+				o.PaginationLimit = 25
+				o.PaginationMaxLimit = 250
+				o.Disabled = true
+			},
+		},
+		{
+			name:    "invalid number",
+			env:     map[string]string{"OPENAPI_PAGINATION_LIMIT": "twenty"},
+			wantErr: `invalid OPENAPI_PAGINATION_LIMIT "twenty"`,
+		},
+		{
+			name:    "invalid boolean",
+			env:     map[string]string{"OPENAPI_ENABLED": "maybe"},
+			wantErr: `invalid OPENAPI_ENABLED "maybe"`,
+		},
+		{
+			name: "every invalid variable",
+			env: map[string]string{
+				"OPENAPI_PAGINATION_MAX_LIMIT": "lots",
+				"OPENAPI_PAGINATION_LIMIT":     "twenty",
+				"OPENAPI_ENABLED":              "maybe",
+			},
+			wantErr: `invalid OPENAPI_PAGINATION_LIMIT "twenty": strconv.Atoi: parsing "twenty": invalid syntax
+invalid OPENAPI_PAGINATION_MAX_LIMIT "lots": strconv.Atoi: parsing "lots": invalid syntax
+invalid OPENAPI_ENABLED "maybe"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookup := func(name string) (string, bool) {
+				value, ok := tt.env[name]
+				return value, ok
+			}
+
+			opts := DefaultOptions()
+			err := opts.applyEnv(lookup)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("applyEnv() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyEnv() error = %v", err)
+			}
+
+			want := DefaultOptions()
+			tt.want(&want)
+			if !reflect.DeepEqual(opts, want) {
+				t.Errorf("applyEnv() = %+v, want %+v", opts, want)
+			}
+		})
+	}
+}
+
+func TestOpenAPIPlugin_Initialize_EnvOverrides(t *testing.T) {
+	t.Setenv("OPENAPI_TITLE", "Orders API")
+	t.Setenv("OPENAPI_SERVER_URL", "https://api.example.com")
+
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{"title": "Configured", "server_url": "https://configured.example.com"}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if plugin.opts.Title != "Orders API" {
+		t.Errorf("title = %q, want the OPENAPI_TITLE override", plugin.opts.Title)
+	}
+	if plugin.opts.ServerURL != "https://api.example.com" {
+		t.Errorf("serverURL = %q, want the OPENAPI_SERVER_URL override", plugin.opts.ServerURL)
+	}
+
+	t.Setenv("OPENAPI_PAGINATION_MAX_LIMIT", "oops")
+	if err := plugin.Initialize(map[string]interface{}{}); err == nil {
+		t.Error("Initialize() should reject an invalid OPENAPI_PAGINATION_MAX_LIMIT")
+	}
+	err := plugin.Initialize(map[string]interface{}{"title": 42, "pagination_limit": -1})
+	for _, want := range []string{"OPENAPI_PAGINATION_MAX_LIMIT", "title", "PaginationLimit"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Initialize() error = %v, want it to report %s", err, want)
+		}
+	}
+	if _, err := NewPluginWithOptions(DefaultOptions()); err == nil {
+		t.Error("NewPluginWithOptions() should reject an invalid OPENAPI_PAGINATION_MAX_LIMIT")
+	}
+}
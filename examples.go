@@ -0,0 +1,170 @@
+package openapi
+
+import (
+	"maps"
+	"strings"
+)
+
+// referenceSharedExample points every JSON request and response body of the
+// path item that carries exactly the given component schema at the shared
+// components/examples entry of the same name.
+func referenceSharedExample(pathItem map[string]interface{}, schemaName string) {
+	schemaRef := "#/components/schemas/" + schemaName
+	exampleRef := map[string]interface{}{
+		schemaName: map[string]string{"$ref": "#/components/examples/" + schemaName},
+	}
+
+	for _, operation := range pathItem {
+		op, ok := operation.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if body, ok := op["requestBody"].(map[string]interface{}); ok {
+			addExampleRef(body, schemaRef, exampleRef)
+		}
+
+		responses, _ := op["responses"].(map[string]interface{})
+		for _, response := range responses {
+			if r, ok := response.(map[string]interface{}); ok {
+				addExampleRef(r, schemaRef, exampleRef)
+			}
+		}
+	}
+}
+
+func addExampleRef(holder map[string]interface{}, schemaRef string, exampleRef map[string]interface{}) {
+	content, _ := holder["content"].(map[string]interface{})
+	media, ok := content["application/json"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	if schema, ok := media["schema"].(map[string]string); ok && schema["$ref"] == schemaRef {
+		media["examples"] = exampleRef
+	}
+}
+
+// synthesizeSchemaExamples attaches a generated example to every component
+// schema that has none, composing nested objects, arrays and $ref targets.
+// Schemas already covered by a shared components/examples entry are skipped.
+func synthesizeSchemaExamples(schemas, sharedExamples map[string]interface{}) {
+	for _, name := range sortedKeys(schemas) {
+		schema, ok := schemas[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := schema["example"]; ok {
+			continue
+		}
+		if _, ok := sharedExamples[name]; ok {
+			continue
+		}
+		if example := synthesizeExample(schema, schemas, map[string]bool{name: true}); example != nil {
+			schema["example"] = example
+		}
+	}
+}
+
+// synthesizeExample builds an example value for a schema. visiting holds the
+// component schemas being expanded so recursive references stop at nil.
+func synthesizeExample(schema map[string]interface{}, schemas map[string]interface{}, visiting map[string]bool) interface{} {
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/components/schemas/")
+		target, ok := schemas[name].(map[string]interface{})
+		if !ok || visiting[name] {
+			return nil
+		}
+		visiting[name] = true
+		defer delete(visiting, name)
+		return synthesizeExample(target, schemas, visiting)
+	}
+
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		if len(allOf) == 1 {
+			return synthesizeExample(asSchema(allOf[0]), schemas, visiting)
+		}
+		// Composed objects merge the examples of their parts
+		merged := make(map[string]interface{})
+		for _, part := range allOf {
+			if example, ok := synthesizeExample(asSchema(part), schemas, visiting).(map[string]interface{}); ok {
+				maps.Copy(merged, example)
+			}
+		}
+		return merged
+	}
+
+	switch enum := schema["enum"].(type) {
+	case []string:
+		if len(enum) > 0 {
+			return enum[0]
+		}
+	case []interface{}:
+		if len(enum) > 0 {
+			return enum[0]
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		properties, _ := schema["properties"].(map[string]interface{})
+		example := make(map[string]interface{}, len(properties))
+		for _, name := range sortedKeys(properties) {
+			if value := synthesizeExample(asSchema(properties[name]), schemas, visiting); value != nil {
+				example[name] = value
+			}
+		}
+		return example
+	case "array":
+		if item := synthesizeExample(asSchema(schema["items"]), schemas, visiting); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return true
+	case "string":
+		return stringExample(schema["format"])
+	}
+	return nil
+}
+
+func stringExample(format interface{}) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri":
+		return "https://example.com"
+	case "byte":
+		return "U3dhZ2dlciByb2Nrcw=="
+	}
+	return "string"
+}
+
+// asSchema normalizes the two schema representations used by the builders:
+// plain maps and the map[string]string form of $ref schemas.
+func asSchema(value interface{}) map[string]interface{} {
+	switch schema := value.(type) {
+	case map[string]interface{}:
+		return schema
+	case map[string]string:
+		converted := make(map[string]interface{}, len(schema))
+		for k, v := range schema {
+			converted[k] = v
+		}
+		return converted
+	}
+	return map[string]interface{}{}
+}
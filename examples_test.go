@@ -0,0 +1,194 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestReferenceSharedExample(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+	item := buildItemEndpoints(resource, "User", GeneratorConfig{})
+
+	referenceSharedExample(item, "User")
+
+	want := map[string]interface{}{
+		"User": map[string]string{"$ref": "#/components/examples/User"},
+	}
+
+	get := item["get"].(map[string]interface{})
+	media := get["responses"].(map[string]interface{})["200"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	if !reflect.DeepEqual(media["examples"], want) {
+		t.Errorf("GET 200 examples = %v, want %v", media["examples"], want)
+	}
+
+	put := item["put"].(map[string]interface{})
+	body := put["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	if !reflect.DeepEqual(body["examples"], want) {
+		t.Errorf("PUT requestBody examples = %v, want %v", body["examples"], want)
+	}
+}
+
+func TestGenerateOpenAPISpec_SharedExamples(t *testing.T) {
+	tempDir := t.TempDir()
+
+	userContent := `package dto
+
+type UserDTO struct {
+	ID   int64  ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "user.go"), []byte(userContent), 0644); err != nil {
+		t.Fatalf("Failed to create user.go: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "user.example.json"), []byte(`{"id": 7, "name": "Ada"}`), 0644); err != nil {
+		t.Fatalf("Failed to create user.example.json: %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(fiber.New(), GeneratorConfig{
+		DTOsDirectory:  tempDir,
+		LoadExamples:   true,
+		SharedExamples: true,
+	})
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	components := spec["components"].(map[string]interface{})
+	examples, ok := components["examples"].(map[string]interface{})
+	if !ok {
+		t.Fatal("components missing examples")
+	}
+	if _, ok := examples["User"]; !ok {
+		t.Error("components/examples missing User")
+	}
+
+	user := components["schemas"].(map[string]interface{})["User"].(map[string]interface{})
+	if _, ok := user["example"]; ok {
+		t.Error("User schema should not inline the example when SharedExamples is set")
+	}
+
+	post := spec["paths"].(map[string]interface{})["/users"].(map[string]interface{})["post"].(map[string]interface{})
+	created := post["responses"].(map[string]interface{})["201"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	ref := created["examples"].(map[string]interface{})["User"].(map[string]string)["$ref"]
+	if ref != "#/components/examples/User" {
+		t.Errorf("POST 201 example $ref = %v, want #/components/examples/User", ref)
+	}
+}
+
+func TestSynthesizeExample(t *testing.T) {
+	schemas := map[string]interface{}{
+		"Address": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"city": map[string]interface{}{"type": "string"},
+				"zip":  map[string]interface{}{"type": "string", "example": "75001"},
+			},
+		},
+		"Node": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id":     map[string]interface{}{"type": "integer"},
+				"parent": map[string]string{"$ref": "#/components/schemas/Node"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		schema map[string]interface{}
+		want   interface{}
+	}{
+		{
+			name: "inline nested object",
+			schema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{"type": "string"},
+					"meta": map[string]interface{}{
+						"type": "object",
+						"properties": map[string]interface{}{
+							"active":    map[string]interface{}{"type": "boolean"},
+							"createdAt": map[string]interface{}{"type": "string", "format": "date-time"},
+						},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"name": "string",
+				"meta": map[string]interface{}{"active": true, "createdAt": "2024-01-01T00:00:00Z"},
+			},
+		},
+		{
+			name: "referenced object inside an array",
+			schema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"addresses": map[string]interface{}{
+						"type":  "array",
+						"items": map[string]string{"$ref": "#/components/schemas/Address"},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"addresses": []interface{}{map[string]interface{}{"city": "string", "zip": "75001"}},
+			},
+		},
+		{
+			name:   "recursive reference stops",
+			schema: map[string]interface{}{"$ref": "#/components/schemas/Node"},
+			want:   map[string]interface{}{"id": 0},
+		},
+		{
+			name:   "enum uses the first value",
+			schema: map[string]interface{}{"type": "string", "enum": []string{"draft", "live"}},
+			want:   "draft",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := synthesizeExample(tt.schema, schemas, map[string]bool{})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("synthesizeExample() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPISpec_SynthesizeExamples(t *testing.T) {
+	tempDir := t.TempDir()
+
+	orderContent := `package dto
+
+type OrderDTO struct {
+	ID       int64 ` + "`json:\"id\"`" + `
+	Customer struct {
+		Name  string ` + "`json:\"name\"`" + `
+		Email string ` + "`json:\"email\"`" + `
+	} ` + "`json:\"customer\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "order.go"), []byte(orderContent), 0644); err != nil {
+		t.Fatalf("Failed to create order.go: %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(fiber.New(), GeneratorConfig{
+		DTOsDirectory:      tempDir,
+		SynthesizeExamples: true,
+	})
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	order := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})["Order"].(map[string]interface{})
+	want := map[string]interface{}{
+		"id":       0,
+		"customer": map[string]interface{}{"name": "string", "email": "string"},
+	}
+	if !reflect.DeepEqual(order["example"], want) {
+		t.Errorf("Order example = %v, want %v", order["example"], want)
+	}
+}
@@ -0,0 +1,44 @@
+package openapi
+
+import "strings"
+
+// Extensions holds vendor extensions (x-* keys) injected into the spec at
+// each level. Keys not starting with "x-" are ignored so extensions cannot
+// overwrite standard fields.
+type Extensions struct {
+	Root map[string]interface{}
+	Info map[string]interface{}
+	// Paths is keyed by path (e.g. "/users/{id}").
+	Paths map[string]map[string]interface{}
+	// Operations is keyed by "METHOD /path" (e.g. "GET /users").
+	Operations map[string]map[string]interface{}
+}
+
+// applyExtensions injects the configured vendor extensions into spec.
+func applyExtensions(spec map[string]interface{}, ext Extensions) {
+	setExtensions(spec, ext.Root)
+	if info, ok := spec["info"].(map[string]interface{}); ok {
+		setExtensions(info, ext.Info)
+	}
+
+	paths, _ := spec["paths"].(map[string]interface{})
+	for path, extensions := range ext.Paths {
+		if item, ok := paths[path].(map[string]interface{}); ok {
+			setExtensions(item, extensions)
+		}
+	}
+
+	if len(ext.Operations) > 0 {
+		forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+			setExtensions(op, ext.Operations[strings.ToUpper(method)+" "+path])
+		})
+	}
+}
+
+func setExtensions(target, extensions map[string]interface{}) {
+	for key, value := range extensions {
+		if strings.HasPrefix(key, "x-") {
+			target[key] = value
+		}
+	}
+}
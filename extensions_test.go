@@ -0,0 +1,101 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyExtensions(t *testing.T) {
+	newSpec := func() map[string]interface{} {
+		return map[string]interface{}{
+			"openapi": "3.0.0",
+			"info":    map[string]interface{}{"title": "API"},
+			"paths": map[string]interface{}{
+				"/users": map[string]interface{}{
+					"get":  map[string]interface{}{"summary": "List"},
+					"post": map[string]interface{}{"summary": "Create"},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		ext   Extensions
+		check func(t *testing.T, spec map[string]interface{})
+	}{
+		{
+			name: "root and info",
+			ext: Extensions{
+				Root: map[string]interface{}{"x-api-id": "orders"},
+				Info: map[string]interface{}{"x-audience": "partner"},
+			},
+			check: func(t *testing.T, spec map[string]interface{}) {
+				if spec["x-api-id"] != "orders" {
+					t.Errorf("x-api-id = %v, want orders", spec["x-api-id"])
+				}
+				if spec["info"].(map[string]interface{})["x-audience"] != "partner" {
+					t.Error("info should carry x-audience")
+				}
+			},
+		},
+		{
+			name: "path and operation",
+			ext: Extensions{
+				Paths:      map[string]map[string]interface{}{"/users": {"x-owner": "identity"}, "/missing": {"x-owner": "nobody"}},
+				Operations: map[string]map[string]interface{}{"GET /users": {"x-rate-limit": 100}},
+			},
+			check: func(t *testing.T, spec map[string]interface{}) {
+				paths := spec["paths"].(map[string]interface{})
+				users := paths["/users"].(map[string]interface{})
+				if users["x-owner"] != "identity" {
+					t.Error("/users should carry x-owner")
+				}
+				if _, ok := paths["/missing"]; ok {
+					t.Error("extensions should not create path items")
+				}
+				if users["get"].(map[string]interface{})["x-rate-limit"] != 100 {
+					t.Error("GET /users should carry x-rate-limit")
+				}
+				if _, ok := users["post"].(map[string]interface{})["x-rate-limit"]; ok {
+					t.Error("POST /users should not carry x-rate-limit")
+				}
+			},
+		},
+		{
+			name: "non extension keys ignored",
+			ext: Extensions{
+				Root: map[string]interface{}{"openapi": "9.9.9", "x-ok": true},
+				Info: map[string]interface{}{"title": "Hijacked"},
+			},
+			check: func(t *testing.T, spec map[string]interface{}) {
+				if spec["openapi"] != "3.0.0" {
+					t.Errorf("openapi = %v, should not be overwritten", spec["openapi"])
+				}
+				if spec["info"].(map[string]interface{})["title"] != "API" {
+					t.Error("info.title should not be overwritten")
+				}
+				if spec["x-ok"] != true {
+					t.Error("x-ok should be injected")
+				}
+			},
+		},
+		{
+			name: "empty",
+			ext:  Extensions{},
+			check: func(t *testing.T, spec map[string]interface{}) {
+				if !reflect.DeepEqual(spec, newSpec()) {
+					t.Errorf("spec = %v, want it unchanged", spec)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := newSpec()
+			applyExtensions(spec, tt.ext)
+			tt.check(t, spec)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Route is an endpoint served outside the DTO resources, documented by
+// Generate the same way route discovery documents a live app's routes.
+type Route struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// Generate builds the OpenAPI document for cfg without a running server: the
+// DTO resources of cfg.DTOsDirectory plus the given routes.
+func Generate(cfg GeneratorConfig, routes []Route) (map[string]interface{}, error) {
+	if err := validateRoutes(routes); err != nil {
+		return nil, err
+	}
+
+	app := fiber.New()
+	for _, route := range routes {
+		app.Add([]string{strings.ToUpper(route.Method)}, route.Path, func(c fiber.Ctx) error {
+			return nil
+		})
+	}
+	return generateOpenAPISpec(app, cfg)
+}
+
+// PublicSpec returns a copy of doc without the operations, schemas and
+// properties flagged x-internal, as served at the public spec path.
+func PublicSpec(doc map[string]interface{}) map[string]interface{} {
+	return filterInternalSpec(doc)
+}
+
+// Render marshals a generated document as "json" (indented) or "yaml", in the
+// requested OpenAPI version ("3.0" or "3.1").
+func Render(doc map[string]interface{}, format, version string) ([]byte, error) {
+	version, err := normalizeSpecVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	if version == SpecVersion31 {
+		if doc, err = convertToOpenAPI31(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	switch format {
+	case specFormatJSON:
+		return encodeIndentedJSON(doc)
+	case specFormatYAML:
+		return encodeYAML(doc)
+	}
+	return nil, fmt.Errorf("unsupported format %q (supported: %s, %s)", format, specFormatJSON, specFormatYAML)
+}
+
+// LoadRouteManifest reads a JSON array of {"method": ..., "path": ...} routes.
+func LoadRouteManifest(path string) ([]Route, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read route manifest: %w", err)
+	}
+
+	var routes []Route
+	if err := json.Unmarshal(raw, &routes); err != nil {
+		return nil, fmt.Errorf("invalid route manifest: %w", err)
+	}
+	if err := validateRoutes(routes); err != nil {
+		return nil, fmt.Errorf("invalid route manifest: %w", err)
+	}
+	return routes, nil
+}
+
+// validateRoutes checks that every route has a known HTTP method and a path
+// starting with /, naming the first bad entry.
+func validateRoutes(routes []Route) error {
+	for i, route := range routes {
+		if !slices.Contains(fiber.DefaultMethods, strings.ToUpper(route.Method)) {
+			return fmt.Errorf("route %d (%q %q): unknown HTTP method", i, route.Method, route.Path)
+		}
+		if !strings.HasPrefix(route.Path, "/") {
+			return fmt.Errorf("route %d (%q %q): path must start with /", i, route.Method, route.Path)
+		}
+	}
+	return nil
+}
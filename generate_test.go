@@ -0,0 +1,171 @@
+package openapi
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerate(t *testing.T) {
+	tempDir := t.TempDir()
+
+	userContent := `package dto
+
+type UserDTO struct {
+	ID   int64  ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "user.go"), []byte(userContent), 0644); err != nil {
+		t.Fatalf("Failed to create user.go: %v", err)
+	}
+
+	doc, err := Generate(GeneratorConfig{
+		DTOsDirectory: tempDir,
+		ServerURL:     "https://api.example.com",
+	}, []Route{
+		{Method: "get", Path: "/health"},
+		{Method: "POST", Path: "/auth/login"},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	paths := doc["paths"].(map[string]interface{})
+	tests := []struct {
+		path   string
+		method string
+	}{
+		{path: "/users", method: "get"},
+		{path: "/users/{id}", method: "delete"},
+		{path: "/health", method: "get"},
+		{path: "/auth/login", method: "post"},
+	}
+	for _, tt := range tests {
+		item, ok := paths[tt.path].(map[string]interface{})
+		if !ok {
+			t.Errorf("Generate() missing path %s", tt.path)
+			continue
+		}
+		if _, ok := item[tt.method]; !ok {
+			t.Errorf("Generate() missing %s %s", tt.method, tt.path)
+		}
+	}
+
+	servers := doc["servers"].([]map[string]string)
+	if servers[0]["url"] != "https://api.example.com" {
+		t.Errorf("servers[0].url = %v, want https://api.example.com", servers[0]["url"])
+	}
+}
+
+func TestGenerate_InvalidRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  []Route
+		wantErr string
+	}{
+		{name: "missing method", routes: []Route{{Path: "/health"}}, wantErr: `route 0 ("" "/health"): unknown HTTP method`},
+		{name: "unknown method", routes: []Route{{Method: "GET", Path: "/health"}, {Method: "FETCH", Path: "/jobs"}}, wantErr: `route 1 ("FETCH" "/jobs"): unknown HTTP method`},
+		{name: "relative path", routes: []Route{{Method: "GET", Path: "health"}}, wantErr: `route 0 ("GET" "health"): path must start with /`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Generate(GeneratorConfig{}, tt.routes)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Generate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	doc := map[string]interface{}{
+		"openapi": "3.0.0",
+		"paths":   map[string]interface{}{},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"User": map[string]interface{}{"type": "string", "nullable": true},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		format      string
+		version     string
+		unmarshal   func([]byte, interface{}) error
+		wantVersion string
+		wantErr     bool
+	}{
+		{name: "json 3.0", format: "json", version: "3.0", unmarshal: json.Unmarshal, wantVersion: "3.0.0"},
+		{name: "yaml 3.1", format: "yaml", version: "3.1", unmarshal: yaml.Unmarshal, wantVersion: "3.1.0"},
+		{name: "unsupported format", format: "xml", version: "3.0", wantErr: true},
+		{name: "unsupported version", format: "json", version: "2.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := Render(doc, tt.format, tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var got map[string]interface{}
+			if err := tt.unmarshal(raw, &got); err != nil {
+				t.Fatalf("Failed to parse rendered spec: %v", err)
+			}
+			if got["openapi"] != tt.wantVersion {
+				t.Errorf("openapi = %v, want %v", got["openapi"], tt.wantVersion)
+			}
+		})
+	}
+
+	if doc["openapi"] != "3.0.0" {
+		t.Error("Render() must not mutate the source document")
+	}
+}
+
+func TestLoadRouteManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr bool
+	}{
+		{name: "valid manifest", content: `[{"method": "GET", "path": "/health"}, {"method": "POST", "path": "/auth/login"}]`, want: 2},
+		{name: "invalid JSON", content: `{"method": "GET"`, wantErr: true},
+		{name: "missing method", content: `[{"path": "/health"}]`, wantErr: true},
+		{name: "unknown method", content: `[{"method": "FETCH", "path": "/health"}]`, wantErr: true},
+		{name: "relative path", content: `[{"method": "GET", "path": "health"}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "_")+".json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create manifest: %v", err)
+			}
+
+			got, err := LoadRouteManifest(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadRouteManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("LoadRouteManifest() returned %d routes, want %d", len(got), tt.want)
+			}
+		})
+	}
+
+	if _, err := LoadRouteManifest(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadRouteManifest() expected error for a missing file")
+	}
+}
@@ -2,21 +2,343 @@ package openapi
 
 import (
 	"fmt"
+	"io/fs"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/nicolasbonnici/gorest/plugin"
 )
 
+// Collection formats supported by GeneratorConfig.CollectionFormat.
+const (
+	CollectionFormatHydra      = "hydra"
+	CollectionFormatLinkHeader = "link-header"
+)
+
+// DTO variant roles recognized by GeneratorConfig.DTOVariants. DTOs matching
+// none of them are candidates for the main resource schema.
+const (
+	DTOVariantCreate = "create"
+	DTOVariantUpdate = "update"
+	DTOVariantList   = "list"
+)
+
+// Embedded struct handling supported by GeneratorConfig.EmbeddedStructs.
+const (
+	EmbeddedFlatten = "flatten"
+	EmbeddedAllOf   = "allOf"
+)
+
+// Schemas for interface{} fields supported by GeneratorConfig.InterfaceSchema.
+const (
+	InterfaceSchemaObject = "object"
+	InterfaceSchemaAny    = "any"
+)
+
 type GeneratorConfig struct {
 	DTOsDirectory      string
 	PluginRegistry     *plugin.PluginRegistry
 	PaginationLimit    int
 	PaginationMaxLimit int
-	ServerURL          string
-	Title              string
-	Version            string
-	Description        string
+	// OffsetMax caps the offset query parameter (deep pagination limit);
+	// zero leaves it unbounded.
+	OffsetMax   int
+	ServerURL   string
+	Title       string
+	Version     string
+	Description string
+	// Contact (name, url, email), License (name, url) and TermsOfService
+	// (a URL) are emitted under info when set.
+	Contact        map[string]string
+	License        map[string]string
+	TermsOfService string
+	// HealthSchema overrides the response schema documented for GET routes
+	// tagged System (e.g. /health). Nil uses the built-in health schema.
+	HealthSchema map[string]interface{}
+	// MainDTO maps a resource name (e.g. "user") to the exact DTO type name
+	// used as its main schema, bypassing the Create/Update heuristic.
+	MainDTO map[string]string
+	// MainDTOPatterns lists DTO name patterns (path.Match syntax, e.g.
+	// "*ResponseDTO") in priority order, selecting the main DTO of resources
+	// without a MainDTO entry before the variant heuristic.
+	MainDTOPatterns []string
+	// CountHead documents a HEAD operation on every collection that returns
+	// the total item count in the X-Total-Count header without a body.
+	CountHead bool
+	// ItemHead documents a HEAD operation on every item path, answering 200
+	// or 404 without a body to check whether a resource exists.
+	ItemHead bool
+	// HideFields lists property names, or path.Match patterns such as
+	// "internal*", dropped from every generated schema.
+	HideFields []string
+	// ResponseContentTypes lists extra media types (e.g. "application/ld+json")
+	// under which every JSON response body is also offered.
+	ResponseContentTypes []string
+	// AcceptHeader documents an Accept header parameter on operations that
+	// offer more than one response media type.
+	AcceptHeader bool
+	// LoadExamples attaches the contents of <resource>.example.json, read from
+	// DTOsDirectory, as the example of the resource's main schema.
+	LoadExamples bool
+	// RequestBodyDescription is the template describing resource request
+	// bodies; {resource} and {action} ("create" or "update") are substituted.
+	// Empty uses the message of the configured locale.
+	RequestBodyDescription string
+	// CollectionFormat selects how list responses are documented:
+	// CollectionFormatHydra (default) or CollectionFormatLinkHeader.
+	CollectionFormat string
+	// ConditionalGet documents ETag / If-None-Match revalidation (with a 304
+	// response) on item and collection GET operations.
+	ConditionalGet bool
+	// SharedExamples emits LoadExamples payloads once under
+	// components/examples and references them from operations by $ref
+	// instead of inlining them on the schema.
+	SharedExamples bool
+	// IDParamDescription is the template describing the id path parameter of
+	// item endpoints; {resource} is substituted (e.g. "The {resource}'s unique
+	// identifier"). Empty uses the message of the configured locale.
+	IDParamDescription string
+	// BooleansOptional leaves non-pointer bool fields out of the required set
+	// unless they carry validate:"required".
+	BooleansOptional bool
+	// MaxRequestBodySize, in bytes, documents a 413 response and an
+	// x-max-body-size extension on every operation accepting a request body.
+	MaxRequestBodySize int
+	// BatchCreate lists resource names exposing POST {base}/batch, which
+	// creates an array of items and answers 201 with the created list.
+	BatchCreate []string
+	// DTOsBaseDirectory anchors a relative DTOsDirectory; when empty it is
+	// resolved against the process working directory.
+	DTOsBaseDirectory string
+	// DTOsFS reads the DTO sources from a file system (e.g. go:embed-ed)
+	// instead of the disk, DTOsDirectory then naming a directory within it.
+	// Types imported from other packages are not resolved.
+	DTOsFS fs.FS
+	// UnauthorizedResponse documents a 401 on every operation requiring
+	// authentication, with the Error schema as body and the WWW-Authenticate
+	// challenges of its basic, bearer and oauth2 schemes.
+	UnauthorizedResponse bool
+	// ErrorSchema replaces the built-in Error schema ({"error": "..."})
+	// referenced by the 401 response body.
+	ErrorSchema map[string]interface{}
+	// TagDescriptions describes resource tags by name (e.g. "User") in the
+	// top-level tags block. DTO resources default to the main DTO's doc comment.
+	TagDescriptions map[string]string
+	// TagExternalDocs links tags by name to external documentation.
+	TagExternalDocs map[string]ExternalDocs
+	// TagOrder lists tag names in display order; unlisted tags follow, sorted
+	// by name.
+	TagOrder []string
+	// IdempotentOverrides replaces the x-idempotent flag derived from the HTTP
+	// method, keyed by "METHOD /path" (e.g. "POST /users/batch").
+	IdempotentOverrides map[string]bool
+	// InterfaceSchema selects how DTO fields typed interface{} (or any) are
+	// documented: InterfaceSchemaObject (default) emits {"type":"object"},
+	// InterfaceSchemaAny emits the open schema {} accepting any JSON value.
+	InterfaceSchema string
+	// RecursiveDTOs scans the subdirectories of DTOsDirectory as well.
+	RecursiveDTOs bool
+	// DirectoryTags tags the operations of a DTO resource after the
+	// subdirectory it lives in (dtos/billing -> "Billing") instead of its name.
+	DirectoryTags bool
+	// Security lists the alternative security requirements applied to every
+	// operation, any one of which grants access (OR semantics). Entries name a
+	// security scheme ("bearerAuth", "apiKey"); an empty entry allows anonymous
+	// access. Empty defaults to bearerAuth only.
+	Security []string
+	// APIKeyHeader registers the "apiKey" security scheme read from the named
+	// request header.
+	APIKeyHeader string
+	// DeprecatedOperations lists the operations flagged deprecated, as
+	// "METHOD /path" (e.g. "GET /v1/users/{id}").
+	DeprecatedOperations []string
+	// OperationSecurity overrides Security for single operations, keyed by
+	// "METHOD /path" (e.g. "GET /articles"). An empty list makes the
+	// operation public.
+	OperationSecurity map[string][]string
+	// SecurityScopes lists the scopes required by the oauth2 and
+	// openIdConnect schemes of SecuritySchemes, by scheme name, in every
+	// requirement naming them. Other schemes take no scopes.
+	SecurityScopes map[string][]string
+	// OperationScopes replaces those scopes for single operations, keyed by
+	// "METHOD /path" (e.g. "DELETE /orders/{id}": ["orders:write"]).
+	OperationScopes map[string][]string
+	// SecuritySchemes declares additional components.securitySchemes by
+	// name (apiKey, http basic/bearer, oauth2, openIdConnect). A scheme named
+	// like a built-in one ("bearerAuth", "apiKey") replaces it. Security and
+	// OperationSecurity refer to them by name.
+	SecuritySchemes map[string]SecurityScheme
+	// NoGlobalSecurity omits the top-level security requirement and the
+	// built-in bearerAuth scheme, for public APIs or APIs authenticated at
+	// the gateway. Schemes from APIKeyHeader and SecuritySchemes are still
+	// declared for OperationSecurity.
+	NoGlobalSecurity bool
+	// SynthesizeExamples attaches an example built from the property types to
+	// every component schema without one (e.g. from LoadExamples). Nested
+	// objects and referenced schemas are composed into the parent's example.
+	SynthesizeExamples bool
+	// DTOVariants overrides the name patterns (path.Match syntax) classifying
+	// the DTOs of a resource file by role; see the DTOVariant constants. The
+	// defaults are "*Create*", "*Update*" and "*ListItem*". A list variant
+	// becomes the <Schema>ListItem schema used by collection responses.
+	DTOVariants map[string]string
+	// DocsPath and SpecPath are the routes serving the documentation UI and
+	// the JSON spec (defaults "/openapi" and "/openapi.json"). Those routes,
+	// and the YAML spec and resource index derived from them, are left out of
+	// the spec.
+	DocsPath string
+	SpecPath string
+	// InternalOperations flags with x-internal the operations whose
+	// "METHOD /path" key matches one of these path.Match patterns (e.g.
+	// "* /admin/*"). InternalSchemas does the same for component schemas by
+	// name, and the openapi:"internal" struct tag for single properties.
+	// Flagged elements only appear in the internal flavor of the spec.
+	InternalOperations []string
+	InternalSchemas    []string
+	// InternalSpecPath is the route serving the internal spec, left out of
+	// the spec like the other documentation routes.
+	InternalSpecPath string
+	// Servers replaces the single server entry (ServerURL, or the host of
+	// the request when served live) with a fixed list, e.g. one per
+	// environment with server variables.
+	Servers []Server
+	// BasePath prefixes every resource path (e.g. "/api/v1") for deployments
+	// mounting the API under a prefix. Paths already carrying it are kept as
+	// is, and it is ignored when deriving tags and summaries of discovered
+	// routes.
+	BasePath string
+	// OperationIDStrategy names the operationId of every operation: one of
+	// the OperationID constants, OperationIDCamelCase when empty.
+	OperationIDStrategy string
+	// OperationTexts overrides the summary and description templates of
+	// generated operations, keyed by the OperationText constants.
+	OperationTexts map[string]OperationText
+	// Extensions injects vendor extensions (e.g. x-api-id, x-audience) at
+	// the root, info, path and operation levels.
+	Extensions Extensions
+	// Locale selects the message catalog of generated summaries,
+	// descriptions and parameter docs; DefaultLocale when empty.
+	Locale string
+	// Translations adds message catalogs, or overrides built-in messages,
+	// keyed by locale then message key (e.g. "list.summary", "param.limit").
+	Translations map[string]map[string]string
+	// PluralOverrides sets the plural of DTO resources by file name
+	// (person: staff), used for their paths and texts, before the built-in
+	// irregular nouns and suffix rules.
+	PluralOverrides map[string]string
+	// PathStyle formats the path segment of DTO resources: one of the
+	// PathStyle constants, the DTO file name as is when empty.
+	PathStyle string
+	// SingularPaths names DTO resource paths after the singular resource
+	// (/user/{id}) instead of its plural.
+	SingularPaths bool
+	// ResourcePaths sets the collection path of DTO resources by name
+	// (order_item: /order-items), bypassing PathStyle and SingularPaths.
+	ResourcePaths map[string]string
+	// EmbeddedStructs selects how embedded structs appear in DTO schemas:
+	// EmbeddedFlatten (default) promotes their fields, EmbeddedAllOf composes
+	// the schema from a component per embedded struct through allOf.
+	EmbeddedStructs string
+	// TypeFormats sets the format of DTO fields by Go type name (Email:
+	// email, uuid.UUID: uuid), for named types of the DTOs directory and
+	// external types alike.
+	TypeFormats map[string]string
+
+	// dtoRefs maps DTO type names to the component schema documenting them,
+	// so fields typed with another DTO are emitted as a $ref.
+	dtoRefs map[string]string
+	// namedTypes holds the named types of the DTOs directory, documented as
+	// their underlying type.
+	namedTypes map[string]namedType
+	// Resources are documented alongside the plugin registry ones, built by
+	// reflection from their models (see OpenAPIPlugin.RegisterResource).
+	Resources []plugin.OpenAPIResource
+	// XMLContent offers request and response bodies as application/xml
+	// alongside JSON, described by the xml struct tags of the fields.
+	XMLContent bool
+	// ResourceNames names the resources of DTO files by file name without
+	// extension (e.g. "user_dtos": "user"), instead of deriving it from the
+	// file or DTO type names.
+	ResourceNames map[string]string
+	// RouteOperations documents discovered routes by "METHOD /path",
+	// replacing their inferred summary, description, tags, request body and
+	// responses.
+	RouteOperations map[string]Operation
+	// GroupTags tags the discovered routes of a route group by its path
+	// prefix (/admin -> Admin). An empty tag strips the prefix before tagging
+	// the route by its next segment (/api/v1/users -> Users).
+	GroupTags map[string]string
+	// MountedApps are the apps mounted on the documented one
+	// (app.Use("/billing", billing)), whose routes are discovered under
+	// their mount path even before the server starts.
+	MountedApps []*fiber.App
+	// IncludeRoutes and ExcludeRoutes select the discovered routes by path:
+	// globs (/debug/*, /api/**) or regular expressions starting with ^. With
+	// include patterns, only the routes they match are documented; routes
+	// matched by an exclude pattern never are.
+	IncludeRoutes []string
+	ExcludeRoutes []string
+	// DiscoverMethods overrides by HTTP method (e.g. "HEAD": false) whether
+	// the discovered routes of that method are documented, such as the
+	// HEAD, OPTIONS, CONNECT and TRACE routes app.All registers. Unlisted
+	// methods are documented.
+	DiscoverMethods map[string]bool
+	// ResolveImportedTypes documents the types DTOs import from other
+	// packages by type-checking the DTO packages, which runs `go list` within
+	// the module enclosing DTOsDirectory: it needs the go command and the
+	// module sources, as at build time with the CLI. Without it imported
+	// types fall back to the string mapping.
+	ResolveImportedTypes bool
+}
+
+// Server is an entry of the spec's servers list. Its URL may hold {name}
+// placeholders described by Variables.
+type Server struct {
+	URL         string                    `json:"url"`
+	Description string                    `json:"description,omitempty"`
+	Variables   map[string]ServerVariable `json:"variables,omitempty"`
+}
+
+// ServerVariable describes a placeholder of a Server URL.
+type ServerVariable struct {
+	Default     string   `json:"default"`
+	Enum        []string `json:"enum,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// SecurityScheme is an entry of components.securitySchemes. Type is one of
+// "apiKey" (with Name and In: header, query or cookie), "http" (with Scheme,
+// e.g. basic or bearer), "oauth2" (with Flows) or "openIdConnect" (with
+// OpenIDConnectURL).
+type SecurityScheme struct {
+	Type             string      `json:"type"`
+	Description      string      `json:"description,omitempty"`
+	Name             string      `json:"name,omitempty"`
+	In               string      `json:"in,omitempty"`
+	Scheme           string      `json:"scheme,omitempty"`
+	BearerFormat     string      `json:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows `json:"flows,omitempty"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty"`
+}
+
+// OAuthFlows lists the OAuth2 flows supported by an oauth2 SecurityScheme.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow describes one OAuth2 flow and the scopes it grants, keyed by
+// scope name with their description.
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
 }
 
 func generateOpenAPISpec(router fiber.Router, cfg GeneratorConfig) (map[string]interface{}, error) {
@@ -25,17 +347,20 @@ func generateOpenAPISpec(router fiber.Router, cfg GeneratorConfig) (map[string]i
 		return nil, err
 	}
 
-	spec["servers"] = []map[string]string{
-		{"url": cfg.ServerURL, "description": "Development server"},
+	if _, ok := spec["servers"]; !ok {
+		spec["servers"] = []map[string]string{
+			{"url": cfg.ServerURL, "description": "Development server"},
+		}
 	}
 
 	return spec, nil
 }
 
 // buildStaticSpec assembles every part of the spec that is invariant for a
-// given boot (paths, schemas, security). The request-dependent servers block is
-// injected by the caller so this result can be built once and reused across
-// requests regardless of the incoming Host.
+// given boot (paths, schemas, security). Unless configured through
+// cfg.Servers, the request-dependent servers block is injected by the caller
+// so this result can be built once and reused across requests regardless of
+// the incoming Host.
 func buildStaticSpec(router fiber.Router, cfg GeneratorConfig) (map[string]interface{}, error) {
 	paths := map[string]interface{}{}
 	components := map[string]interface{}{
@@ -43,191 +368,416 @@ func buildStaticSpec(router fiber.Router, cfg GeneratorConfig) (map[string]inter
 	}
 
 	resourcePaths := make(map[string]bool)
+	tagDescriptions := make(map[string]string)
 
-	var pluginResources []plugin.OpenAPIResource
-	if cfg.PluginRegistry != nil {
-		pluginResources = loadResourcesFromPlugins(cfg.PluginRegistry)
-	}
+	pluginResources := append(loadResourcesFromPlugins(cfg.PluginRegistry), cfg.Resources...)
 
 	if len(pluginResources) > 0 {
-		for _, resource := range pluginResources {
-			schemaName := strings.ToUpper(resource.Name[:1]) + resource.Name[1:]
+		addPluginResources(pluginResources, cfg, paths, components["schemas"].(map[string]interface{}), resourcePaths)
+	} else if cfg.DTOsFS != nil || cfg.DTOsDirectory != "" {
+		if err := addDTOResources(cfg, paths, components, resourcePaths, tagDescriptions); err != nil {
+			return nil, err
+		}
+	}
 
-			if resource.ResponseModel != nil {
-				schema := buildSchemaFromModel(resource.ResponseModel)
-				components["schemas"].(map[string]interface{})[schemaName] = schema
-			}
+	if cfg.SynthesizeExamples {
+		sharedExamples, _ := components["examples"].(map[string]interface{})
+		synthesizeSchemaExamples(components["schemas"].(map[string]interface{}), sharedExamples)
+	}
 
-			if resource.CreateModel != nil {
-				createSchemaName := "Create" + schemaName + "Request"
-				schema := buildSchemaFromModel(resource.CreateModel)
-				components["schemas"].(map[string]interface{})[createSchemaName] = schema
-			}
+	// Route discovery requires *fiber.App for GetRoutes() method
+	// Try to type-assert if router is the full app
+	var names map[string]string
+	if app, ok := router.(*fiber.App); ok {
+		discoveredRoutes := discoverNonResourceRoutes(app, resourcePaths, cfg)
+		for path, methods := range discoveredRoutes {
+			paths[path] = methods
+		}
+		names = routeNames(appRoutes(app, cfg.MountedApps))
+	}
 
-			if resource.UpdateModel != nil {
-				updateSchemaName := "Update" + schemaName + "Request"
-				schema := buildSchemaFromModel(resource.UpdateModel)
-				components["schemas"].(map[string]interface{})[updateSchemaName] = schema
-			}
+	applyRouteOperations(paths, cfg.RouteOperations, newModelSchemaBuilder(modelRefs(pluginResources)))
 
-			base := resource.BasePath
-			resourcePaths[base] = true
-			resourcePaths[base+"/:id"] = true
+	applyContentNegotiation(paths, cfg)
+	applyMaxRequestBodySize(paths, cfg.MaxRequestBodySize)
+	applyIdempotency(paths, cfg.IdempotentOverrides)
+	applyOperationSecurity(paths, cfg.OperationSecurity, cfg.SecurityScopes)
+	applyDeprecatedOperations(paths, cfg.DeprecatedOperations)
+	applyOperationIDs(paths, cfg, resourcePaths, names)
 
-			paths[base] = buildCollectionEndpointsFromResource(resource, schemaName, cfg)
-			paths[base+"/{id}"] = buildItemEndpointsFromResource(resource, schemaName)
+	var globalSecurity []map[string]interface{}
+	if !cfg.NoGlobalSecurity {
+		security := cfg.Security
+		if len(security) == 0 {
+			security = []string{"bearerAuth"}
 		}
-	} else if cfg.DTOsDirectory != "" {
-		resourceDTOs, err := loadResourceDTOs(cfg.DTOsDirectory)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load DTOs: %w", err)
+		globalSecurity = buildSecurityRequirements(security, cfg.SecurityScopes)
+	}
+	applyOperationScopes(paths, globalSecurity, cfg.OperationScopes, scopedSecuritySchemes(cfg.SecuritySchemes))
+
+	if cfg.UnauthorizedResponse {
+		errorSchema := cfg.ErrorSchema
+		if errorSchema == nil {
+			errorSchema = defaultErrorSchema()
 		}
+		components["schemas"].(map[string]interface{})["Error"] = errorSchema
+		applyUnauthorizedResponses(paths, globalSecurity, authenticationChallenges(cfg))
+	}
 
-		for _, resource := range resourceDTOs {
-			mainDTO := resource.getMainDTO()
-			if mainDTO == nil {
-				continue
-			}
+	securitySchemes := map[string]interface{}{}
+	if !cfg.NoGlobalSecurity {
+		securitySchemes["bearerAuth"] = map[string]interface{}{
+			"type":         "http",
+			"scheme":       "bearer",
+			"bearerFormat": "JWT",
+			"description":  "JWT authentication token",
+		}
+	}
+	if cfg.APIKeyHeader != "" {
+		securitySchemes["apiKey"] = map[string]interface{}{
+			"type":        "apiKey",
+			"in":          "header",
+			"name":        cfg.APIKeyHeader,
+			"description": "API key authentication",
+		}
+	}
+	for name, scheme := range cfg.SecuritySchemes {
+		securitySchemes[name] = scheme
+	}
+	if len(securitySchemes) > 0 {
+		components["securitySchemes"] = securitySchemes
+	}
 
-			schemaName := strings.ToUpper(resource.Name[:1]) + resource.Name[1:]
-			properties := buildSchemaPropertiesFromDTO(mainDTO.Fields)
-			required := getRequiredFieldsFromDTO(mainDTO.Fields)
+	applyInternalMarkers(paths, components["schemas"].(map[string]interface{}), cfg.InternalOperations, cfg.InternalSchemas)
+
+	info := map[string]interface{}{
+		"title":       cfg.Title,
+		"version":     cfg.Version,
+		"description": cfg.Description,
+	}
+	if len(cfg.Contact) > 0 {
+		info["contact"] = cfg.Contact
+	}
+	if len(cfg.License) > 0 {
+		info["license"] = cfg.License
+	}
+	if cfg.TermsOfService != "" {
+		info["termsOfService"] = cfg.TermsOfService
+	}
 
-			schema := map[string]interface{}{
-				"type":       "object",
-				"properties": properties,
+	spec := map[string]interface{}{
+		"openapi":    "3.0.0",
+		"info":       info,
+		"paths":      paths,
+		"components": components,
+	}
+	if globalSecurity != nil {
+		spec["security"] = globalSecurity
+	}
+	if len(cfg.Servers) > 0 {
+		spec["servers"] = cfg.Servers
+	}
+
+	for tag, description := range cfg.TagDescriptions {
+		tagDescriptions[tag] = description
+	}
+	if tags := buildTags(paths, tagDescriptions, cfg.TagExternalDocs, cfg.TagOrder); len(tags) > 0 {
+		spec["tags"] = tags
+	}
+
+	applyExtensions(spec, cfg.Extensions)
+
+	return spec, nil
+}
+
+// addPluginResources documents the plugin and registered resources: the
+// schemas of their models and their collection, item and batch paths.
+func addPluginResources(resources []plugin.OpenAPIResource, cfg GeneratorConfig, paths, schemas map[string]interface{}, resourcePaths map[string]bool) {
+	models := newModelSchemaBuilder(modelRefs(resources))
+	for _, resource := range resources {
+		schemaName := strings.ToUpper(resource.Name[:1]) + resource.Name[1:]
+
+		if resource.ResponseModel != nil {
+			schema := models.build(resource.ResponseModel)
+			hideSchemaFields(schema, cfg.HideFields)
+			schemas[schemaName] = schema
+		}
+
+		if resource.CreateModel != nil {
+			createSchemaName := "Create" + schemaName + "Request"
+			schema := models.build(resource.CreateModel)
+			hideSchemaFields(schema, cfg.HideFields)
+			stripReadOnlyProperties(schema)
+			schemas[createSchemaName] = schema
+		}
+
+		if resource.UpdateModel != nil {
+			updateSchemaName := "Update" + schemaName + "Request"
+			schema := models.build(resource.UpdateModel)
+			hideSchemaFields(schema, cfg.HideFields)
+			stripReadOnlyProperties(schema)
+			schemas[updateSchemaName] = schema
+		}
+
+		base := joinBasePath(cfg.BasePath, resource.BasePath)
+		resourcePaths[base] = true
+		resourcePaths[base+"/:id"] = true
+
+		paths[base] = buildCollectionEndpointsFromResource(resource, schemaName, cfg)
+		paths[base+"/{id}"] = buildItemEndpointsFromResource(resource, schemaName, cfg)
+
+		if resource.CreateModel != nil && slices.Contains(cfg.BatchCreate, resource.Name) {
+			resourcePaths[base+"/batch"] = true
+			paths[base+"/batch"] = buildBatchCreateEndpoints(resource.Name, resource.PluralName, base, "Create"+schemaName+"Request", schemaName, pluginResourceTags(resource, schemaName), cfg)
+		}
+	}
+}
+
+// addDTOResources documents the resources parsed from the DTOs directory: the
+// schemas of their DTOs, their examples and their collection, item and batch
+// paths. Tag descriptions taken from the main DTOs are added to
+// tagDescriptions.
+func addDTOResources(cfg GeneratorConfig, paths, components map[string]interface{}, resourcePaths map[string]bool, tagDescriptions map[string]string) error {
+	schemas := components["schemas"].(map[string]interface{})
+	source := dtoSourceFor(cfg)
+	resourceDTOs, err := loadResourceDTOsFrom(source, cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load DTOs: %w", err)
+	}
+	cfg.namedTypes, err = loadNamedTypesFrom(source, cfg.RecursiveDTOs, cfg.ResolveImportedTypes)
+	if err != nil {
+		return fmt.Errorf("failed to load DTOs: %w", err)
+	}
+	resolveEmbeddedImports(resourceDTOs, cfg.namedTypes)
+
+	examples := make(map[string]interface{})
+	variants := dtoVariantPatterns(cfg.DTOVariants)
+	refs, nestedSchemas, err := nestedDTOSchemas(resourceDTOs, cfg.MainDTO, variants, cfg.MainDTOPatterns, cfg.EmbeddedStructs == EmbeddedAllOf)
+	if err != nil {
+		return fmt.Errorf("failed to load DTOs: %w", err)
+	}
+	cfg.dtoRefs = refs
+	for name, dto := range nestedSchemas {
+		schemas[name] = buildSchemaFromDTO(dto.Fields, cfg)
+	}
+	for _, resource := range resourceDTOs {
+		schemaName := strings.ToUpper(resource.Name[:1]) + resource.Name[1:]
+
+		if listDTO := resource.variantDTO(DTOVariantList, variants); listDTO != nil {
+			schemas[schemaName+"ListItem"] = buildSchemaFromDTO(dtoContextFields(listDTO.Fields, dtoContextRead), cfg)
+		}
+		for _, role := range []string{DTOVariantCreate, DTOVariantUpdate} {
+			if fields, ok := requestDTOFields(resource, role, cfg); ok {
+				schema := buildSchemaFromDTO(fields, cfg)
+				stripReadOnlyProperties(schema)
+				schemas[requestSchemaName(role, schemaName)] = schema
 			}
+		}
 
-			if len(required) > 0 {
-				schema["required"] = required
+		mainDTO := resource.resolveMainDTO(cfg.MainDTO[resource.Name], variants, cfg.MainDTOPatterns)
+		if mainDTO == nil {
+			continue
+		}
+
+		if tag := resourceTag(resource, schemaName, cfg); tag == schemaName && mainDTO.Description != "" {
+			tagDescriptions[tag] = mainDTO.Description
+		}
+
+		schema := buildSchemaFromDTO(dtoContextFields(mainDTO.Fields, dtoContextRead), cfg)
+
+		if cfg.LoadExamples {
+			example, err := loadResourceExampleFrom(source, resource.Dir, resource.Name)
+			if err != nil {
+				return err
+			}
+			if example != nil && cfg.SharedExamples {
+				examples[schemaName] = map[string]interface{}{
+					"summary": "Example " + resource.Name,
+					"value":   example,
+				}
+			} else if example != nil {
+				schema["example"] = example
 			}
+		}
+
+		schemas[schemaName] = schema
+	}
+
+	for _, resource := range resourceDTOs {
+		schemaName := strings.ToUpper(resource.Name[:1]) + resource.Name[1:]
+		base := joinBasePath(cfg.BasePath, resourcePath(resource, cfg))
+
+		resourcePaths[base] = true
+		resourcePaths[base+"/:id"] = true
+
+		paths[base] = buildCollectionEndpoints(resource, schemaName, cfg)
+		paths[base+"/{id}"] = buildItemEndpoints(resource, schemaName, cfg)
 
-			components["schemas"].(map[string]interface{})[schemaName] = schema
+		if slices.Contains(cfg.BatchCreate, resource.Name) {
+			resourcePaths[base+"/batch"] = true
+			paths[base+"/batch"] = buildBatchCreateEndpoints(resource.Name, resource.PluralName, base, resourceRequestSchema(resource, DTOVariantCreate, schemaName, cfg), schemaName, []string{resourceTag(resource, schemaName, cfg)}, cfg)
 		}
 
-		for _, resource := range resourceDTOs {
-			schemaName := strings.ToUpper(resource.Name[:1]) + resource.Name[1:]
-			base := "/" + resource.PluralName
+		if _, ok := examples[schemaName]; ok {
+			referenceSharedExample(paths[base].(map[string]interface{}), schemaName)
+			referenceSharedExample(paths[base+"/{id}"].(map[string]interface{}), schemaName)
+		}
+	}
+
+	if len(examples) > 0 {
+		components["examples"] = examples
+	}
+	return nil
+}
 
-			resourcePaths[base] = true
-			resourcePaths[base+"/:id"] = true
+// resourceTag returns the tag grouping the operations of a DTO resource: its
+// schema name, or its title-cased subdirectory when DirectoryTags is set.
+func resourceTag(resource resourceDTOs, schemaName string, cfg GeneratorConfig) string {
+	if !cfg.DirectoryTags || resource.Dir == "" {
+		return schemaName
+	}
+
+	segments := strings.Split(resource.Dir, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ToUpper(segment[:1]) + segment[1:]
+	}
+	return strings.Join(segments, "/")
+}
+
+// ExternalDocs links a tag to additional documentation.
+type ExternalDocs struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
 
-			paths[base] = buildCollectionEndpoints(resource, schemaName, cfg)
-			paths[base+"/{id}"] = buildItemEndpoints(resource, schemaName)
+// buildTags lists the tags of the top-level tags block: every tag used by an
+// operation or given metadata, those named by order first and in that order,
+// the rest sorted by name.
+func buildTags(paths map[string]interface{}, descriptions map[string]string, externalDocs map[string]ExternalDocs, order []string) []map[string]interface{} {
+	names := make(map[string]bool)
+	forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+		tags, _ := op["tags"].([]string)
+		for _, tag := range tags {
+			names[tag] = true
 		}
+	})
+	for name := range descriptions {
+		names[name] = true
+	}
+	for name := range externalDocs {
+		names[name] = true
 	}
 
-	// Route discovery requires *fiber.App for GetRoutes() method
-	// Try to type-assert if router is the full app
-	if app, ok := router.(*fiber.App); ok {
-		discoveredRoutes := discoverNonResourceRoutes(app, resourcePaths)
-		for path, methods := range discoveredRoutes {
-			paths[path] = methods
+	var ordered []string
+	for _, name := range order {
+		if names[name] {
+			ordered = append(ordered, name)
+			delete(names, name)
 		}
 	}
+	ordered = append(ordered, sortedKeys(names)...)
 
-	components["securitySchemes"] = map[string]interface{}{
-		"bearerAuth": map[string]interface{}{
-			"type":         "http",
-			"scheme":       "bearer",
-			"bearerFormat": "JWT",
-			"description":  "JWT authentication token",
-		},
+	tags := make([]map[string]interface{}, 0, len(ordered))
+	for _, name := range ordered {
+		tag := map[string]interface{}{"name": name}
+		if description := descriptions[name]; description != "" {
+			tag["description"] = description
+		}
+		// the url of externalDocs is required, an entry without one is dropped
+		if docs := externalDocs[name]; docs.URL != "" {
+			tag["externalDocs"] = docs
+		}
+		tags = append(tags, tag)
 	}
+	return tags
+}
 
-	return map[string]interface{}{
-		"openapi": "3.0.0",
-		"info": map[string]interface{}{
-			"title":       cfg.Title,
-			"version":     cfg.Version,
-			"description": cfg.Description,
-		},
-		"paths":      paths,
-		"components": components,
-		"security": []map[string]interface{}{
-			{"bearerAuth": []string{}},
-		},
-	}, nil
+// requestSchemaName names the component of a create or update request body:
+// CreateUserRequest, UpdateUserRequest.
+func requestSchemaName(role, schemaName string) string {
+	return strings.ToUpper(role[:1]) + role[1:] + schemaName + "Request"
+}
+
+// resourceRequestSchema returns the component documenting the create or
+// update request body of a DTO resource: its own request schema when it has
+// one, the resource schema otherwise.
+func resourceRequestSchema(resource resourceDTOs, role, schemaName string, cfg GeneratorConfig) string {
+	if _, ok := requestDTOFields(resource, role, cfg); !ok {
+		return schemaName
+	}
+	return requestSchemaName(role, schemaName)
+}
+
+// requestDTOFields returns the fields of the create or update request schema
+// of a DTO resource: those of its Create or Update DTO when it declares one,
+// or those of its main DTO in that dto tag context when its fields carry dto
+// tags. ok is false when the body is documented by the resource schema.
+func requestDTOFields(resource resourceDTOs, role string, cfg GeneratorConfig) (fields []structField, ok bool) {
+	variants := dtoVariantPatterns(cfg.DTOVariants)
+	if dto := resource.variantDTO(role, variants); dto != nil {
+		return dtoContextFields(dto.Fields, role), true
+	}
+	if mainDTO := resource.resolveMainDTO(cfg.MainDTO[resource.Name], variants, cfg.MainDTOPatterns); mainDTO != nil && hasDTOContexts(mainDTO.Fields) {
+		return dtoContextFields(mainDTO.Fields, role), true
+	}
+	return nil, false
 }
 
 func buildCollectionEndpoints(resource resourceDTOs, schemaName string, cfg GeneratorConfig) map[string]interface{} {
-	return map[string]interface{}{
+	tags := []string{resourceTag(resource, schemaName, cfg)}
+	texts := resourceTexts(cfg, resource.Name, resource.PluralName)
+
+	itemSchema := schemaName
+	if resource.variantDTO(DTOVariantList, dtoVariantPatterns(cfg.DTOVariants)) != nil {
+		itemSchema = schemaName + "ListItem"
+	}
+
+	endpoints := map[string]interface{}{
 		"get": map[string]interface{}{
-			"summary":     "List " + resource.PluralName,
-			"description": "Retrieve a list of " + resource.PluralName,
-			"tags":        []string{schemaName},
+			"summary":     texts.summary(OperationTextList),
+			"description": texts.description(OperationTextList),
+			"tags":        tags,
 			"parameters": []map[string]interface{}{
 				{
 					"name":        "limit",
 					"in":          "query",
-					"description": fmt.Sprintf("Maximum number of items to return (default: %d, max: %d)", cfg.PaginationLimit, cfg.PaginationMaxLimit),
+					"description": limitParamDescription(cfg),
 					"schema":      map[string]interface{}{"type": "integer", "default": cfg.PaginationLimit, "maximum": cfg.PaginationMaxLimit},
 				},
 				{
 					"name":        "offset",
 					"in":          "query",
-					"description": "Number of items to skip (default: 0)",
-					"schema":      map[string]interface{}{"type": "integer", "default": 0, "minimum": 0},
+					"description": message(cfg, messageOffsetParam),
+					"schema":      offsetParamSchema(cfg),
 				},
 				{
 					"name":        "count",
 					"in":          "query",
-					"description": "Include total count in response (adds hydra:totalItems field)",
+					"description": message(cfg, messageCountParam),
 					"schema":      map[string]interface{}{"type": "boolean", "default": false},
 				},
 				{
 					"name":        "expand",
 					"in":          "query",
-					"description": "Comma-separated list of relations to expand",
+					"description": message(cfg, messageExpandParam),
 					"schema":      map[string]string{"type": "string"},
 				},
 			},
 			"responses": map[string]interface{}{
-				"200": map[string]interface{}{
-					"description": "Hydra paginated collection",
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]interface{}{
-								"type": "object",
-								"properties": map[string]interface{}{
-									"@context":         map[string]string{"type": "string"},
-									"@id":              map[string]string{"type": "string"},
-									"@type":            map[string]string{"type": "string", "example": "hydra:Collection"},
-									"hydra:totalItems": map[string]interface{}{"type": "integer", "description": "Total count (only present if count=true)"},
-									"hydra:member": map[string]interface{}{
-										"type": "array",
-										"items": map[string]string{
-											"$ref": "#/components/schemas/" + schemaName,
-										},
-									},
-									"hydra:view": map[string]interface{}{
-										"type": "object",
-										"properties": map[string]interface{}{
-											"@id":            map[string]string{"type": "string"},
-											"@type":          map[string]string{"type": "string"},
-											"hydra:first":    map[string]string{"type": "string"},
-											"hydra:last":     map[string]string{"type": "string"},
-											"hydra:previous": map[string]string{"type": "string"},
-											"hydra:next":     map[string]string{"type": "string"},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
+				"200": buildCollectionResponse(itemSchema, cfg),
 			},
 		},
 		"post": map[string]interface{}{
-			"summary":     "Create " + resource.Name,
-			"description": "Create a new " + resource.Name,
-			"tags":        []string{schemaName},
+			"summary":     texts.summary(OperationTextCreate),
+			"description": texts.description(OperationTextCreate),
+			"tags":        tags,
 			"requestBody": map[string]interface{}{
-				"required": true,
+				"required":    true,
+				"description": requestBodyDescription(resource.Name, "create", cfg),
 				"content": map[string]interface{}{
 					"application/json": map[string]interface{}{
 						"schema": map[string]string{
-							"$ref": "#/components/schemas/" + schemaName,
+							"$ref": "#/components/schemas/" + resourceRequestSchema(resource, DTOVariantCreate, schemaName, cfg),
 						},
 					},
 				},
@@ -246,20 +796,33 @@ func buildCollectionEndpoints(resource resourceDTOs, schemaName string, cfg Gene
 			},
 		},
 	}
+
+	if cfg.ConditionalGet {
+		addConditionalGet(endpoints["get"].(map[string]interface{}))
+	}
+
+	if cfg.CountHead {
+		endpoints["head"] = buildCountHeadEndpoint(resource.Name, resource.PluralName, tags, cfg)
+	}
+
+	return endpoints
 }
 
-func buildItemEndpoints(resource resourceDTOs, schemaName string) map[string]interface{} {
-	return map[string]interface{}{
+func buildItemEndpoints(resource resourceDTOs, schemaName string, cfg GeneratorConfig) map[string]interface{} {
+	tags := []string{resourceTag(resource, schemaName, cfg)}
+	texts := resourceTexts(cfg, resource.Name, resource.PluralName)
+
+	endpoints := map[string]interface{}{
 		"get": map[string]interface{}{
-			"summary":     "Get " + resource.Name + " by ID",
-			"description": "Retrieve a single " + resource.Name + " by ID",
-			"tags":        []string{schemaName},
+			"summary":     texts.summary(OperationTextGet),
+			"description": texts.description(OperationTextGet),
+			"tags":        tags,
 			"parameters": []map[string]interface{}{
 				{
 					"name":        "id",
 					"in":          "path",
 					"required":    true,
-					"description": "Resource ID",
+					"description": idParamDescription(resource.Name, cfg),
 					"schema":      map[string]string{"type": "string"},
 				},
 			},
@@ -280,24 +843,25 @@ func buildItemEndpoints(resource resourceDTOs, schemaName string) map[string]int
 			},
 		},
 		"put": map[string]interface{}{
-			"summary":     "Update " + resource.Name + " by ID",
-			"description": "Update an existing " + resource.Name,
-			"tags":        []string{schemaName},
+			"summary":     texts.summary(OperationTextUpdate),
+			"description": texts.description(OperationTextUpdate),
+			"tags":        tags,
 			"parameters": []map[string]interface{}{
 				{
 					"name":        "id",
 					"in":          "path",
 					"required":    true,
-					"description": "Resource ID",
+					"description": idParamDescription(resource.Name, cfg),
 					"schema":      map[string]string{"type": "string"},
 				},
 			},
 			"requestBody": map[string]interface{}{
-				"required": true,
+				"required":    true,
+				"description": requestBodyDescription(resource.Name, "update", cfg),
 				"content": map[string]interface{}{
 					"application/json": map[string]interface{}{
 						"schema": map[string]string{
-							"$ref": "#/components/schemas/" + schemaName,
+							"$ref": "#/components/schemas/" + resourceRequestSchema(resource, DTOVariantUpdate, schemaName, cfg),
 						},
 					},
 				},
@@ -319,15 +883,15 @@ func buildItemEndpoints(resource resourceDTOs, schemaName string) map[string]int
 			},
 		},
 		"delete": map[string]interface{}{
-			"summary":     "Delete " + resource.Name + " by ID",
-			"description": "Delete an existing " + resource.Name,
-			"tags":        []string{schemaName},
+			"summary":     texts.summary(OperationTextDelete),
+			"description": texts.description(OperationTextDelete),
+			"tags":        tags,
 			"parameters": []map[string]interface{}{
 				{
 					"name":        "id",
 					"in":          "path",
 					"required":    true,
-					"description": "Resource ID",
+					"description": idParamDescription(resource.Name, cfg),
 					"schema":      map[string]string{"type": "string"},
 				},
 			},
@@ -341,42 +905,59 @@ func buildItemEndpoints(resource resourceDTOs, schemaName string) map[string]int
 			},
 		},
 	}
+
+	if cfg.ConditionalGet {
+		addConditionalGet(endpoints["get"].(map[string]interface{}))
+	}
+
+	if cfg.ItemHead {
+		endpoints["head"] = buildItemHeadEndpoint(resource.Name, resource.PluralName, tags, cfg)
+	}
+
+	return endpoints
 }
 
-func buildCollectionEndpointsFromResource(resource plugin.OpenAPIResource, schemaName string, cfg GeneratorConfig) map[string]interface{} {
-	tags := resource.Tags
-	if len(tags) == 0 {
-		tags = []string{schemaName}
+// pluginResourceTags returns the tags of the operations of a plugin or
+// registered resource: its own, or its schema name.
+func pluginResourceTags(resource plugin.OpenAPIResource, schemaName string) []string {
+	if len(resource.Tags) > 0 {
+		return resource.Tags
 	}
+	return []string{schemaName}
+}
+
+func buildCollectionEndpointsFromResource(resource plugin.OpenAPIResource, schemaName string, cfg GeneratorConfig) map[string]interface{} {
+	tags := pluginResourceTags(resource, schemaName)
+	texts := resourceTexts(cfg, resource.Name, resource.PluralName)
 
 	description := resource.Description
 	if description == "" {
-		description = "Retrieve a list of " + resource.PluralName
+		description = texts.description(OperationTextList)
 	}
 
 	params := []map[string]interface{}{
 		{
 			"name":        "limit",
 			"in":          "query",
-			"description": fmt.Sprintf("Maximum number of items to return (default: %d, max: %d)", cfg.PaginationLimit, cfg.PaginationMaxLimit),
+			"description": limitParamDescription(cfg),
 			"schema":      map[string]interface{}{"type": "integer", "default": cfg.PaginationLimit, "maximum": cfg.PaginationMaxLimit},
 		},
 		{
 			"name":        "offset",
 			"in":          "query",
-			"description": "Number of items to skip (default: 0)",
-			"schema":      map[string]interface{}{"type": "integer", "default": 0, "minimum": 0},
+			"description": message(cfg, messageOffsetParam),
+			"schema":      offsetParamSchema(cfg),
 		},
 		{
 			"name":        "count",
 			"in":          "query",
-			"description": "Include total count in response (adds hydra:totalItems field)",
+			"description": message(cfg, messageCountParam),
 			"schema":      map[string]interface{}{"type": "boolean", "default": false},
 		},
 		{
 			"name":        "expand",
 			"in":          "query",
-			"description": "Comma-separated list of relations to expand",
+			"description": message(cfg, messageExpandParam),
 			"schema":      map[string]string{"type": "string"},
 		},
 	}
@@ -397,44 +978,12 @@ func buildCollectionEndpointsFromResource(resource plugin.OpenAPIResource, schem
 
 	endpoints := map[string]interface{}{
 		"get": map[string]interface{}{
-			"summary":     "List " + resource.PluralName,
+			"summary":     texts.summary(OperationTextList),
 			"description": description,
 			"tags":        tags,
 			"parameters":  params,
 			"responses": map[string]interface{}{
-				"200": map[string]interface{}{
-					"description": "Hydra paginated collection",
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]interface{}{
-								"type": "object",
-								"properties": map[string]interface{}{
-									"@context":         map[string]string{"type": "string"},
-									"@id":              map[string]string{"type": "string"},
-									"@type":            map[string]string{"type": "string", "example": "hydra:Collection"},
-									"hydra:totalItems": map[string]interface{}{"type": "integer", "description": "Total count (only present if count=true)"},
-									"hydra:member": map[string]interface{}{
-										"type": "array",
-										"items": map[string]string{
-											"$ref": "#/components/schemas/" + schemaName,
-										},
-									},
-									"hydra:view": map[string]interface{}{
-										"type": "object",
-										"properties": map[string]interface{}{
-											"@id":            map[string]string{"type": "string"},
-											"@type":          map[string]string{"type": "string"},
-											"hydra:first":    map[string]string{"type": "string"},
-											"hydra:last":     map[string]string{"type": "string"},
-											"hydra:previous": map[string]string{"type": "string"},
-											"hydra:next":     map[string]string{"type": "string"},
-										},
-									},
-								},
-							},
-						},
-					},
-				},
+				"200": buildCollectionResponse(schemaName, cfg),
 			},
 		},
 	}
@@ -442,11 +991,12 @@ func buildCollectionEndpointsFromResource(resource plugin.OpenAPIResource, schem
 	if resource.CreateModel != nil {
 		createSchemaRef := "Create" + schemaName + "Request"
 		endpoints["post"] = map[string]interface{}{
-			"summary":     "Create " + resource.Name,
-			"description": "Create a new " + resource.Name,
+			"summary":     texts.summary(OperationTextCreate),
+			"description": texts.description(OperationTextCreate),
 			"tags":        tags,
 			"requestBody": map[string]interface{}{
-				"required": true,
+				"required":    true,
+				"description": requestBodyDescription(resource.Name, "create", cfg),
 				"content": map[string]interface{}{
 					"application/json": map[string]interface{}{
 						"schema": map[string]string{
@@ -470,26 +1020,226 @@ func buildCollectionEndpointsFromResource(resource plugin.OpenAPIResource, schem
 		}
 	}
 
+	if cfg.ConditionalGet {
+		addConditionalGet(endpoints["get"].(map[string]interface{}))
+	}
+
+	if cfg.CountHead {
+		endpoints["head"] = buildCountHeadEndpoint(resource.Name, resource.PluralName, tags, cfg)
+	}
+
 	return endpoints
 }
 
-func buildItemEndpointsFromResource(resource plugin.OpenAPIResource, schemaName string) map[string]interface{} {
-	tags := resource.Tags
-	if len(tags) == 0 {
-		tags = []string{schemaName}
+// buildCollectionResponse documents the list 200 response in the configured
+// CollectionFormat: a Hydra collection envelope by default, or a plain array
+// paginated through the RFC 5988 Link header.
+func buildCollectionResponse(schemaName string, cfg GeneratorConfig) map[string]interface{} {
+	itemRef := map[string]string{"$ref": "#/components/schemas/" + schemaName}
+
+	if cfg.CollectionFormat == CollectionFormatLinkHeader {
+		return map[string]interface{}{
+			"description": "Paginated collection",
+			"headers": map[string]interface{}{
+				"Link": map[string]interface{}{
+					"description": "RFC 5988 pagination links with rel=\"first\", \"prev\", \"next\" and \"last\"",
+					"schema":      map[string]interface{}{"type": "string"},
+				},
+			},
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]interface{}{
+						"type":  "array",
+						"items": itemRef,
+					},
+				},
+			},
+		}
 	}
 
+	return map[string]interface{}{
+		"description": "Hydra paginated collection",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"@context":         map[string]string{"type": "string"},
+						"@id":              map[string]string{"type": "string"},
+						"@type":            map[string]string{"type": "string", "example": "hydra:Collection"},
+						"hydra:totalItems": map[string]interface{}{"type": "integer", "description": "Total count (only present if count=true)"},
+						"hydra:member": map[string]interface{}{
+							"type":  "array",
+							"items": itemRef,
+						},
+						"hydra:view": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"@id":            map[string]string{"type": "string"},
+								"@type":          map[string]string{"type": "string"},
+								"hydra:first":    map[string]string{"type": "string"},
+								"hydra:last":     map[string]string{"type": "string"},
+								"hydra:previous": map[string]string{"type": "string"},
+								"hydra:next":     map[string]string{"type": "string"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// addConditionalGet documents the ETag/If-None-Match revalidation flow on a GET
+// operation: the 200 response carries an ETag and a matching If-None-Match
+// yields an empty 304.
+func addConditionalGet(op map[string]interface{}) {
+	params, _ := op["parameters"].([]map[string]interface{})
+	op["parameters"] = append(params, map[string]interface{}{
+		"name":        "If-None-Match",
+		"in":          "header",
+		"description": "ETag from a previous response; the server answers 304 when it still matches",
+		"schema":      map[string]interface{}{"type": "string"},
+	})
+
+	responses := op["responses"].(map[string]interface{})
+	if ok, exists := responses["200"].(map[string]interface{}); exists {
+		headers, _ := ok["headers"].(map[string]interface{})
+		if headers == nil {
+			headers = make(map[string]interface{})
+			ok["headers"] = headers
+		}
+		headers["ETag"] = map[string]interface{}{
+			"description": "Entity tag of the returned representation",
+			"schema":      map[string]interface{}{"type": "string"},
+		}
+	}
+	responses["304"] = map[string]interface{}{
+		"description": "Not modified",
+	}
+}
+
+// buildBatchCreateEndpoints documents the bulk creation of a resource: the body
+// is an array of items and the 201 response returns the created items along
+// with a Location header pointing at the collection.
+func buildBatchCreateEndpoints(name, pluralName, base, requestSchema, schemaName string, tags []string, cfg GeneratorConfig) map[string]interface{} {
+	texts := resourceTexts(cfg, name, pluralName)
+	return map[string]interface{}{
+		"post": map[string]interface{}{
+			"summary":     texts.summary(OperationTextBatchCreate),
+			"description": texts.description(OperationTextBatchCreate),
+			"tags":        tags,
+			"requestBody": map[string]interface{}{
+				"required":    true,
+				"description": "The " + pluralName + " to create",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{
+							"type":  "array",
+							"items": map[string]string{"$ref": "#/components/schemas/" + requestSchema},
+						},
+					},
+				},
+			},
+			"responses": map[string]interface{}{
+				"201": map[string]interface{}{
+					"description": "Successfully created " + pluralName,
+					"headers": map[string]interface{}{
+						"Location": map[string]interface{}{
+							"description": "URL of the " + name + " collection",
+							"schema":      map[string]interface{}{"type": "string", "example": base},
+						},
+					},
+					"content": map[string]interface{}{
+						"application/json": map[string]interface{}{
+							"schema": map[string]interface{}{
+								"type":  "array",
+								"items": map[string]string{"$ref": "#/components/schemas/" + schemaName},
+							},
+						},
+					},
+				},
+				"400": map[string]interface{}{
+					"description": "Bad request",
+				},
+			},
+		},
+	}
+}
+
+func offsetParamSchema(cfg GeneratorConfig) map[string]interface{} {
+	schema := map[string]interface{}{"type": "integer", "default": 0, "minimum": 0}
+	if cfg.OffsetMax > 0 {
+		schema["maximum"] = cfg.OffsetMax
+	}
+	return schema
+}
+
+// buildCountHeadEndpoint documents the lightweight count request: a HEAD on the
+// collection answers with the total number of items in X-Total-Count.
+func buildCountHeadEndpoint(name, pluralName string, tags []string, cfg GeneratorConfig) map[string]interface{} {
+	texts := resourceTexts(cfg, name, pluralName)
+	return map[string]interface{}{
+		"summary":     texts.summary(OperationTextCount),
+		"description": texts.description(OperationTextCount),
+		"tags":        tags,
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "Total count returned in headers",
+				"headers": map[string]interface{}{
+					"X-Total-Count": map[string]interface{}{
+						"description": "Total number of " + pluralName,
+						"schema":      map[string]interface{}{"type": "integer", "minimum": 0},
+					},
+				},
+			},
+		},
+	}
+}
+
+// buildItemHeadEndpoint documents the existence check on an item path: a HEAD
+// answers 200 when the resource exists and 404 otherwise, never with a body.
+func buildItemHeadEndpoint(name, pluralName string, tags []string, cfg GeneratorConfig) map[string]interface{} {
+	texts := resourceTexts(cfg, name, pluralName)
+	return map[string]interface{}{
+		"summary":     texts.summary(OperationTextCheck),
+		"description": texts.description(OperationTextCheck),
+		"tags":        tags,
+		"parameters": []map[string]interface{}{
+			{
+				"name":        "id",
+				"in":          "path",
+				"required":    true,
+				"description": idParamDescription(name, cfg),
+				"schema":      map[string]string{"type": "string"},
+			},
+		},
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "Resource exists",
+			},
+			"404": map[string]interface{}{
+				"description": "Resource not found",
+			},
+		},
+	}
+}
+
+func buildItemEndpointsFromResource(resource plugin.OpenAPIResource, schemaName string, cfg GeneratorConfig) map[string]interface{} {
+	tags := pluginResourceTags(resource, schemaName)
+	texts := resourceTexts(cfg, resource.Name, resource.PluralName)
+
 	endpoints := map[string]interface{}{
 		"get": map[string]interface{}{
-			"summary":     "Get " + resource.Name + " by ID",
-			"description": "Retrieve a single " + resource.Name + " by ID",
+			"summary":     texts.summary(OperationTextGet),
+			"description": texts.description(OperationTextGet),
 			"tags":        tags,
 			"parameters": []map[string]interface{}{
 				{
 					"name":        "id",
 					"in":          "path",
 					"required":    true,
-					"description": "Resource ID",
+					"description": idParamDescription(resource.Name, cfg),
 					"schema":      map[string]string{"type": "string"},
 				},
 			},
@@ -510,15 +1260,15 @@ func buildItemEndpointsFromResource(resource plugin.OpenAPIResource, schemaName
 			},
 		},
 		"delete": map[string]interface{}{
-			"summary":     "Delete " + resource.Name + " by ID",
-			"description": "Delete an existing " + resource.Name,
+			"summary":     texts.summary(OperationTextDelete),
+			"description": texts.description(OperationTextDelete),
 			"tags":        tags,
 			"parameters": []map[string]interface{}{
 				{
 					"name":        "id",
 					"in":          "path",
 					"required":    true,
-					"description": "Resource ID",
+					"description": idParamDescription(resource.Name, cfg),
 					"schema":      map[string]string{"type": "string"},
 				},
 			},
@@ -536,20 +1286,21 @@ func buildItemEndpointsFromResource(resource plugin.OpenAPIResource, schemaName
 	if resource.UpdateModel != nil {
 		updateSchemaRef := "Update" + schemaName + "Request"
 		endpoints["put"] = map[string]interface{}{
-			"summary":     "Update " + resource.Name + " by ID",
-			"description": "Update an existing " + resource.Name,
+			"summary":     texts.summary(OperationTextUpdate),
+			"description": texts.description(OperationTextUpdate),
 			"tags":        tags,
 			"parameters": []map[string]interface{}{
 				{
 					"name":        "id",
 					"in":          "path",
 					"required":    true,
-					"description": "Resource ID",
+					"description": idParamDescription(resource.Name, cfg),
 					"schema":      map[string]string{"type": "string"},
 				},
 			},
 			"requestBody": map[string]interface{}{
-				"required": true,
+				"required":    true,
+				"description": requestBodyDescription(resource.Name, "update", cfg),
 				"content": map[string]interface{}{
 					"application/json": map[string]interface{}{
 						"schema": map[string]string{
@@ -576,5 +1327,302 @@ func buildItemEndpointsFromResource(resource plugin.OpenAPIResource, schemaName
 		}
 	}
 
+	if cfg.ConditionalGet {
+		addConditionalGet(endpoints["get"].(map[string]interface{}))
+	}
+
+	if cfg.ItemHead {
+		endpoints["head"] = buildItemHeadEndpoint(resource.Name, resource.PluralName, tags, cfg)
+	}
+
 	return endpoints
 }
+
+func applyMaxRequestBodySize(paths map[string]interface{}, maxSize int) {
+	if maxSize <= 0 {
+		return
+	}
+
+	forEachOperation(paths, func(_, _ string, op map[string]interface{}) {
+		body, ok := op["requestBody"].(map[string]interface{})
+		if !ok {
+			return
+		}
+		body["x-max-body-size"] = maxSize
+
+		responses, _ := op["responses"].(map[string]interface{})
+		if responses == nil {
+			return
+		}
+		responses["413"] = map[string]interface{}{
+			"description": fmt.Sprintf("Payload too large (maximum %d bytes)", maxSize),
+		}
+	})
+}
+
+// applyUnauthorizedResponses adds a 401 response to every operation requiring
+// authentication, under its own security requirements or the global ones.
+// Operations that allow anonymous access, such as those declaring
+// security: [], are left untouched. The WWW-Authenticate header lists the
+// challenges of the schemes the operation accepts, and is omitted when none
+// of them has one (API keys).
+func applyUnauthorizedResponses(paths map[string]interface{}, globalSecurity []map[string]interface{}, challenges map[string]string) {
+	forEachOperation(paths, func(_, _ string, op map[string]interface{}) {
+		security, ok := operationSecurity(op)
+		if !ok {
+			security = globalSecurity
+		}
+		if !requiresAuthentication(security) {
+			return
+		}
+
+		responses, _ := op["responses"].(map[string]interface{})
+		if responses == nil {
+			return
+		}
+		response := map[string]interface{}{
+			"description": "Missing or invalid authentication credentials",
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": map[string]string{"$ref": "#/components/schemas/Error"},
+				},
+			},
+		}
+		if challenge := operationChallenge(security, challenges); challenge != "" {
+			response["headers"] = map[string]interface{}{
+				"WWW-Authenticate": map[string]interface{}{
+					"description": "Authentication challenges of the accepted schemes",
+					"schema":      map[string]interface{}{"type": "string", "example": challenge},
+				},
+			}
+		}
+		responses["401"] = response
+	})
+}
+
+// operationChallenge joins the challenges of the schemes named by the
+// security requirements, in requirement order and without duplicates.
+func operationChallenge(security []map[string]interface{}, challenges map[string]string) string {
+	var listed []string
+	for _, requirement := range security {
+		for _, name := range sortedKeys(requirement) {
+			if challenge := challenges[name]; challenge != "" && !slices.Contains(listed, challenge) {
+				listed = append(listed, challenge)
+			}
+		}
+	}
+	return strings.Join(listed, ", ")
+}
+
+// authenticationChallenges returns the WWW-Authenticate challenge of each
+// declared security scheme answering a 401 with one: Basic for http basic,
+// Bearer for http bearer and oauth2. API key schemes have none.
+func authenticationChallenges(cfg GeneratorConfig) map[string]string {
+	bearer := `Bearer error="invalid_token"`
+	challenges := map[string]string{}
+	if !cfg.NoGlobalSecurity {
+		challenges["bearerAuth"] = bearer
+	}
+	for name, scheme := range cfg.SecuritySchemes {
+		switch {
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+			challenges[name] = `Basic realm="` + cfg.Title + `"`
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"), scheme.Type == "oauth2":
+			challenges[name] = bearer
+		}
+	}
+	return challenges
+}
+
+// operationSecurity returns the security requirements an operation declares,
+// typed as built or as decoded from JSON; ok is false when it inherits the
+// global ones.
+func operationSecurity(op map[string]interface{}) (security []map[string]interface{}, ok bool) {
+	switch declared := op["security"].(type) {
+	case []map[string]interface{}:
+		return declared, true
+	case []interface{}:
+		for _, requirement := range declared {
+			requirement, _ := requirement.(map[string]interface{})
+			security = append(security, requirement)
+		}
+		return security, true
+	}
+	return nil, false
+}
+
+// buildSecurityRequirements turns scheme names into alternative security
+// requirement objects, with the scopes of the scheme; an empty name becomes
+// {}, allowing anonymous access.
+func buildSecurityRequirements(schemes []string, scopes map[string][]string) []map[string]interface{} {
+	requirements := make([]map[string]interface{}, 0, len(schemes))
+	for _, scheme := range schemes {
+		if scheme == "" {
+			requirements = append(requirements, map[string]interface{}{})
+			continue
+		}
+		requirements = append(requirements, map[string]interface{}{scheme: append([]string{}, scopes[scheme]...)})
+	}
+	return requirements
+}
+
+// scopedSecuritySchemes returns the names of the schemes whose requirements
+// list scopes: the oauth2 and openIdConnect ones.
+func scopedSecuritySchemes(schemes map[string]SecurityScheme) map[string]bool {
+	scoped := make(map[string]bool)
+	for name, scheme := range schemes {
+		if scheme.Type == "oauth2" || scheme.Type == "openIdConnect" {
+			scoped[name] = true
+		}
+	}
+	return scoped
+}
+
+// applyOperationScopes replaces the scopes of the scoped scheme requirements
+// of the operations listed by "METHOD /path", declaring them on operations
+// that inherited the global requirements.
+func applyOperationScopes(paths map[string]interface{}, globalSecurity []map[string]interface{}, overrides map[string][]string, scoped map[string]bool) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+		scopes, ok := overrides[strings.ToUpper(method)+" "+path]
+		if !ok {
+			return
+		}
+		security, declared := operationSecurity(op)
+		if !declared {
+			security = globalSecurity
+		}
+
+		changed := false
+		requirements := make([]map[string]interface{}, 0, len(security))
+		for _, requirement := range security {
+			scopedRequirement := make(map[string]interface{}, len(requirement))
+			for scheme, schemeScopes := range requirement {
+				if scoped[scheme] {
+					schemeScopes = append([]string{}, scopes...)
+					changed = true
+				}
+				scopedRequirement[scheme] = schemeScopes
+			}
+			requirements = append(requirements, scopedRequirement)
+		}
+		if changed {
+			op["security"] = requirements
+		}
+	})
+}
+
+// requiresAuthentication reports whether every alternative requirement names
+// a scheme, i.e. anonymous access is not allowed.
+func requiresAuthentication(security []map[string]interface{}) bool {
+	if len(security) == 0 {
+		return false
+	}
+	for _, requirement := range security {
+		if len(requirement) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// applyOperationSecurity sets the security requirements of the operations
+// listed by "METHOD /path", overriding the global ones.
+func applyOperationSecurity(paths map[string]interface{}, overrides map[string][]string, scopes map[string][]string) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+		if schemes, ok := overrides[strings.ToUpper(method)+" "+path]; ok {
+			op["security"] = buildSecurityRequirements(schemes, scopes)
+		}
+	})
+}
+
+// applyDeprecatedOperations flags the operations listed by "METHOD /path"
+// deprecated.
+func applyDeprecatedOperations(paths map[string]interface{}, deprecated []string) {
+	if len(deprecated) == 0 {
+		return
+	}
+
+	forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+		if slices.Contains(deprecated, strings.ToUpper(method)+" "+path) {
+			op["deprecated"] = true
+		}
+	})
+}
+
+func defaultErrorSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"error": map[string]interface{}{"type": "string", "example": "unauthorized"},
+		},
+		"required": []string{"error"},
+	}
+}
+
+// applyIdempotency flags every operation with x-idempotent, following the HTTP
+// method semantics (RFC 9110) unless overridden by "METHOD /path".
+func applyIdempotency(paths map[string]interface{}, overrides map[string]bool) {
+	forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+		idempotent, ok := overrides[strings.ToUpper(method)+" "+path]
+		if !ok {
+			idempotent = method != "post" && method != "patch"
+		}
+		op["x-idempotent"] = idempotent
+	})
+}
+
+// forEachOperation calls fn for every operation object in the paths map, in a
+// stable path and method order.
+func forEachOperation(paths map[string]interface{}, fn func(path, method string, op map[string]interface{})) {
+	for _, path := range sortedKeys(paths) {
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, method := range sortedKeys(item) {
+			if op, ok := item[method].(map[string]interface{}); ok {
+				fn(path, method, op)
+			}
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func idParamDescription(resourceName string, cfg GeneratorConfig) string {
+	template := cfg.IDParamDescription
+	if template == "" {
+		template = message(cfg, messageIDParam)
+	}
+	return strings.ReplaceAll(template, "{resource}", resourceName)
+}
+
+func limitParamDescription(cfg GeneratorConfig) string {
+	return strings.NewReplacer(
+		"{default}", strconv.Itoa(cfg.PaginationLimit),
+		"{max}", strconv.Itoa(cfg.PaginationMaxLimit),
+	).Replace(message(cfg, messageLimitParam))
+}
+
+func requestBodyDescription(resourceName, action string, cfg GeneratorConfig) string {
+	template := cfg.RequestBodyDescription
+	if template == "" {
+		template = message(cfg, messageRequestBody)
+	}
+	return strings.NewReplacer("{resource}", resourceName, "{action}", message(cfg, messageBodyAction+action)).Replace(template)
+}
@@ -1,10 +1,13 @@
 package openapi
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/gofiber/fiber/v3"
 )
@@ -87,7 +90,7 @@ func TestBuildItemEndpoints(t *testing.T) {
 	}
 	schemaName := "User"
 
-	got := buildItemEndpoints(resource, schemaName)
+	got := buildItemEndpoints(resource, schemaName, GeneratorConfig{})
 
 	validateItemGETEndpoint(t, got)
 	validateItemPUTEndpoint(t, got)
@@ -563,3 +566,1807 @@ func TestBuildCollectionEndpointsWithDifferentConfigs(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateOpenAPISpec_MainDTOOverride(t *testing.T) {
+	tempDir := t.TempDir()
+
+	userContent := `package dto
+
+type UserDTO struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}
+
+type UserResponseDTO struct {
+	ID    int64  ` + "`json:\"id\"`" + `
+	Name  string ` + "`json:\"name\"`" + `
+	Email string ` + "`json:\"email\"`" + `
+}`
+	err := os.WriteFile(filepath.Join(tempDir, "user.go"), []byte(userContent), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create user.go: %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(fiber.New(), GeneratorConfig{
+		DTOsDirectory: tempDir,
+		MainDTO:       map[string]string{"user": "UserResponseDTO"},
+	})
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	user := schemas["User"].(map[string]interface{})
+	properties := user["properties"].(map[string]interface{})
+
+	if len(properties) != 3 {
+		t.Errorf("User schema has %d properties, want 3 (from UserResponseDTO)", len(properties))
+	}
+	if _, ok := properties["email"]; !ok {
+		t.Error("User schema missing email property from UserResponseDTO")
+	}
+}
+
+func TestBuildCollectionEndpoints_CountHead(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+
+	without := buildCollectionEndpoints(resource, "User", GeneratorConfig{})
+	if _, ok := without["head"]; ok {
+		t.Error("HEAD endpoint should not be documented unless CountHead is set")
+	}
+
+	with := buildCollectionEndpoints(resource, "User", GeneratorConfig{CountHead: true})
+	head, ok := with["head"].(map[string]interface{})
+	if !ok {
+		t.Fatal("buildCollectionEndpoints() missing HEAD endpoint")
+	}
+
+	responses := head["responses"].(map[string]interface{})
+	ok200 := responses["200"].(map[string]interface{})
+	headers := ok200["headers"].(map[string]interface{})
+	if _, ok := headers["X-Total-Count"]; !ok {
+		t.Error("HEAD 200 response missing X-Total-Count header")
+	}
+	if _, ok := ok200["content"]; ok {
+		t.Error("HEAD 200 response should not document a body")
+	}
+}
+
+func TestBuildItemEndpoints_ItemHead(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+
+	without := buildItemEndpoints(resource, "User", GeneratorConfig{})
+	if _, ok := without["head"]; ok {
+		t.Error("HEAD endpoint should not be documented unless ItemHead is set")
+	}
+
+	with := buildItemEndpoints(resource, "User", GeneratorConfig{ItemHead: true})
+	head, ok := with["head"].(map[string]interface{})
+	if !ok {
+		t.Fatal("buildItemEndpoints() missing HEAD endpoint")
+	}
+
+	params := head["parameters"].([]map[string]interface{})
+	if len(params) != 1 || params[0]["name"] != "id" || params[0]["in"] != "path" {
+		t.Errorf("HEAD parameters = %v, want the id path parameter", params)
+	}
+
+	responses := head["responses"].(map[string]interface{})
+	for _, status := range []string{"200", "404"} {
+		response, ok := responses[status].(map[string]interface{})
+		if !ok {
+			t.Errorf("HEAD responses missing %s status", status)
+			continue
+		}
+		if _, ok := response["content"]; ok {
+			t.Errorf("HEAD %s response should not document a body", status)
+		}
+	}
+}
+
+func TestGenerateOpenAPISpec_HideFields(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"user.go": `package dto
+
+type UserDTO struct {
+	ID            int64  ` + "`json:\"id\"`" + `
+	Name          string ` + "`json:\"name\"`" + `
+	InternalNotes string ` + "`json:\"internalNotes\"`" + `
+}`,
+		"note.go": `package dto
+
+type NoteDTO struct {
+	Body          string ` + "`json:\"body\"`" + `
+	InternalNotes string ` + "`json:\"internalNotes\"`" + `
+}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	spec, err := generateOpenAPISpec(fiber.New(), GeneratorConfig{
+		DTOsDirectory: tempDir,
+		HideFields:    []string{"internalNotes"},
+	})
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for name, schema := range schemas {
+		s := schema.(map[string]interface{})
+		if _, ok := s["properties"].(map[string]interface{})["internalNotes"]; ok {
+			t.Errorf("schema %s still exposes internalNotes", name)
+		}
+		if required, ok := s["required"].([]string); ok {
+			for _, r := range required {
+				if r == "internalNotes" {
+					t.Errorf("schema %s still requires internalNotes", name)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateOpenAPISpec_LoadExamples(t *testing.T) {
+	tempDir := t.TempDir()
+
+	userContent := `package dto
+
+type UserDTO struct {
+	ID   int64  ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "user.go"), []byte(userContent), 0644); err != nil {
+		t.Fatalf("Failed to create user.go: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "user.example.json"), []byte(`{"id": 7, "name": "Ada"}`), 0644); err != nil {
+		t.Fatalf("Failed to create user.example.json: %v", err)
+	}
+
+	for _, load := range []bool{false, true} {
+		spec, err := generateOpenAPISpec(fiber.New(), GeneratorConfig{DTOsDirectory: tempDir, LoadExamples: load})
+		if err != nil {
+			t.Fatalf("generateOpenAPISpec() error = %v", err)
+		}
+
+		schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+		example, ok := schemas["User"].(map[string]interface{})["example"]
+		if ok != load {
+			t.Fatalf("LoadExamples=%v: example present = %v", load, ok)
+		}
+		if load && example.(map[string]interface{})["name"] != "Ada" {
+			t.Errorf("User example = %v, want the companion file contents", example)
+		}
+	}
+}
+
+func TestRequestBodyDescription(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      GeneratorConfig
+		wantPost string
+		wantPut  string
+	}{
+		{
+			name:     "default template",
+			cfg:      GeneratorConfig{},
+			wantPost: "The user to create",
+			wantPut:  "The user to update",
+		},
+		{
+			name:     "custom template",
+			cfg:      GeneratorConfig{RequestBodyDescription: "Payload used to {action} a {resource}"},
+			wantPost: "Payload used to create a user",
+			wantPut:  "Payload used to update a user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := resourceDTOs{Name: "user", PluralName: "users"}
+
+			post := buildCollectionEndpoints(resource, "User", tt.cfg)["post"].(map[string]interface{})
+			if got := post["requestBody"].(map[string]interface{})["description"]; got != tt.wantPost {
+				t.Errorf("POST requestBody description = %v, want %v", got, tt.wantPost)
+			}
+
+			put := buildItemEndpoints(resource, "User", tt.cfg)["put"].(map[string]interface{})
+			if got := put["requestBody"].(map[string]interface{})["description"]; got != tt.wantPut {
+				t.Errorf("PUT requestBody description = %v, want %v", got, tt.wantPut)
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPISpec_DiscoveredRoutesOnly(t *testing.T) {
+	app := fiber.New()
+	app.Get("/health", func(c fiber.Ctx) error { return c.SendString("OK") })
+	app.Post("/webhooks/stripe", func(c fiber.Ctx) error { return nil })
+
+	spec, err := generateOpenAPISpec(app, GeneratorConfig{
+		ServerURL: "http://localhost:3000",
+		Title:     "Routes Only",
+		Version:   "1.0.0",
+	})
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	if len(paths) != 2 {
+		t.Errorf("spec has %d paths, want 2 discovered routes", len(paths))
+	}
+	for _, path := range []string{"/health", "/webhooks/stripe"} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("spec missing discovered path %q", path)
+		}
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	if len(schemas) != 0 {
+		t.Errorf("spec has %d schemas, want none without DTOs", len(schemas))
+	}
+}
+
+func TestBuildCollectionResponse_LinkHeader(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+	endpoints := buildCollectionEndpoints(resource, "User", GeneratorConfig{CollectionFormat: CollectionFormatLinkHeader})
+
+	list := endpoints["get"].(map[string]interface{})
+	ok200 := list["responses"].(map[string]interface{})["200"].(map[string]interface{})
+
+	headers, ok := ok200["headers"].(map[string]interface{})
+	if !ok {
+		t.Fatal("link-header 200 response missing headers")
+	}
+	if _, ok := headers["Link"]; !ok {
+		t.Error("link-header 200 response missing Link header")
+	}
+
+	schema := ok200["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	if schema["type"] != "array" {
+		t.Errorf("link-header body type = %v, want array", schema["type"])
+	}
+	items := schema["items"].(map[string]string)
+	if items["$ref"] != "#/components/schemas/User" {
+		t.Errorf("link-header items = %v, want User ref", items)
+	}
+}
+
+func TestBuildCollectionResponse_DefaultsToHydra(t *testing.T) {
+	for _, format := range []string{"", CollectionFormatHydra, "unknown"} {
+		response := buildCollectionResponse("User", GeneratorConfig{CollectionFormat: format})
+		if response["description"] != "Hydra paginated collection" {
+			t.Errorf("CollectionFormat %q description = %v, want Hydra collection", format, response["description"])
+		}
+		if _, ok := response["headers"]; ok {
+			t.Errorf("CollectionFormat %q should not document a Link header", format)
+		}
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+	cfg := GeneratorConfig{ConditionalGet: true}
+
+	operations := map[string]map[string]interface{}{
+		"collection": buildCollectionEndpoints(resource, "User", cfg)["get"].(map[string]interface{}),
+		"item":       buildItemEndpoints(resource, "User", cfg)["get"].(map[string]interface{}),
+	}
+
+	for name, op := range operations {
+		responses := op["responses"].(map[string]interface{})
+		if _, ok := responses["304"]; !ok {
+			t.Errorf("%s GET missing 304 response", name)
+		}
+
+		headers, _ := responses["200"].(map[string]interface{})["headers"].(map[string]interface{})
+		if _, ok := headers["ETag"]; !ok {
+			t.Errorf("%s GET 200 missing ETag header", name)
+		}
+
+		found := false
+		for _, param := range op["parameters"].([]map[string]interface{}) {
+			if param["name"] == "If-None-Match" && param["in"] == "header" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%s GET missing If-None-Match header parameter", name)
+		}
+	}
+
+	plain := buildCollectionEndpoints(resource, "User", GeneratorConfig{})["get"].(map[string]interface{})
+	if _, ok := plain["responses"].(map[string]interface{})["304"]; ok {
+		t.Error("collection GET should not document 304 unless ConditionalGet is set")
+	}
+}
+
+func TestIDParamDescription(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  GeneratorConfig
+		want string
+	}{
+		{
+			name: "default description",
+			cfg:  GeneratorConfig{},
+			want: "Resource ID",
+		},
+		{
+			name: "templated description",
+			cfg:  GeneratorConfig{IDParamDescription: "The {resource}'s unique identifier"},
+			want: "The user's unique identifier",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := buildItemEndpoints(resourceDTOs{Name: "user", PluralName: "users"}, "User", tt.cfg)
+
+			for _, method := range []string{"get", "put", "delete"} {
+				params := endpoints[method].(map[string]interface{})["parameters"].([]map[string]interface{})
+				if got := params[0]["description"]; got != tt.want {
+					t.Errorf("%s id description = %v, want %v", method, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyMaxRequestBodySize(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+	paths := map[string]interface{}{
+		"/users":      buildCollectionEndpoints(resource, "User", GeneratorConfig{}),
+		"/users/{id}": buildItemEndpoints(resource, "User", GeneratorConfig{}),
+	}
+
+	applyMaxRequestBodySize(paths, 1<<20)
+
+	tests := []struct {
+		path     string
+		method   string
+		wantBody bool
+	}{
+		{path: "/users", method: "post", wantBody: true},
+		{path: "/users/{id}", method: "put", wantBody: true},
+		{path: "/users", method: "get", wantBody: false},
+		{path: "/users/{id}", method: "delete", wantBody: false},
+	}
+
+	for _, tt := range tests {
+		op := paths[tt.path].(map[string]interface{})[tt.method].(map[string]interface{})
+		_, has413 := op["responses"].(map[string]interface{})["413"]
+		if has413 != tt.wantBody {
+			t.Errorf("%s %s 413 present = %v, want %v", tt.method, tt.path, has413, tt.wantBody)
+		}
+		if tt.wantBody {
+			body := op["requestBody"].(map[string]interface{})
+			if body["x-max-body-size"] != 1<<20 {
+				t.Errorf("%s %s x-max-body-size = %v, want %d", tt.method, tt.path, body["x-max-body-size"], 1<<20)
+			}
+		}
+	}
+}
+
+func TestApplyUnauthorizedResponses(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+	paths := map[string]interface{}{
+		"/users": buildCollectionEndpoints(resource, "User", GeneratorConfig{}),
+		"/health": map[string]interface{}{
+			"get": map[string]interface{}{
+				"security":  []map[string]interface{}{},
+				"responses": map[string]interface{}{"200": map[string]interface{}{"description": "OK"}},
+			},
+		},
+		"/status": map[string]interface{}{
+			"get": map[string]interface{}{
+				"security":  []interface{}{},
+				"responses": map[string]interface{}{"200": map[string]interface{}{"description": "OK"}},
+			},
+			"post": map[string]interface{}{
+				"security":  []interface{}{map[string]interface{}{"apiKey": []interface{}{}}},
+				"responses": map[string]interface{}{"200": map[string]interface{}{"description": "OK"}},
+			},
+		},
+		"/reports": map[string]interface{}{
+			"get": map[string]interface{}{
+				"security":  []interface{}{map[string]interface{}{"basic": []interface{}{}}, map[string]interface{}{"oauth": []interface{}{"read"}}},
+				"responses": map[string]interface{}{"200": map[string]interface{}{"description": "OK"}},
+			},
+		},
+	}
+
+	challenges := authenticationChallenges(GeneratorConfig{
+		Title: "My API",
+		SecuritySchemes: map[string]SecurityScheme{
+			"basic": {Type: "http", Scheme: "basic"},
+			"oauth": {Type: "oauth2"},
+		},
+	})
+	applyUnauthorizedResponses(paths, buildSecurityRequirements([]string{"bearerAuth"}, nil), challenges)
+
+	tests := []struct {
+		path          string
+		method        string
+		want401       bool
+		wantChallenge string
+	}{
+		{path: "/users", method: "get", want401: true, wantChallenge: `Bearer error="invalid_token"`},
+		{path: "/users", method: "post", want401: true, wantChallenge: `Bearer error="invalid_token"`},
+		{path: "/health", method: "get", want401: false},
+		{path: "/status", method: "get", want401: false},
+		{path: "/status", method: "post", want401: true},
+		{path: "/reports", method: "get", want401: true, wantChallenge: `Basic realm="My API", Bearer error="invalid_token"`},
+	}
+
+	for _, tt := range tests {
+		op := paths[tt.path].(map[string]interface{})[tt.method].(map[string]interface{})
+		resp, has401 := op["responses"].(map[string]interface{})["401"].(map[string]interface{})
+		if has401 != tt.want401 {
+			t.Errorf("%s %s 401 present = %v, want %v", tt.method, tt.path, has401, tt.want401)
+			continue
+		}
+		if !tt.want401 {
+			continue
+		}
+		var challenge interface{}
+		headers, _ := resp["headers"].(map[string]interface{})
+		if header, ok := headers["WWW-Authenticate"].(map[string]interface{}); ok {
+			challenge = header["schema"].(map[string]interface{})["example"]
+		}
+		if tt.wantChallenge == "" && challenge != nil {
+			t.Errorf("%s %s WWW-Authenticate = %v, want no header", tt.method, tt.path, challenge)
+		} else if tt.wantChallenge != "" && challenge != tt.wantChallenge {
+			t.Errorf("%s %s WWW-Authenticate = %v, want %q", tt.method, tt.path, challenge, tt.wantChallenge)
+		}
+		schema := resp["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"]
+		if want := map[string]string{"$ref": "#/components/schemas/Error"}; !reflect.DeepEqual(schema, want) {
+			t.Errorf("%s %s 401 schema = %v, want %v", tt.method, tt.path, schema, want)
+		}
+	}
+}
+
+func TestAuthenticationChallenges(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  GeneratorConfig
+		want map[string]string
+	}{
+		{
+			name: "built-in bearer scheme",
+			cfg:  GeneratorConfig{APIKeyHeader: "X-API-Key"},
+			want: map[string]string{"bearerAuth": `Bearer error="invalid_token"`},
+		},
+		{
+			name: "declared schemes",
+			cfg: GeneratorConfig{
+				Title:            "My API",
+				NoGlobalSecurity: true,
+				SecuritySchemes: map[string]SecurityScheme{
+					"basic":  {Type: "http", Scheme: "basic"},
+					"token":  {Type: "http", Scheme: "Bearer"},
+					"oauth":  {Type: "oauth2"},
+					"key":    {Type: "apiKey", In: "header", Name: "X-Key"},
+					"openId": {Type: "openIdConnect", OpenIDConnectURL: "https://example.com"},
+				},
+			},
+			want: map[string]string{
+				"basic": `Basic realm="My API"`,
+				"token": `Bearer error="invalid_token"`,
+				"oauth": `Bearer error="invalid_token"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := authenticationChallenges(tt.cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("authenticationChallenges() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPISpec_UnauthorizedResponse(t *testing.T) {
+	customSchema := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+	}
+
+	tests := []struct {
+		name        string
+		errorSchema map[string]interface{}
+		want        map[string]interface{}
+	}{
+		{name: "default error schema", want: defaultErrorSchema()},
+		{name: "custom error schema", errorSchema: customSchema, want: customSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, app, cfg := setupSpecWithMultipleResources(t)
+			cfg.UnauthorizedResponse = true
+			cfg.ErrorSchema = tt.errorSchema
+
+			spec, err := generateOpenAPISpec(app, cfg)
+			if err != nil {
+				t.Fatalf("generateOpenAPISpec() error = %v", err)
+			}
+
+			schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+			if !reflect.DeepEqual(schemas["Error"], tt.want) {
+				t.Errorf("Error schema = %v, want %v", schemas["Error"], tt.want)
+			}
+
+			users := spec["paths"].(map[string]interface{})["/users"].(map[string]interface{})
+			if _, ok := users["get"].(map[string]interface{})["responses"].(map[string]interface{})["401"]; !ok {
+				t.Error("GET /users missing 401 response")
+			}
+		})
+	}
+}
+
+func TestBuildCollectionEndpoints_OffsetMax(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+
+	tests := []struct {
+		name        string
+		offsetMax   int
+		wantMaximum interface{}
+	}{
+		{name: "unbounded by default", offsetMax: 0, wantMaximum: nil},
+		{name: "configured maximum", offsetMax: 10000, wantMaximum: 10000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildCollectionEndpoints(resource, "User", GeneratorConfig{OffsetMax: tt.offsetMax})
+
+			params := got["get"].(map[string]interface{})["parameters"].([]map[string]interface{})
+			for _, param := range params {
+				if param["name"] != "offset" {
+					continue
+				}
+				schema := param["schema"].(map[string]interface{})
+				if schema["maximum"] != tt.wantMaximum {
+					t.Errorf("offset maximum = %v, want %v", schema["maximum"], tt.wantMaximum)
+				}
+				return
+			}
+			t.Error("offset parameter not found")
+		})
+	}
+}
+
+func TestApplyIdempotency(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+	paths := map[string]interface{}{
+		"/users":       buildCollectionEndpoints(resource, "User", GeneratorConfig{CountHead: true}),
+		"/users/{id}":  buildItemEndpoints(resource, "User", GeneratorConfig{}),
+		"/users/batch": buildBatchCreateEndpoints("user", "users", "/users", "User", "User", []string{"User"}, GeneratorConfig{}),
+	}
+
+	applyIdempotency(paths, map[string]bool{"POST /users/batch": true})
+
+	tests := []struct {
+		path   string
+		method string
+		want   bool
+	}{
+		{path: "/users", method: "get", want: true},
+		{path: "/users", method: "head", want: true},
+		{path: "/users", method: "post", want: false},
+		{path: "/users/{id}", method: "get", want: true},
+		{path: "/users/{id}", method: "put", want: true},
+		{path: "/users/{id}", method: "delete", want: true},
+		{path: "/users/batch", method: "post", want: true},
+	}
+
+	for _, tt := range tests {
+		op := paths[tt.path].(map[string]interface{})[tt.method].(map[string]interface{})
+		if got := op["x-idempotent"]; got != tt.want {
+			t.Errorf("%s %s x-idempotent = %v, want %v", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestApplyDeprecatedOperations(t *testing.T) {
+	resource := resourceDTOs{Name: "user", PluralName: "users"}
+	paths := map[string]interface{}{
+		"/users":      buildCollectionEndpoints(resource, "User", GeneratorConfig{}),
+		"/users/{id}": buildItemEndpoints(resource, "User", GeneratorConfig{}),
+	}
+
+	applyDeprecatedOperations(paths, []string{"GET /users/{id}", "post /users"})
+
+	tests := []struct {
+		path   string
+		method string
+		want   bool
+	}{
+		{path: "/users/{id}", method: "get", want: true},
+		{path: "/users/{id}", method: "delete", want: false},
+		{path: "/users", method: "get", want: false},
+		{path: "/users", method: "post", want: false},
+	}
+
+	for _, tt := range tests {
+		op := paths[tt.path].(map[string]interface{})[tt.method].(map[string]interface{})
+		if got, _ := op["deprecated"].(bool); got != tt.want {
+			t.Errorf("%s %s deprecated = %v, want %v", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestBuildSecurityRequirements(t *testing.T) {
+	tests := []struct {
+		name    string
+		schemes []string
+		scopes  map[string][]string
+		want    []map[string]interface{}
+	}{
+		{
+			name:    "single scheme",
+			schemes: []string{"bearerAuth"},
+			want:    []map[string]interface{}{{"bearerAuth": []string{}}},
+		},
+		{
+			name:    "alternative schemes",
+			schemes: []string{"bearerAuth", "apiKey"},
+			want:    []map[string]interface{}{{"bearerAuth": []string{}}, {"apiKey": []string{}}},
+		},
+		{
+			name:    "scoped scheme",
+			schemes: []string{"oauth", "apiKey"},
+			scopes:  map[string][]string{"oauth": {"orders:read"}},
+			want:    []map[string]interface{}{{"oauth": []string{"orders:read"}}, {"apiKey": []string{}}},
+		},
+		{
+			name:    "optional authentication",
+			schemes: []string{"bearerAuth", ""},
+			want:    []map[string]interface{}{{"bearerAuth": []string{}}, {}},
+		},
+		{
+			name:    "public",
+			schemes: []string{},
+			want:    []map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildSecurityRequirements(tt.schemes, tt.scopes)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildSecurityRequirements(%v) = %v, want %v", tt.schemes, got, tt.want)
+			}
+			if wantAuth := len(tt.schemes) > 0 && tt.schemes[len(tt.schemes)-1] != ""; requiresAuthentication(got) != wantAuth {
+				t.Errorf("requiresAuthentication(%v) = %v, want %v", got, !wantAuth, wantAuth)
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPISpec_SecurityAlternatives(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	cfg.Security = []string{"bearerAuth", "apiKey"}
+	cfg.APIKeyHeader = "X-API-Key"
+	cfg.OperationSecurity = map[string][]string{"GET /products": {"bearerAuth", ""}}
+	cfg.UnauthorizedResponse = true
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	wantGlobal := []map[string]interface{}{{"bearerAuth": []string{}}, {"apiKey": []string{}}}
+	if !reflect.DeepEqual(spec["security"], wantGlobal) {
+		t.Errorf("security = %v, want %v", spec["security"], wantGlobal)
+	}
+
+	schemes := spec["components"].(map[string]interface{})["securitySchemes"].(map[string]interface{})
+	apiKey, ok := schemes["apiKey"].(map[string]interface{})
+	if !ok || apiKey["in"] != "header" || apiKey["name"] != "X-API-Key" {
+		t.Errorf("apiKey scheme = %v, want a header scheme named X-API-Key", schemes["apiKey"])
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	listProducts := paths["/products"].(map[string]interface{})["get"].(map[string]interface{})
+	wantOperation := []map[string]interface{}{{"bearerAuth": []string{}}, {}}
+	if !reflect.DeepEqual(listProducts["security"], wantOperation) {
+		t.Errorf("GET /products security = %v, want %v", listProducts["security"], wantOperation)
+	}
+	if _, ok := listProducts["responses"].(map[string]interface{})["401"]; ok {
+		t.Error("GET /products allows anonymous access and should not document 401")
+	}
+
+	listUsers := paths["/users"].(map[string]interface{})["get"].(map[string]interface{})
+	if _, ok := listUsers["security"]; ok {
+		t.Error("GET /users should inherit the global security")
+	}
+	if _, ok := listUsers["responses"].(map[string]interface{})["401"]; !ok {
+		t.Error("GET /users requires authentication and should document 401")
+	}
+}
+
+func TestGenerateOpenAPISpec_InfoMetadata(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*GeneratorConfig)
+		want   map[string]interface{}
+	}{
+		{
+			name:   "unset",
+			modify: func(cfg *GeneratorConfig) {},
+			want:   map[string]interface{}{},
+		},
+		{
+			name: "contact, license and terms",
+			modify: func(cfg *GeneratorConfig) {
+				cfg.Contact = map[string]string{"name": "API Support", "email": "support@example.com"}
+				cfg.License = map[string]string{"name": "MIT", "url": "https://opensource.org/licenses/MIT"}
+				cfg.TermsOfService = "https://example.com/terms"
+			},
+			want: map[string]interface{}{
+				"contact":        map[string]string{"name": "API Support", "email": "support@example.com"},
+				"license":        map[string]string{"name": "MIT", "url": "https://opensource.org/licenses/MIT"},
+				"termsOfService": "https://example.com/terms",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, app, cfg := setupSpecWithMultipleResources(t)
+			tt.modify(&cfg)
+
+			spec, err := generateOpenAPISpec(app, cfg)
+			if err != nil {
+				t.Fatalf("generateOpenAPISpec() error = %v", err)
+			}
+
+			info := spec["info"].(map[string]interface{})
+			for _, key := range []string{"contact", "license", "termsOfService"} {
+				if !reflect.DeepEqual(info[key], tt.want[key]) {
+					t.Errorf("info.%s = %v, want %v", key, info[key], tt.want[key])
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPISpec_Servers(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+	wantDefault := []map[string]string{{"url": cfg.ServerURL, "description": "Development server"}}
+	if !reflect.DeepEqual(spec["servers"], wantDefault) {
+		t.Errorf("servers = %v, want %v", spec["servers"], wantDefault)
+	}
+
+	cfg.Servers = []Server{
+		{
+			URL:         "https://{environment}.api.example.com",
+			Description: "Per-environment API",
+			Variables: map[string]ServerVariable{
+				"environment": {Default: "production", Enum: []string{"production", "staging"}},
+			},
+		},
+		{URL: "http://localhost:8000"},
+	}
+	spec, err = generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	raw, err := json.Marshal(spec["servers"])
+	if err != nil {
+		t.Fatalf("Failed to marshal servers: %v", err)
+	}
+	want := `[{"url":"https://{environment}.api.example.com","description":"Per-environment API",` +
+		`"variables":{"environment":{"default":"production","enum":["production","staging"]}}},` +
+		`{"url":"http://localhost:8000"}]`
+	if string(raw) != want {
+		t.Errorf("servers = %s, want %s", raw, want)
+	}
+}
+
+func TestGenerateOpenAPISpec_BasePath(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	cfg.BasePath = "/api/v1"
+	app.Get("/api/v1/users", func(c fiber.Ctx) error { return nil })
+	app.Get("/api/v1/auth/me", func(c fiber.Ctx) error { return nil })
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	for _, path := range []string{"/api/v1/users", "/api/v1/users/{id}", "/api/v1/products", "/api/v1/auth/me"} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("expected path %s", path)
+		}
+	}
+	for _, path := range []string{"/users", "/products"} {
+		if _, ok := paths[path]; ok {
+			t.Errorf("unprefixed path %s should not be documented", path)
+		}
+	}
+
+	// The registered resource route is recognized, not documented twice
+	users := paths["/api/v1/users"].(map[string]interface{})["get"].(map[string]interface{})
+	if tags := users["tags"].([]string); tags[0] != "User" {
+		t.Errorf("GET /api/v1/users tags = %v, want the resource tag", tags)
+	}
+	me := paths["/api/v1/auth/me"].(map[string]interface{})["get"].(map[string]interface{})
+	if tags := me["tags"].([]string); tags[0] != "Authentication" {
+		t.Errorf("GET /api/v1/auth/me tags = %v, want [Authentication]", tags)
+	}
+}
+
+func TestGenerateOpenAPISpec_BatchCreate(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	cfg.BatchCreate = []string{"user"}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	if _, ok := paths["/products/batch"]; ok {
+		t.Error("/products/batch should not be documented for a resource without batch support")
+	}
+
+	batch, ok := paths["/users/batch"].(map[string]interface{})
+	if !ok {
+		t.Fatal("spec missing /users/batch path")
+	}
+
+	created := batch["post"].(map[string]interface{})["responses"].(map[string]interface{})["201"].(map[string]interface{})
+	if _, ok := created["headers"].(map[string]interface{})["Location"]; !ok {
+		t.Error("batch 201 response missing Location header")
+	}
+
+	schema := created["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	if schema["type"] != "array" {
+		t.Errorf("batch 201 body type = %v, want array", schema["type"])
+	}
+	if items := schema["items"].(map[string]string); items["$ref"] != "#/components/schemas/User" {
+		t.Errorf("batch 201 items = %v, want User ref", items)
+	}
+}
+
+func TestGenerateOpenAPISpec_RelativeDTOsDirectory(t *testing.T) {
+	baseDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(baseDir, "dtos"), 0755); err != nil {
+		t.Fatalf("Failed to create dtos dir: %v", err)
+	}
+
+	userContent := `package dto
+
+type UserDTO struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(baseDir, "dtos", "user.go"), []byte(userContent), 0644); err != nil {
+		t.Fatalf("Failed to create user.go: %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(fiber.New(), GeneratorConfig{
+		DTOsDirectory:     "./dtos",
+		DTOsBaseDirectory: baseDir,
+	})
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	if _, ok := spec["paths"].(map[string]interface{})["/users"]; !ok {
+		t.Error("spec missing /users from the base-relative DTOs directory")
+	}
+}
+
+func TestGenerateOpenAPISpec_TagDescriptions(t *testing.T) {
+	tempDir := t.TempDir()
+
+	files := map[string]string{
+		"user.go": `package dto
+
+// UserDTO is a registered account.
+type UserDTO struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}`,
+		"product.go": `package dto
+
+// ProductDTO is an item of the catalog.
+type ProductDTO struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}`,
+		"order.go": `package dto
+
+type OrderDTO struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	spec, err := generateOpenAPISpec(fiber.New(), GeneratorConfig{
+		DTOsDirectory:   tempDir,
+		TagDescriptions: map[string]string{"Product": "Things we sell"},
+	})
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"name": "Order"},
+		{"name": "Product", "description": "Things we sell"},
+		{"name": "User", "description": "UserDTO is a registered account."},
+	}
+	if got := spec["tags"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateOpenAPISpec_TagMetadata(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	app.Get("/health", func(c fiber.Ctx) error { return nil })
+	cfg.TagDescriptions = map[string]string{"User": "Registered accounts"}
+	cfg.TagExternalDocs = map[string]ExternalDocs{
+		"Product": {URL: "https://example.com/catalog", Description: "Catalog guide"},
+		"User":    {Description: "No URL, dropped"},
+	}
+	cfg.TagOrder = []string{"User", "Missing", "System"}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"name": "User", "description": "Registered accounts"},
+		{"name": "System"},
+		{"name": "Product", "externalDocs": ExternalDocs{URL: "https://example.com/catalog", Description: "Catalog guide"}},
+	}
+	if got := spec["tags"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("tags = %v, want %v", got, want)
+	}
+}
+
+func TestGenerateOpenAPISpec_DirectoryTags(t *testing.T) {
+	dtosDir := t.TempDir()
+	files := map[string]string{
+		"user.go":            "UserDTO",
+		"billing/invoice.go": "InvoiceDTO",
+		"billing/refund.go":  "RefundDTO",
+	}
+	for name, dto := range files {
+		path := filepath.Join(dtosDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		content := "package dto\n\ntype " + dto + " struct {\n\tID int64 `json:\"id\"`\n}\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	spec, err := generateOpenAPISpec(fiber.New(), GeneratorConfig{
+		DTOsDirectory: dtosDir,
+		RecursiveDTOs: true,
+		DirectoryTags: true,
+	})
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	tests := []struct {
+		path    string
+		wantTag string
+	}{
+		{path: "/users", wantTag: "User"},
+		{path: "/users/{id}", wantTag: "User"},
+		{path: "/invoices", wantTag: "Billing"},
+		{path: "/refunds/{id}", wantTag: "Billing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			item, ok := paths[tt.path].(map[string]interface{})
+			if !ok {
+				t.Fatalf("spec missing %s", tt.path)
+			}
+			tags := item["get"].(map[string]interface{})["tags"].([]string)
+			if !reflect.DeepEqual(tags, []string{tt.wantTag}) {
+				t.Errorf("%s tags = %v, want [%s]", tt.path, tags, tt.wantTag)
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPISpec_ListVariant(t *testing.T) {
+	tempDir := t.TempDir()
+
+	userContent := `package dto
+
+type UserDTO struct {
+	ID    int64  ` + "`json:\"id\"`" + `
+	Name  string ` + "`json:\"name\"`" + `
+	Email string ` + "`json:\"email\"`" + `
+}
+
+type UserCreateDTO struct {
+	Name  string ` + "`json:\"name\"`" + `
+	Email string ` + "`json:\"email\"`" + `
+}
+
+type UserUpdateDTO struct {
+	Name string ` + "`json:\"name\"`" + `
+}
+
+type UserListItemDTO struct {
+	ID   int64  ` + "`json:\"id\"`" + `
+	Name string ` + "`json:\"name\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "user.go"), []byte(userContent), 0644); err != nil {
+		t.Fatalf("Failed to create user.go: %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(fiber.New(), GeneratorConfig{
+		DTOsDirectory:    tempDir,
+		CollectionFormat: CollectionFormatLinkHeader,
+	})
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	user := schemas["User"].(map[string]interface{})["properties"].(map[string]interface{})
+	if _, ok := user["email"]; !ok {
+		t.Error("User schema should be built from UserDTO")
+	}
+	listItem, ok := schemas["UserListItem"].(map[string]interface{})
+	if !ok {
+		t.Fatal("components/schemas missing UserListItem")
+	}
+	if _, ok := listItem["properties"].(map[string]interface{})["email"]; ok {
+		t.Error("UserListItem schema should be built from UserListItemDTO")
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	list := paths["/users"].(map[string]interface{})["get"].(map[string]interface{})
+	ok200 := list["responses"].(map[string]interface{})["200"].(map[string]interface{})
+	items := ok200["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})["items"]
+	if want := map[string]string{"$ref": "#/components/schemas/UserListItem"}; !reflect.DeepEqual(items, want) {
+		t.Errorf("GET /users items = %v, want %v", items, want)
+	}
+
+	get := paths["/users/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	item := get["responses"].(map[string]interface{})["200"].(map[string]interface{})
+	schema := item["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"]
+	if want := map[string]string{"$ref": "#/components/schemas/User"}; !reflect.DeepEqual(schema, want) {
+		t.Errorf("GET /users/{id} schema = %v, want %v", schema, want)
+	}
+}
+
+func TestGenerateOpenAPISpec_SecuritySchemes(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	cfg.Security = []string{"oauth", "basicAuth"}
+	cfg.SecuritySchemes = map[string]SecurityScheme{
+		"partnerKey": {Type: "apiKey", In: "query", Name: "api_key"},
+		"basicAuth":  {Type: "http", Scheme: "basic"},
+		"oauth": {Type: "oauth2", Flows: &OAuthFlows{
+			ClientCredentials: &OAuthFlow{TokenURL: "https://auth.example.com/token", Scopes: map[string]string{"orders:read": "Read orders"}},
+		}},
+		"oidc": {Type: "openIdConnect", OpenIDConnectURL: "https://auth.example.com/.well-known/openid-configuration"},
+	}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	wantGlobal := []map[string]interface{}{{"oauth": []string{}}, {"basicAuth": []string{}}}
+	if !reflect.DeepEqual(spec["security"], wantGlobal) {
+		t.Errorf("security = %v, want %v", spec["security"], wantGlobal)
+	}
+
+	raw, err := json.Marshal(spec["components"].(map[string]interface{})["securitySchemes"])
+	if err != nil {
+		t.Fatalf("Failed to marshal securitySchemes: %v", err)
+	}
+	want := `{"basicAuth":{"type":"http","scheme":"basic"},` +
+		`"bearerAuth":{"bearerFormat":"JWT","description":"JWT authentication token","scheme":"bearer","type":"http"},` +
+		`"oauth":{"type":"oauth2","flows":{"clientCredentials":{"tokenUrl":"https://auth.example.com/token","scopes":{"orders:read":"Read orders"}}}},` +
+		`"oidc":{"type":"openIdConnect","openIdConnectUrl":"https://auth.example.com/.well-known/openid-configuration"},` +
+		`"partnerKey":{"type":"apiKey","name":"api_key","in":"query"}}`
+	if string(raw) != want {
+		t.Errorf("securitySchemes = %s, want %s", raw, want)
+	}
+}
+
+func TestGenerateOpenAPISpec_SecurityScopes(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	cfg.Security = []string{"oauth", "bearerAuth"}
+	cfg.SecuritySchemes = map[string]SecurityScheme{
+		"oauth": {Type: "oauth2", Flows: &OAuthFlows{
+			ClientCredentials: &OAuthFlow{TokenURL: "https://auth.example.com/token", Scopes: map[string]string{
+				"users:read":  "Read users",
+				"users:write": "Manage users",
+			}},
+		}},
+	}
+	cfg.SecurityScopes = map[string][]string{"oauth": {"users:read"}}
+	cfg.OperationSecurity = map[string][]string{"GET /products": {"oauth", ""}}
+	cfg.OperationScopes = map[string][]string{
+		"POST /users":   {"users:write"},
+		"GET /products": {},
+	}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	wantGlobal := []map[string]interface{}{{"oauth": []string{"users:read"}}, {"bearerAuth": []string{}}}
+	if !reflect.DeepEqual(spec["security"], wantGlobal) {
+		t.Errorf("security = %v, want %v", spec["security"], wantGlobal)
+	}
+
+	tests := []struct {
+		path   string
+		method string
+		want   interface{}
+	}{
+		{path: "/users", method: "get", want: nil},
+		{path: "/users", method: "post", want: []map[string]interface{}{{"oauth": []string{"users:write"}}, {"bearerAuth": []string{}}}},
+		{path: "/products", method: "get", want: []map[string]interface{}{{"oauth": []string{}}, {}}},
+	}
+	for _, tt := range tests {
+		op := spec["paths"].(map[string]interface{})[tt.path].(map[string]interface{})[tt.method].(map[string]interface{})
+		if got := op["security"]; tt.want == nil && got != nil || tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s %s security = %v, want %v", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateOpenAPISpec_NoGlobalSecurity(t *testing.T) {
+	tests := []struct {
+		name        string
+		apiKey      string
+		wantSchemes []string
+	}{
+		{name: "no schemes left", wantSchemes: nil},
+		{name: "api key kept for operations", apiKey: "X-API-Key", wantSchemes: []string{"apiKey"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, app, cfg := setupSpecWithMultipleResources(t)
+			cfg.NoGlobalSecurity = true
+			cfg.UnauthorizedResponse = true
+			cfg.APIKeyHeader = tt.apiKey
+
+			spec, err := generateOpenAPISpec(app, cfg)
+			if err != nil {
+				t.Fatalf("generateOpenAPISpec() error = %v", err)
+			}
+
+			if _, ok := spec["security"]; ok {
+				t.Error("spec should not have a top-level security block")
+			}
+
+			schemes, ok := spec["components"].(map[string]interface{})["securitySchemes"].(map[string]interface{})
+			var names []string
+			for name := range schemes {
+				names = append(names, name)
+			}
+			if ok != (tt.wantSchemes != nil) || !reflect.DeepEqual(names, tt.wantSchemes) {
+				t.Errorf("securitySchemes = %v, want %v", names, tt.wantSchemes)
+			}
+
+			listUsers := spec["paths"].(map[string]interface{})["/users"].(map[string]interface{})["get"].(map[string]interface{})
+			if _, ok := listUsers["responses"].(map[string]interface{})["401"]; ok {
+				t.Error("public operations should not document 401")
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPISpec_OperationIDs(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	cfg.CountHead = true
+	app.Get("/health", func(c fiber.Ctx) error { return nil })
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	want := map[string]string{
+		"GET /users":         "listUsers",
+		"POST /users":        "createUsers",
+		"HEAD /users":        "countUsers",
+		"GET /users/{id}":    "getUser",
+		"PUT /users/{id}":    "updateUser",
+		"DELETE /users/{id}": "deleteUser",
+		"GET /health":        "getHealth",
+	}
+	for operation, wantID := range want {
+		method, path, _ := strings.Cut(operation, " ")
+		op, ok := paths[path].(map[string]interface{})[strings.ToLower(method)].(map[string]interface{})
+		if !ok {
+			t.Errorf("missing operation %s", operation)
+			continue
+		}
+		if op["operationId"] != wantID {
+			t.Errorf("%s operationId = %v, want %s", operation, op["operationId"], wantID)
+		}
+	}
+
+	seen := map[string]string{}
+	forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+		id, _ := op["operationId"].(string)
+		if id == "" {
+			t.Errorf("%s %s has no operationId", method, path)
+		}
+		if other, ok := seen[id]; ok {
+			t.Errorf("operationId %s used by both %s and %s %s", id, other, method, path)
+		}
+		seen[id] = method + " " + path
+	})
+}
+
+func TestGenerateOpenAPISpec_OperationTexts(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	cfg.OperationTexts = map[string]OperationText{
+		OperationTextList: {Summary: "All {plural}"},
+		OperationTextGet:  {Summary: "Fetch a {name}", Description: "Returns one {name}"},
+	}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	list := paths["/users"].(map[string]interface{})["get"].(map[string]interface{})
+	if list["summary"] != "All users" || list["description"] != "Retrieve a list of users" {
+		t.Errorf("GET /users = %q / %q, want the list summary override and default description", list["summary"], list["description"])
+	}
+	get := paths["/users/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	if get["summary"] != "Fetch a user" || get["description"] != "Returns one user" {
+		t.Errorf("GET /users/{id} = %q / %q, want both overrides", get["summary"], get["description"])
+	}
+}
+
+func TestGenerateOpenAPISpec_Locale(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	cfg.Locale = "fr"
+	cfg.OperationTexts = map[string]OperationText{OperationTextGet: {Summary: "Fetch a {name}"}}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	list := paths["/users"].(map[string]interface{})["get"].(map[string]interface{})
+	if list["summary"] != "Lister les users" {
+		t.Errorf("GET /users summary = %q, want %q", list["summary"], "Lister les users")
+	}
+	limit := list["parameters"].([]map[string]interface{})[0]
+	if want := "Nombre maximum d'éléments à retourner (par défaut : 20, max : 100)"; limit["description"] != want {
+		t.Errorf("limit description = %q, want %q", limit["description"], want)
+	}
+	get := paths["/users/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	if get["summary"] != "Fetch a user" || get["description"] != "Récupérer un élément user par ID" {
+		t.Errorf("GET /users/{id} = %q / %q, want the override and the localized description", get["summary"], get["description"])
+	}
+}
+
+func TestGenerateOpenAPISpec_PluralOverrides(t *testing.T) {
+	tempDir, app, cfg := setupSpecWithMultipleResources(t)
+	personContent := `package dto
+
+type PersonDTO struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "person.go"), []byte(personContent), 0644); err != nil {
+		t.Fatalf("Failed to create person.go: %v", err)
+	}
+	cfg.PluralOverrides = map[string]string{"product": "catalog"}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	for _, path := range []string{"/people", "/people/{id}", "/catalog", "/catalog/{id}", "/users"} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("spec missing path %s", path)
+		}
+	}
+	if _, ok := paths["/products"]; ok {
+		t.Error("overridden resource still documented at /products")
+	}
+	list := paths["/people"].(map[string]interface{})["get"].(map[string]interface{})
+	if list["summary"] != "List people" {
+		t.Errorf("GET /people summary = %q, want %q", list["summary"], "List people")
+	}
+}
+
+func TestGenerateOpenAPISpec_PathStyle(t *testing.T) {
+	tempDir, app, cfg := setupSpecWithMultipleResources(t)
+	orderItemContent := `package dto
+
+type OrderItemDTO struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "order_item.go"), []byte(orderItemContent), 0644); err != nil {
+		t.Fatalf("Failed to create order_item.go: %v", err)
+	}
+	cfg.PathStyle = PathStyleKebabCase
+	cfg.SingularPaths = true
+	cfg.ResourcePaths = map[string]string{"product": "/catalog/products"}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	for _, path := range []string{"/order-item", "/order-item/{id}", "/user", "/catalog/products", "/catalog/products/{id}"} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("spec missing path %s", path)
+		}
+	}
+}
+
+func TestGenerateOpenAPISpec_NestedDTORefs(t *testing.T) {
+	tempDir, app, cfg := setupSpecWithMultipleResources(t)
+	postContent := `package dto
+
+type PostDTO struct {
+	ID        int64       ` + "`json:\"id\"`" + `
+	Author    AuthorDTO   ` + "`json:\"author\"`" + `
+	Reviewers []AuthorDTO ` + "`json:\"reviewers\"`" + `
+	Owner     *UserDTO    ` + "`json:\"owner\"`" + `
+}
+
+type AuthorDTO struct {
+	Name string ` + "`json:\"name\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "post.go"), []byte(postContent), 0644); err != nil {
+		t.Fatalf("Failed to create post.go: %v", err)
+	}
+	cfg.MainDTO = map[string]string{"post": "PostDTO"}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	author, ok := schemas["Author"].(map[string]interface{})
+	if !ok {
+		t.Fatal("referenced AuthorDTO not registered as the Author component")
+	}
+	if _, ok := author["properties"].(map[string]interface{})["name"]; !ok {
+		t.Errorf("Author schema = %v, want its name property", author)
+	}
+
+	properties := schemas["Post"].(map[string]interface{})["properties"].(map[string]interface{})
+	authorRef := map[string]interface{}{"$ref": "#/components/schemas/Author"}
+	if !reflect.DeepEqual(properties["author"], authorRef) {
+		t.Errorf("author = %v, want %v", properties["author"], authorRef)
+	}
+	if items := properties["reviewers"].(map[string]interface{})["items"]; !reflect.DeepEqual(items, authorRef) {
+		t.Errorf("reviewers items = %v, want %v", items, authorRef)
+	}
+	wantOwner := map[string]interface{}{
+		"allOf":    []interface{}{map[string]interface{}{"$ref": "#/components/schemas/User"}},
+		"nullable": true,
+	}
+	if !reflect.DeepEqual(properties["owner"], wantOwner) {
+		t.Errorf("owner = %v, want %v", properties["owner"], wantOwner)
+	}
+}
+
+func TestGenerateOpenAPISpec_RecursiveDTOs(t *testing.T) {
+	tempDir, app, cfg := setupSpecWithMultipleResources(t)
+	categoryContent := `package dto
+
+type CategoryDTO struct {
+	Name     string        ` + "`json:\"name\"`" + `
+	Parent   *CategoryDTO  ` + "`json:\"parent\"`" + `
+	Children []CategoryDTO ` + "`json:\"children\"`" + `
+	Root     TreeNodeDTO   ` + "`json:\"root\"`" + `
+}
+
+type TreeNodeDTO struct {
+	Nodes []TreeNodeDTO ` + "`json:\"nodes\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "category.go"), []byte(categoryContent), 0644); err != nil {
+		t.Fatalf("Failed to create category.go: %v", err)
+	}
+	cfg.MainDTO = map[string]string{"category": "CategoryDTO"}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+	if _, err := json.Marshal(spec); err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	properties := schemas["Category"].(map[string]interface{})["properties"].(map[string]interface{})
+	categoryRef := map[string]interface{}{"$ref": "#/components/schemas/Category"}
+	wantParent := map[string]interface{}{"allOf": []interface{}{categoryRef}, "nullable": true}
+	if !reflect.DeepEqual(properties["parent"], wantParent) {
+		t.Errorf("parent = %v, want %v", properties["parent"], wantParent)
+	}
+	if items := properties["children"].(map[string]interface{})["items"]; !reflect.DeepEqual(items, categoryRef) {
+		t.Errorf("children items = %v, want %v", items, categoryRef)
+	}
+
+	treeNode, ok := schemas["TreeNode"].(map[string]interface{})
+	if !ok {
+		t.Fatal("referenced TreeNodeDTO not registered as the TreeNode component")
+	}
+	nodes := treeNode["properties"].(map[string]interface{})["nodes"].(map[string]interface{})
+	if want := map[string]interface{}{"$ref": "#/components/schemas/TreeNode"}; !reflect.DeepEqual(nodes["items"], want) {
+		t.Errorf("nodes items = %v, want %v", nodes["items"], want)
+	}
+}
+
+func TestGenerateOpenAPISpec_NamedTypes(t *testing.T) {
+	tempDir, app, cfg := setupSpecWithMultipleResources(t)
+	if err := os.WriteFile(filepath.Join(tempDir, "types.go"), []byte("package dto\n\ntype Email string\n\ntype Status int\n\nconst (\n\tStatusActive Status = iota + 1\n\tStatusClosed\n)\n"), 0644); err != nil {
+		t.Fatalf("Failed to create types.go: %v", err)
+	}
+	accountContent := `package dto
+
+type AccountDTO struct {
+	Email  Email  ` + "`json:\"email\"`" + `
+	Status Status ` + "`json:\"status\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "account.go"), []byte(accountContent), 0644); err != nil {
+		t.Fatalf("Failed to create account.go: %v", err)
+	}
+	cfg.TypeFormats = map[string]string{"Email": "email"}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	properties := schemas["Account"].(map[string]interface{})["properties"].(map[string]interface{})
+	if want := map[string]interface{}{"type": "string", "format": "email"}; !reflect.DeepEqual(properties["email"], want) {
+		t.Errorf("email = %v, want %v", properties["email"], want)
+	}
+	if want := map[string]interface{}{"type": "integer", "format": "int32", "enum": []interface{}{int64(1), int64(2)}}; !reflect.DeepEqual(properties["status"], want) {
+		t.Errorf("status = %v, want %v", properties["status"], want)
+	}
+}
+
+func TestGenerateOpenAPISpec_InlineStructs(t *testing.T) {
+	tempDir, app, cfg := setupSpecWithMultipleResources(t)
+	customerContent := `package dto
+
+type Contacts map[string]struct {
+	Phone string ` + "`json:\"phone\"`" + `
+}
+
+type CustomerDTO struct {
+	Address struct {
+		City string  ` + "`json:\"city\"`" + `
+		Zip  *string ` + "`json:\"zip\"`" + `
+	} ` + "`json:\"address\"`" + `
+	Contacts Contacts ` + "`json:\"contacts\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "customer.go"), []byte(customerContent), 0644); err != nil {
+		t.Fatalf("Failed to create customer.go: %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	properties := schemas["Customer"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	address, ok := properties["address"].(map[string]interface{})
+	if !ok || address["type"] != "object" {
+		t.Fatalf("address = %v, want an inline object schema", properties["address"])
+	}
+	addressProperties := address["properties"].(map[string]interface{})
+	if want := map[string]interface{}{"type": "string"}; !reflect.DeepEqual(addressProperties["city"], want) {
+		t.Errorf("address.city = %v, want %v", addressProperties["city"], want)
+	}
+	if _, ok := addressProperties["zip"]; !ok {
+		t.Error("address schema missing the zip property")
+	}
+	if want := []string{"city"}; !reflect.DeepEqual(address["required"], want) {
+		t.Errorf("address required = %v, want %v", address["required"], want)
+	}
+
+	contacts, ok := properties["contacts"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("contacts = %v, want an object schema", properties["contacts"])
+	}
+	values, _ := contacts["additionalProperties"].(map[string]interface{})
+	valueProperties, _ := values["properties"].(map[string]interface{})
+	if want := map[string]interface{}{"type": "string"}; !reflect.DeepEqual(valueProperties["phone"], want) {
+		t.Errorf("contacts values = %v, want objects with a phone string", contacts["additionalProperties"])
+	}
+}
+
+func TestGenerateOpenAPISpec_UnionTypes(t *testing.T) {
+	tempDir, app, cfg := setupSpecWithMultipleResources(t)
+	paymentContent := `package dto
+
+type CardPaymentDTO struct {
+	Number string ` + "`json:\"number\"`" + `
+}
+
+type BankPaymentDTO struct {
+	IBAN string ` + "`json:\"iban\"`" + `
+}
+
+type PaymentDTO struct {
+	Method interface{}  ` + "`json:\"method\" oneof_types:\"CardPaymentDTO,BankPaymentDTO\"`" + `
+	Notes  *interface{} ` + "`json:\"notes\" anyof_types:\"string,int64\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "payment.go"), []byte(paymentContent), 0644); err != nil {
+		t.Fatalf("Failed to create payment.go: %v", err)
+	}
+	cfg.MainDTO = map[string]string{"payment": "PaymentDTO"}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for _, name := range []string{"CardPayment", "BankPayment"} {
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("union member %s not registered as a component", name)
+		}
+	}
+
+	properties := schemas["Payment"].(map[string]interface{})["properties"].(map[string]interface{})
+	wantMethod := map[string]interface{}{"oneOf": []interface{}{
+		map[string]interface{}{"$ref": "#/components/schemas/CardPayment"},
+		map[string]interface{}{"$ref": "#/components/schemas/BankPayment"},
+	}}
+	if !reflect.DeepEqual(properties["method"], wantMethod) {
+		t.Errorf("method = %v, want %v", properties["method"], wantMethod)
+	}
+	wantNotes := map[string]interface{}{
+		"anyOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "integer", "format": "int64"},
+		},
+		"nullable": true,
+	}
+	if !reflect.DeepEqual(properties["notes"], wantNotes) {
+		t.Errorf("notes = %v, want %v", properties["notes"], wantNotes)
+	}
+}
+
+func TestGenerateOpenAPISpec_EmbeddedStructs(t *testing.T) {
+	tempDir, app, cfg := setupSpecWithMultipleResources(t)
+	orderContent := `package dto
+
+type Timestamps struct {
+	CreatedAt string ` + "`json:\"created_at\"`" + `
+}
+
+type OrderDTO struct {
+	Timestamps
+	BaseDTO
+	Total int ` + "`json:\"total\"`" + `
+}`
+	baseContent := `package dto
+
+type BaseDTO struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}`
+	for name, content := range map[string]string{"order.go": orderContent, "base.go": baseContent} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	t.Run("flatten", func(t *testing.T) {
+		spec, err := generateOpenAPISpec(app, cfg)
+		if err != nil {
+			t.Fatalf("generateOpenAPISpec() error = %v", err)
+		}
+		schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+		properties := schemas["Order"].(map[string]interface{})["properties"].(map[string]interface{})
+		for _, name := range []string{"id", "created_at", "total"} {
+			if _, ok := properties[name]; !ok {
+				t.Errorf("Order schema missing promoted property %q", name)
+			}
+		}
+		if _, ok := schemas["Timestamps"]; ok {
+			t.Error("flattened embedded struct registered as a component")
+		}
+	})
+
+	t.Run("allOf", func(t *testing.T) {
+		cfg := cfg
+		cfg.EmbeddedStructs = EmbeddedAllOf
+		spec, err := generateOpenAPISpec(app, cfg)
+		if err != nil {
+			t.Fatalf("generateOpenAPISpec() error = %v", err)
+		}
+		schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+		allOf, ok := schemas["Order"].(map[string]interface{})["allOf"].([]interface{})
+		if !ok || len(allOf) != 3 {
+			t.Fatalf("Order schema = %v, want allOf of both embedded structs and its own fields", schemas["Order"])
+		}
+		refs := []interface{}{allOf[0].(map[string]interface{})["$ref"], allOf[1].(map[string]interface{})["$ref"]}
+		want := []interface{}{"#/components/schemas/Timestamps", "#/components/schemas/Base"}
+		if !reflect.DeepEqual(refs, want) {
+			t.Errorf("allOf refs = %v, want %v", refs, want)
+		}
+		for _, name := range []string{"Timestamps", "Base"} {
+			if _, ok := schemas[name]; !ok {
+				t.Errorf("embedded struct %s not registered as a component", name)
+			}
+		}
+	})
+}
+
+func TestGenerateOpenAPISpec_DTOsFS(t *testing.T) {
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	cfg.DTOsDirectory = "dtos"
+	cfg.DTOsFS = fstest.MapFS{
+		"dtos/invoice.go": {Data: []byte("package dto\n\ntype InvoiceDTO struct {\n\tTotal int `json:\"total\"`\n}\n")},
+	}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	if _, ok := schemas["Invoice"]; !ok {
+		t.Errorf("schemas = %v, want the Invoice schema read from the file system", sortedKeys(schemas))
+	}
+	if _, ok := schemas["User"]; ok {
+		t.Error("DTOs on disk must be ignored when DTOsFS is set")
+	}
+}
+
+func TestGenerateOpenAPISpec_RequestDTOs(t *testing.T) {
+	tempDir, app, cfg := setupSpecWithMultipleResources(t)
+	accountContent := `package dto
+
+type AccountDTO struct {
+	ID    string ` + "`json:\"id\"`" + `
+	Email string ` + "`json:\"email\"`" + `
+}
+
+type CreateAccountDTO struct {
+	ID       string ` + "`json:\"id\"`" + `
+	Email    string ` + "`json:\"email\"`" + `
+	Password string ` + "`json:\"password\"`" + `
+}
+
+type UpdateAccountDTO struct {
+	Email *string ` + "`json:\"email,omitempty\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "account.go"), []byte(accountContent), 0644); err != nil {
+		t.Fatalf("Failed to create account.go: %v", err)
+	}
+	cfg.BatchCreate = []string{"account", "product"}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	create, ok := schemas["CreateAccountRequest"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("schemas = %v, want CreateAccountRequest", sortedKeys(schemas))
+	}
+	if got := sortedKeys(create["properties"].(map[string]interface{})); !reflect.DeepEqual(got, []string{"email", "password"}) {
+		t.Errorf("CreateAccountRequest properties = %v, want the read-only id stripped", got)
+	}
+	if _, ok := schemas["UpdateAccountRequest"]; !ok {
+		t.Errorf("schemas = %v, want UpdateAccountRequest", sortedKeys(schemas))
+	}
+
+	bodyRef := func(path, method string) interface{} {
+		operation := spec["paths"].(map[string]interface{})[path].(map[string]interface{})[method].(map[string]interface{})
+		content := operation["requestBody"].(map[string]interface{})["content"].(map[string]interface{})
+		return content["application/json"].(map[string]interface{})["schema"]
+	}
+	tests := []struct {
+		path, method string
+		want         string
+	}{
+		{"/accounts", "post", "#/components/schemas/CreateAccountRequest"},
+		{"/accounts/{id}", "put", "#/components/schemas/UpdateAccountRequest"},
+		{"/products", "post", "#/components/schemas/Product"},
+		{"/products/{id}", "put", "#/components/schemas/Product"},
+	}
+	for _, tt := range tests {
+		if got := bodyRef(tt.path, tt.method); !reflect.DeepEqual(got, map[string]string{"$ref": tt.want}) {
+			t.Errorf("%s %s body = %v, want %s", tt.method, tt.path, got, tt.want)
+		}
+	}
+
+	batch := bodyRef("/accounts/batch", "post").(map[string]interface{})
+	if want := map[string]string{"$ref": "#/components/schemas/CreateAccountRequest"}; !reflect.DeepEqual(batch["items"], want) {
+		t.Errorf("batch body items = %v, want %v", batch["items"], want)
+	}
+}
+
+func TestGenerateOpenAPISpec_DTOTagContexts(t *testing.T) {
+	tempDir, app, cfg := setupSpecWithMultipleResources(t)
+	memberContent := `package dto
+
+type MemberDTO struct {
+	ID       string ` + "`json:\"id\" dto:\"read\"`" + `
+	Email    string ` + "`json:\"email\"`" + `
+	Password string ` + "`json:\"password\" dto:\"create\"`" + `
+	Role     string ` + "`json:\"role\" dto:\"read,update\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "member.go"), []byte(memberContent), 0644); err != nil {
+		t.Fatalf("Failed to create member.go: %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	tests := []struct {
+		schema string
+		want   []string
+	}{
+		{schema: "Member", want: []string{"email", "id", "role"}},
+		{schema: "CreateMemberRequest", want: []string{"email", "password"}},
+		{schema: "UpdateMemberRequest", want: []string{"email", "role"}},
+	}
+	for _, tt := range tests {
+		schema, ok := schemas[tt.schema].(map[string]interface{})
+		if !ok {
+			t.Errorf("schemas = %v, want %s", sortedKeys(schemas), tt.schema)
+			continue
+		}
+		if got := sortedKeys(schema["properties"].(map[string]interface{})); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s properties = %v, want %v", tt.schema, got, tt.want)
+		}
+	}
+
+	post := spec["paths"].(map[string]interface{})["/members"].(map[string]interface{})["post"].(map[string]interface{})
+	body := post["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})
+	if want := map[string]string{"$ref": "#/components/schemas/CreateMemberRequest"}; !reflect.DeepEqual(body["schema"], want) {
+		t.Errorf("POST /members body = %v, want %v", body["schema"], want)
+	}
+}
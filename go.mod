@@ -5,13 +5,15 @@ go 1.26
 toolchain go1.26.5
 
 require (
+	github.com/andybalholm/brotli v1.2.2
+	github.com/fsnotify/fsnotify v1.10.1
 	github.com/gofiber/fiber/v3 v3.4.0
 	github.com/google/uuid v1.6.0
 	github.com/nicolasbonnici/gorest v0.6.4
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
-	github.com/andybalholm/brotli v1.2.2 // indirect
 	github.com/gofiber/schema v1.8.2 // indirect
 	github.com/gofiber/utils/v2 v2.2.0 // indirect
 	github.com/klauspost/compress v1.19.0 // indirect
@@ -0,0 +1,145 @@
+package openapi
+
+import "sort"
+
+// DefaultLocale is the locale of generated texts when GeneratorConfig.Locale
+// is empty, and the fallback for messages missing from another locale.
+const DefaultLocale = "en"
+
+// Keys of the messages generated texts are built from. Operation summaries
+// and descriptions use "<operation>.summary" and "<operation>.description",
+// with the OperationText constants as operation (e.g. "list.summary").
+const (
+	messageLimitParam  = "param.limit"
+	messageOffsetParam = "param.offset"
+	messageCountParam  = "param.count"
+	messageExpandParam = "param.expand"
+	messageIDParam     = "param.id"
+	messagePathParam   = "param.path"
+	messageRequestBody = "request_body"
+	// Followed by the HTTP method: route.action.GET.
+	messageRouteAction = "route.action."
+	// Followed by the action: request_body.create.
+	messageBodyAction = "request_body."
+)
+
+// builtinCatalogs holds the messages shipped for each supported locale.
+// GeneratorConfig.Translations adds locales or overrides single messages.
+var builtinCatalogs = map[string]map[string]string{
+	"en": {
+		"list.summary":             "List {plural}",
+		"list.description":         "Retrieve a list of {plural}",
+		"create.summary":           "Create {name}",
+		"create.description":       "Create a new {name}",
+		"get.summary":              "Get {name} by ID",
+		"get.description":          "Retrieve a single {name} by ID",
+		"update.summary":           "Update {name} by ID",
+		"update.description":       "Update an existing {name}",
+		"delete.summary":           "Delete {name} by ID",
+		"delete.description":       "Delete an existing {name}",
+		"batch_create.summary":     "Create {plural} in batch",
+		"batch_create.description": "Create several {plural} in a single request",
+		"count.summary":            "Count {plural}",
+		"count.description":        "Retrieve the total number of {plural} in the X-Total-Count header",
+		"check.summary":            "Check {name} existence",
+		"check.description":        "Check whether a {name} exists without retrieving it",
+		"route.summary":            "{action} {words}",
+		"route.description":        "{method} {path}",
+		"route.action.GET":         "Get",
+		"route.action.POST":        "Create or execute",
+		"route.action.PUT":         "Update",
+		"route.action.PATCH":       "Partially update",
+		"route.action.DELETE":      "Delete",
+		"param.limit":              "Maximum number of items to return (default: {default}, max: {max})",
+		"param.offset":             "Number of items to skip (default: 0)",
+		"param.count":              "Include total count in response (adds hydra:totalItems field)",
+		"param.expand":             "Comma-separated list of relations to expand",
+		"param.id":                 "Resource ID",
+		"param.path":               "Path parameter: {name}",
+		"request_body":             "The {resource} to {action}",
+		"request_body.create":      "create",
+		"request_body.update":      "update",
+	},
+	"fr": {
+		"list.summary":             "Lister les {plural}",
+		"list.description":         "Récupérer une liste de {plural}",
+		"create.summary":           "Créer {name}",
+		"create.description":       "Créer un nouvel élément {name}",
+		"get.summary":              "Obtenir {name} par ID",
+		"get.description":          "Récupérer un élément {name} par ID",
+		"update.summary":           "Mettre à jour {name} par ID",
+		"update.description":       "Mettre à jour un élément {name} existant",
+		"delete.summary":           "Supprimer {name} par ID",
+		"delete.description":       "Supprimer un élément {name} existant",
+		"batch_create.summary":     "Créer des {plural} par lot",
+		"batch_create.description": "Créer plusieurs {plural} en une seule requête",
+		"count.summary":            "Compter les {plural}",
+		"count.description":        "Récupérer le nombre total de {plural} dans l'en-tête X-Total-Count",
+		"check.summary":            "Vérifier l'existence de {name}",
+		"check.description":        "Vérifier qu'un élément {name} existe sans le récupérer",
+		"route.summary":            "{action} {words}",
+		"route.description":        "{method} {path}",
+		"route.action.GET":         "Obtenir",
+		"route.action.POST":        "Créer ou exécuter",
+		"route.action.PUT":         "Mettre à jour",
+		"route.action.PATCH":       "Mettre à jour partiellement",
+		"route.action.DELETE":      "Supprimer",
+		"param.limit":              "Nombre maximum d'éléments à retourner (par défaut : {default}, max : {max})",
+		"param.offset":             "Nombre d'éléments à ignorer (par défaut : 0)",
+		"param.count":              "Inclure le nombre total dans la réponse (ajoute le champ hydra:totalItems)",
+		"param.expand":             "Liste des relations à développer, séparées par des virgules",
+		"param.id":                 "Identifiant de la ressource",
+		"param.path":               "Paramètre de chemin : {name}",
+		"request_body":             "L'élément {resource} à {action}",
+		"request_body.create":      "créer",
+		"request_body.update":      "mettre à jour",
+	},
+}
+
+// message returns the text of key in the configured locale, falling back to
+// DefaultLocale. Translations win over the built-in catalogs.
+func message(cfg GeneratorConfig, key string) string {
+	locale := cfg.Locale
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	for _, l := range []string{locale, DefaultLocale} {
+		if text, ok := cfg.Translations[l][key]; ok {
+			return text
+		}
+		if text, ok := builtinCatalogs[l][key]; ok {
+			return text
+		}
+	}
+	return ""
+}
+
+// knownLocale reports whether locale has a built-in or configured catalog.
+func knownLocale(locale string, translations map[string]map[string]string) bool {
+	_, builtin := builtinCatalogs[locale]
+	_, custom := translations[locale]
+	return builtin || custom
+}
+
+// supportedLocales lists the built-in and configured locales, sorted.
+func supportedLocales(translations map[string]map[string]string) []string {
+	seen := make(map[string]bool)
+	var locales []string
+	for _, catalogs := range []map[string]map[string]string{builtinCatalogs, translations} {
+		for locale := range catalogs {
+			if !seen[locale] {
+				seen[locale] = true
+				locales = append(locales, locale)
+			}
+		}
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// isOperationText reports whether operation is one of the OperationText
+// constants.
+func isOperationText(operation string) bool {
+	_, ok := builtinCatalogs[DefaultLocale][operation+".summary"]
+	return ok
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMessage(t *testing.T) {
+	translations := map[string]map[string]string{
+		"fr": {"list.summary": "Tous les {plural}"},
+		"de": {"list.summary": "{plural} auflisten"},
+	}
+
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		want   string
+	}{
+		{name: "default locale", key: "list.summary", want: "List {plural}"},
+		{name: "built-in locale", locale: "fr", key: "param.offset", want: "Nombre d'éléments à ignorer (par défaut : 0)"},
+		{name: "translation overrides built-in", locale: "fr", key: "list.summary", want: "Tous les {plural}"},
+		{name: "configured locale", locale: "de", key: "list.summary", want: "{plural} auflisten"},
+		{name: "missing message falls back to default locale", locale: "de", key: "param.id", want: "Resource ID"},
+		{name: "unknown key", locale: "fr", key: "route.action.OPTIONS", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := GeneratorConfig{Locale: tt.locale, Translations: translations}
+			if got := message(cfg, tt.key); got != tt.want {
+				t.Errorf("message(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuiltinCatalogsComplete(t *testing.T) {
+	for locale, catalog := range builtinCatalogs {
+		for key := range builtinCatalogs[DefaultLocale] {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("catalog %q misses message %q", locale, key)
+			}
+		}
+	}
+}
+
+func TestSupportedLocales(t *testing.T) {
+	translations := map[string]map[string]string{"de": {}, "fr": {}}
+
+	if got, want := supportedLocales(translations), []string{"de", "en", "fr"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("supportedLocales() = %v, want %v", got, want)
+	}
+	if !knownLocale("de", translations) || !knownLocale("fr", nil) || knownLocale("es", translations) {
+		t.Error("knownLocale() does not match the built-in and configured catalogs")
+	}
+}
@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+type resourceIndexEntry struct {
+	Name     string
+	Tag      string
+	BasePath string
+}
+
+var resourceIndexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <title>{{.Title}} - Resources</title>
+    <meta charset="utf-8"/>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+    <h1>{{.Title}}</h1>
+    <ul>
+{{- range .Resources}}
+        <li><a href="{{$.DocsPath}}#tag/{{.Tag}}">{{.Name}}</a> <code>{{.BasePath}}</code></li>
+{{- end}}
+    </ul>
+</body>
+</html>`))
+
+// resourceIndex holds the entries of the index page listed for a generation
+// of the spec.
+type resourceIndex struct {
+	built      bool
+	generation uint64
+	entries    []resourceIndexEntry
+}
+
+// loadResourceIndex lists the documented resources with their base paths,
+// using the same plugin-first, DTO-fallback resolution as the spec generator.
+func loadResourceIndex(cfg GeneratorConfig) ([]resourceIndexEntry, error) {
+	var entries []resourceIndexEntry
+
+	if pluginResources := append(loadResourcesFromPlugins(cfg.PluginRegistry), cfg.Resources...); len(pluginResources) > 0 {
+		for _, resource := range pluginResources {
+			entries = append(entries, resourceIndexEntry{
+				Name:     resource.Name,
+				Tag:      pluginResourceTags(resource, strings.ToUpper(resource.Name[:1])+resource.Name[1:])[0],
+				BasePath: joinBasePath(cfg.BasePath, resource.BasePath),
+			})
+		}
+	} else if cfg.DTOsFS != nil || cfg.DTOsDirectory != "" {
+		resources, err := loadResourceDTOsFrom(dtoSourceFor(cfg), cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load DTOs: %w", err)
+		}
+		for _, resource := range resources {
+			entries = append(entries, resourceIndexEntry{
+				Name:     resource.Name,
+				Tag:      resourceTag(resource, strings.ToUpper(resource.Name[:1])+resource.Name[1:], cfg),
+				BasePath: joinBasePath(cfg.BasePath, resourcePath(resource, cfg)),
+			})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+func renderResourceIndex(title, docsPath string, resources []resourceIndexEntry) (string, error) {
+	var buf bytes.Buffer
+	err := resourceIndexTemplate.Execute(&buf, map[string]interface{}{
+		"Title":     title,
+		"DocsPath":  docsPath,
+		"Resources": resources,
+	})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,163 @@
+package openapi
+
+import (
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestRenderResourceIndex(t *testing.T) {
+	html, err := renderResourceIndex("Shop API", "/openapi", []resourceIndexEntry{
+		{Name: "category", Tag: "Category", BasePath: "/categories"},
+		{Name: "product", Tag: "Product", BasePath: "/products"},
+	})
+	if err != nil {
+		t.Fatalf("renderResourceIndex() error = %v", err)
+	}
+
+	for _, want := range []string{"Shop API", "category", "/categories", "product", "/products", `href="/openapi#tag/Product"`} {
+		if !strings.Contains(html, want) {
+			t.Errorf("index HTML missing %q", want)
+		}
+	}
+}
+
+func TestOpenAPIPlugin_IndexPage(t *testing.T) {
+	tempDir := t.TempDir()
+	for name, dto := range map[string]string{"user.go": "UserDTO", "product.go": "ProductDTO"} {
+		content := "package dto\n\ntype " + dto + " struct {\n\tID int64 `json:\"id\"`\n}\n"
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	plugin := &OpenAPIPlugin{
+		opts: Options{
+			DTOsDirectory: tempDir,
+			Title:         "Test API",
+			IndexPage:     true,
+		},
+	}
+	app := fiber.New()
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi/index", nil))
+	if err != nil {
+		t.Fatalf("Test request failed: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("Status code = %v, want 200", resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{"user", "/users", "product", "/products"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("index page missing %q", want)
+		}
+	}
+}
+
+func TestOpenAPIPlugin_IndexPage_BasePath(t *testing.T) {
+	plugin := &OpenAPIPlugin{opts: Options{BasePath: "/api/v1", IndexPage: true}}
+	if err := plugin.RegisterResource("invoice", registeredInvoice{}); err != nil {
+		t.Fatalf("RegisterResource() error = %v", err)
+	}
+	app := fiber.New()
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi/index", nil))
+	if err != nil {
+		t.Fatalf("Test request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "<code>/api/v1/invoices</code>") {
+		t.Errorf("index page = %s, want the invoice path under the base path", body)
+	}
+}
+
+func TestOpenAPIPlugin_IndexPage_ResourceTags(t *testing.T) {
+	plugin := &OpenAPIPlugin{opts: Options{IndexPage: true}}
+	if err := plugin.RegisterResource("invoice", registeredInvoice{}, WithTags("Billing", "Invoices")); err != nil {
+		t.Fatalf("RegisterResource() error = %v", err)
+	}
+	if err := plugin.RegisterResource("receipt", registeredInvoice{}); err != nil {
+		t.Fatalf("RegisterResource() error = %v", err)
+	}
+	app := fiber.New()
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi/index", nil))
+	if err != nil {
+		t.Fatalf("Test request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{`href="/openapi#tag/Billing">invoice`, `href="/openapi#tag/Receipt">receipt`} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("index page = %s, want it to link %s", body, want)
+		}
+	}
+}
+
+func TestOpenAPIPlugin_IndexPage_Cached(t *testing.T) {
+	tempDir := t.TempDir()
+	userFile := filepath.Join(tempDir, "user.go")
+	if err := os.WriteFile(userFile, []byte("package dto\n\ntype UserDTO struct {\n\tID int64 `json:\"id\"`\n}\n"), 0644); err != nil {
+		t.Fatalf("Failed to create user.go: %v", err)
+	}
+
+	plugin := &OpenAPIPlugin{opts: Options{DTOsDirectory: tempDir, IndexPage: true}}
+	app := fiber.New()
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+	indexPage := func() string {
+		resp, err := app.Test(httptest.NewRequest("GET", "/openapi/index", nil))
+		if err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	if page := indexPage(); !strings.Contains(page, "/users") {
+		t.Fatalf("index page = %s, want the user resource", page)
+	}
+	if err := os.Remove(userFile); err != nil {
+		t.Fatalf("Failed to remove user.go: %v", err)
+	}
+	if page := indexPage(); !strings.Contains(page, "/users") {
+		t.Errorf("index page = %s, want the entries cached with the spec", page)
+	}
+
+	plugin.Invalidate()
+	if page := indexPage(); strings.Contains(page, "/users") {
+		t.Errorf("index page = %s, want the entries listed again once the spec is invalidated", page)
+	}
+}
+
+func TestOpenAPIPlugin_IndexPageDisabledByDefault(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	app := fiber.New()
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi/index", nil))
+	if err != nil {
+		t.Fatalf("Test request failed: %v", err)
+	}
+	if resp.StatusCode != 404 {
+		t.Errorf("Status code = %v, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,370 @@
+package openapi
+
+import (
+	"maps"
+	"path"
+	"slices"
+	"strings"
+)
+
+// internalMarker flags operations, schemas and properties left out of the
+// public spec.
+const internalMarker = "x-internal"
+
+// applyInternalMarkers flags with x-internal the operations whose "METHOD
+// /path" key matches one of the operation patterns (path.Match syntax, e.g.
+// "* /admin/*") and the component schemas listed by name.
+func applyInternalMarkers(paths, schemas map[string]interface{}, operations, schemaNames []string) {
+	if len(operations) > 0 {
+		forEachOperation(paths, func(p, method string, op map[string]interface{}) {
+			key := strings.ToUpper(method) + " " + p
+			for _, pattern := range operations {
+				if matched, _ := path.Match(pattern, key); matched {
+					op[internalMarker] = true
+					return
+				}
+			}
+		})
+	}
+
+	for _, name := range schemaNames {
+		if schema, ok := schemas[name].(map[string]interface{}); ok {
+			schema[internalMarker] = true
+		}
+	}
+}
+
+func isInternal(node map[string]interface{}) bool {
+	internal, _ := node[internalMarker].(bool)
+	return internal
+}
+
+// filterInternalSpec returns the public flavor of doc: operations, component
+// schemas and properties flagged x-internal are removed, along with path
+// items left without operations. References to removed schemas are pruned
+// transitively: a schema that cannot do without one (a $ref, array items or
+// an allOf member) is removed too, properties and oneOf/anyOf members holding
+// one are dropped, and so are the request and response contents of public
+// operations. Tags only internal operations used are dropped. doc itself is
+// not modified.
+func filterInternalSpec(doc map[string]interface{}) map[string]interface{} {
+	public := maps.Clone(doc)
+
+	removed := make(map[string]bool)
+	components, _ := doc["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	for name, schema := range schemas {
+		if s, ok := schema.(map[string]interface{}); ok && isInternal(s) {
+			removed[name] = true
+		}
+	}
+	for pruned := true; pruned; {
+		pruned = false
+		for name, schema := range schemas {
+			if removed[name] {
+				continue
+			}
+			if _, _, ok := publicSchema(schema, removed); !ok {
+				removed[name] = true
+				pruned = true
+			}
+		}
+	}
+
+	if schemas != nil {
+		publicSchemas := make(map[string]interface{}, len(schemas))
+		for name, schema := range schemas {
+			if !removed[name] {
+				publicSchemas[name], _, _ = publicSchema(schema, removed)
+			}
+		}
+
+		publicComponents := maps.Clone(components)
+		publicComponents["schemas"] = publicSchemas
+		public["components"] = publicComponents
+	}
+
+	if paths, ok := doc["paths"].(map[string]interface{}); ok {
+		publicPaths := make(map[string]interface{}, len(paths))
+		for p, item := range paths {
+			pathItem, ok := item.(map[string]interface{})
+			if !ok {
+				publicPaths[p] = item
+				continue
+			}
+
+			kept := make(map[string]interface{}, len(pathItem))
+			operations := 0
+			for method, operation := range pathItem {
+				op, ok := operation.(map[string]interface{})
+				if ok && isInternal(op) {
+					continue
+				}
+				if ok {
+					operations++
+					operation = publicOperation(op, removed)
+				}
+				kept[method] = operation
+			}
+			if operations > 0 {
+				publicPaths[p] = kept
+			}
+		}
+		public["paths"] = publicPaths
+
+		if tags, ok := doc["tags"].([]map[string]interface{}); ok {
+			public["tags"] = publicTags(tags, paths, publicPaths)
+		}
+	}
+
+	return public
+}
+
+// publicTags drops the tags used by operations of paths but by none of
+// publicPaths. Tags no operation uses, only given metadata, are kept.
+func publicTags(tags []map[string]interface{}, paths, publicPaths map[string]interface{}) []map[string]interface{} {
+	usedBy := func(paths map[string]interface{}) map[string]bool {
+		used := make(map[string]bool)
+		forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+			tags, _ := op["tags"].([]string)
+			for _, tag := range tags {
+				used[tag] = true
+			}
+		})
+		return used
+	}
+	used, publicUsed := usedBy(paths), usedBy(publicPaths)
+
+	kept := make([]map[string]interface{}, 0, len(tags))
+	for _, tag := range tags {
+		name, _ := tag["name"].(string)
+		if used[name] && !publicUsed[name] {
+			continue
+		}
+		kept = append(kept, tag)
+	}
+	return kept
+}
+
+// publicOperation returns op without the request and response contents whose
+// schema references a removed component schema, dropping a request body left
+// without content. Unchanged operations are returned as is.
+func publicOperation(op map[string]interface{}, removed map[string]bool) map[string]interface{} {
+	public := op
+	changed := false
+	clone := func() {
+		if !changed {
+			public = maps.Clone(op)
+			changed = true
+		}
+	}
+
+	if body, ok := op["requestBody"].(map[string]interface{}); ok {
+		if content, contentChanged := publicContent(body, removed); contentChanged {
+			clone()
+			if content == nil {
+				delete(public, "requestBody")
+			} else {
+				publicBody := maps.Clone(body)
+				publicBody["content"] = content
+				public["requestBody"] = publicBody
+			}
+		}
+	}
+
+	if responses, ok := op["responses"].(map[string]interface{}); ok {
+		var publicResponses map[string]interface{}
+		for code, response := range responses {
+			r, ok := response.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			content, contentChanged := publicContent(r, removed)
+			if !contentChanged {
+				continue
+			}
+			if publicResponses == nil {
+				publicResponses = maps.Clone(responses)
+			}
+			publicResponse := maps.Clone(r)
+			if content == nil {
+				delete(publicResponse, "content")
+			} else {
+				publicResponse["content"] = content
+			}
+			publicResponses[code] = publicResponse
+		}
+		if publicResponses != nil {
+			clone()
+			public["responses"] = publicResponses
+		}
+	}
+
+	return public
+}
+
+// publicContent returns the content of a request body or response without
+// the media types whose schema references a removed component schema, nil
+// when none is left, and whether anything was removed.
+func publicContent(node map[string]interface{}, removed map[string]bool) (map[string]interface{}, bool) {
+	content, ok := node["content"].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+
+	kept := make(map[string]interface{}, len(content))
+	changed := false
+	for mediaType, media := range content {
+		m, ok := media.(map[string]interface{})
+		if !ok {
+			kept[mediaType] = media
+			continue
+		}
+		schema, hasSchema := m["schema"]
+		if !hasSchema {
+			kept[mediaType] = media
+			continue
+		}
+		publicMedia, schemaChanged, ok := publicSchema(schema, removed)
+		if !ok {
+			changed = true
+			continue
+		}
+		if schemaChanged {
+			m = maps.Clone(m)
+			m["schema"] = publicMedia
+			changed = true
+		}
+		kept[mediaType] = m
+	}
+	if !changed {
+		return content, false
+	}
+	if len(kept) == 0 {
+		return nil, true
+	}
+	return kept, true
+}
+
+// schemaRefName returns the component schema name a $ref schema points to.
+func schemaRefName(schema interface{}) (string, bool) {
+	var ref string
+	switch s := schema.(type) {
+	case map[string]string:
+		ref = s["$ref"]
+	case map[string]interface{}:
+		ref, _ = s["$ref"].(string)
+	}
+	return strings.CutPrefix(ref, "#/components/schemas/")
+}
+
+// publicSchema returns schema without its x-internal properties and its
+// references to removed component schemas, recursing into inline objects,
+// array items, additionalProperties and allOf, oneOf and anyOf members, and
+// whether anything was removed. ok is false when the schema cannot be kept:
+// it references a removed schema itself, through its items,
+// additionalProperties or an allOf member, or through all its oneOf or anyOf
+// members. Unchanged schemas are returned as is.
+func publicSchema(schema interface{}, removed map[string]bool) (public interface{}, changed, ok bool) {
+	if name, isRef := schemaRefName(schema); isRef {
+		return schema, false, !removed[name]
+	}
+	s, isMap := schema.(map[string]interface{})
+	if !isMap {
+		return schema, false, true
+	}
+
+	stripped := s
+	set := func(key string, value interface{}) {
+		if !changed {
+			stripped = maps.Clone(s)
+			changed = true
+		}
+		stripped[key] = value
+	}
+
+	for _, key := range []string{"items", "additionalProperties"} {
+		value, exists := s[key]
+		if !exists {
+			continue
+		}
+		publicValue, valueChanged, ok := publicSchema(value, removed)
+		if !ok {
+			return schema, false, false
+		}
+		if valueChanged {
+			set(key, publicValue)
+		}
+	}
+
+	for _, keyword := range []string{"allOf", "oneOf", "anyOf"} {
+		members, exists := s[keyword].([]interface{})
+		if !exists {
+			continue
+		}
+		kept := make([]interface{}, 0, len(members))
+		membersChanged := false
+		for _, member := range members {
+			publicMember, memberChanged, ok := publicSchema(member, removed)
+			if !ok {
+				if keyword == "allOf" {
+					return schema, false, false
+				}
+				membersChanged = true
+				continue
+			}
+			membersChanged = membersChanged || memberChanged
+			kept = append(kept, publicMember)
+		}
+		if len(kept) == 0 {
+			return schema, false, false
+		}
+		if membersChanged {
+			set(keyword, kept)
+		}
+	}
+
+	properties, hasProperties := s["properties"].(map[string]interface{})
+	if !hasProperties {
+		return stripped, changed, true
+	}
+
+	publicProperties := make(map[string]interface{}, len(properties))
+	var dropped []string
+	propertiesChanged := false
+	for name, property := range properties {
+		if prop, isMap := property.(map[string]interface{}); isMap && isInternal(prop) {
+			dropped = append(dropped, name)
+			propertiesChanged = true
+			continue
+		}
+		publicProp, propChanged, ok := publicSchema(property, removed)
+		if !ok {
+			dropped = append(dropped, name)
+			propertiesChanged = true
+			continue
+		}
+		propertiesChanged = propertiesChanged || propChanged
+		publicProperties[name] = publicProp
+	}
+	if !propertiesChanged {
+		return stripped, changed, true
+	}
+	set("properties", publicProperties)
+
+	if required, isList := s["required"].([]string); isList && len(dropped) > 0 {
+		kept := make([]string, 0, len(required))
+		for _, name := range required {
+			if !slices.Contains(dropped, name) {
+				kept = append(kept, name)
+			}
+		}
+		if len(kept) == 0 {
+			delete(stripped, "required")
+		} else {
+			stripped["required"] = kept
+		}
+	}
+
+	return stripped, true, true
+}
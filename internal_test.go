@@ -0,0 +1,248 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestApplyInternalMarkers(t *testing.T) {
+	newPaths := func() map[string]interface{} {
+		return map[string]interface{}{
+			"/users": map[string]interface{}{
+				"get":  map[string]interface{}{},
+				"post": map[string]interface{}{},
+			},
+			"/admin/stats": map[string]interface{}{
+				"get": map[string]interface{}{},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		operations   []string
+		wantInternal []string
+	}{
+		{name: "none", operations: nil, wantInternal: nil},
+		{name: "exact key", operations: []string{"POST /users"}, wantInternal: []string{"POST /users"}},
+		{name: "pattern", operations: []string{"* /admin/*"}, wantInternal: []string{"GET /admin/stats"}},
+		{name: "unmatched", operations: []string{"DELETE /users"}, wantInternal: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := newPaths()
+			applyInternalMarkers(paths, map[string]interface{}{}, tt.operations, nil)
+
+			var got []string
+			forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+				if isInternal(op) {
+					got = append(got, map[string]string{"get": "GET", "post": "POST"}[method]+" "+path)
+				}
+			})
+			if !reflect.DeepEqual(got, tt.wantInternal) {
+				t.Errorf("internal operations = %v, want %v", got, tt.wantInternal)
+			}
+		})
+	}
+
+	t.Run("schemas", func(t *testing.T) {
+		schemas := map[string]interface{}{
+			"User":  map[string]interface{}{"type": "object"},
+			"Audit": map[string]interface{}{"type": "object"},
+		}
+		applyInternalMarkers(map[string]interface{}{}, schemas, nil, []string{"Audit", "Missing"})
+
+		if isInternal(schemas["User"].(map[string]interface{})) {
+			t.Error("User should stay public")
+		}
+		if !isInternal(schemas["Audit"].(map[string]interface{})) {
+			t.Error("Audit should be flagged x-internal")
+		}
+	})
+}
+
+func TestFilterInternalSpec(t *testing.T) {
+	doc := map[string]interface{}{
+		"openapi": "3.0.0",
+		"paths": map[string]interface{}{
+			"/users": map[string]interface{}{
+				"get":    map[string]interface{}{"summary": "List"},
+				"delete": map[string]interface{}{"summary": "Purge", "x-internal": true},
+			},
+			"/admin": map[string]interface{}{
+				"get": map[string]interface{}{"x-internal": true},
+			},
+		},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Audit": map[string]interface{}{"type": "object", "x-internal": true},
+				"User": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name":  map[string]interface{}{"type": "string"},
+						"notes": map[string]interface{}{"type": "string", "x-internal": true},
+						"tags": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"label":  map[string]interface{}{"type": "string"},
+									"weight": map[string]interface{}{"type": "integer", "x-internal": true},
+								},
+							},
+						},
+					},
+					"required": []string{"name", "notes"},
+				},
+				"Post": map[string]interface{}{"type": "object"},
+			},
+			"securitySchemes": map[string]interface{}{"bearerAuth": map[string]interface{}{}},
+		},
+	}
+
+	want := map[string]interface{}{
+		"openapi": "3.0.0",
+		"paths": map[string]interface{}{
+			"/users": map[string]interface{}{
+				"get": map[string]interface{}{"summary": "List"},
+			},
+		},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"User": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{"type": "string"},
+						"tags": map[string]interface{}{
+							"type": "array",
+							"items": map[string]interface{}{
+								"type": "object",
+								"properties": map[string]interface{}{
+									"label": map[string]interface{}{"type": "string"},
+								},
+							},
+						},
+					},
+					"required": []string{"name"},
+				},
+				"Post": map[string]interface{}{"type": "object"},
+			},
+			"securitySchemes": map[string]interface{}{"bearerAuth": map[string]interface{}{}},
+		},
+	}
+
+	snapshot := func() string {
+		raw, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatalf("Failed to marshal doc: %v", err)
+		}
+		return string(raw)
+	}
+
+	before := snapshot()
+	got := filterInternalSpec(doc)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filterInternalSpec() = %#v, want %#v", got, want)
+	}
+	if snapshot() != before {
+		t.Error("filterInternalSpec() should not modify its input")
+	}
+}
+
+func TestFilterInternalSpec_DanglingRefs(t *testing.T) {
+	ref := func(name string) map[string]string {
+		return map[string]string{"$ref": "#/components/schemas/" + name}
+	}
+	content := func(schema interface{}) map[string]interface{} {
+		return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+	}
+
+	doc := map[string]interface{}{
+		"paths": map[string]interface{}{
+			"/users": map[string]interface{}{
+				"get": map[string]interface{}{
+					"tags": []string{"User"},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{"description": "OK", "content": content(ref("User"))},
+						"403": map[string]interface{}{"description": "Forbidden", "content": content(ref("AuditTrail"))},
+					},
+				},
+				"post": map[string]interface{}{
+					"tags":        []string{"User"},
+					"requestBody": map[string]interface{}{"content": content(ref("Audit"))},
+				},
+			},
+			"/audits": map[string]interface{}{
+				"get": map[string]interface{}{"tags": []string{"Audit"}, "x-internal": true},
+			},
+		},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Audit":      map[string]interface{}{"type": "object", "x-internal": true},
+				"AuditTrail": map[string]interface{}{"type": "array", "items": ref("Audit")},
+				"AuditPage":  map[string]interface{}{"allOf": []interface{}{ref("AuditTrail"), map[string]interface{}{"type": "object"}}},
+				"Event": map[string]interface{}{
+					"oneOf": []interface{}{ref("Audit"), ref("User")},
+				},
+				"Entry": map[string]interface{}{
+					"anyOf": []interface{}{ref("Audit"), ref("AuditTrail")},
+				},
+				"User": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name":    map[string]interface{}{"type": "string"},
+						"history": ref("AuditTrail"),
+						"byKind":  map[string]interface{}{"type": "object", "additionalProperties": ref("Audit")},
+					},
+					"required": []string{"name", "history"},
+				},
+			},
+		},
+		"tags": []map[string]interface{}{
+			{"name": "Audit"},
+			{"name": "User"},
+			{"name": "Reports", "description": "Reporting"},
+		},
+	}
+
+	want := map[string]interface{}{
+		"paths": map[string]interface{}{
+			"/users": map[string]interface{}{
+				"get": map[string]interface{}{
+					"tags": []string{"User"},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{"description": "OK", "content": content(ref("User"))},
+						"403": map[string]interface{}{"description": "Forbidden"},
+					},
+				},
+				"post": map[string]interface{}{"tags": []string{"User"}},
+			},
+		},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Event": map[string]interface{}{"oneOf": []interface{}{ref("User")}},
+				"User": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{"type": "string"},
+					},
+					"required": []string{"name"},
+				},
+			},
+		},
+		"tags": []map[string]interface{}{
+			{"name": "User"},
+			{"name": "Reports", "description": "Reporting"},
+		},
+	}
+
+	got := filterInternalSpec(doc)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("filterInternalSpec() = %#v, want %#v", got, want)
+	}
+	if _, ok := doc["paths"].(map[string]interface{})["/users"].(map[string]interface{})["post"].(map[string]interface{})["requestBody"]; !ok {
+		t.Error("filterInternalSpec() should not modify its input")
+	}
+}
@@ -1,7 +1,12 @@
 package openapi
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/nicolasbonnici/gorest/logger"
@@ -9,15 +14,26 @@ import (
 )
 
 type OpenAPIPlugin struct {
-	paginationLimit    int
-	paginationMaxLimit int
-	dtosDirectory      string
-	pluginRegistry     *plugin.PluginRegistry
-	title              string
-	version            string
-	description        string
-	hideOnProduction   bool
-	environment        string
+	opts          Options
+	cache         *specCache
+	internalCache *specCache
+	localeCaches  map[string]localeSpecCaches
+	// resources are registered with RegisterResource, guarded by resourcesMu.
+	resources   []plugin.OpenAPIResource
+	resourcesMu sync.RWMutex
+	dtoWatcher  *dtoWatcher
+	// routeOperations are documented with Describe by "METHOD /path",
+	// guarded by routeOperationsMu.
+	routeOperations   map[string]Operation
+	routeOperationsMu sync.RWMutex
+	// mountedApps are registered with RegisterMountedApps, guarded by
+	// mountedAppsMu.
+	mountedApps   []*fiber.App
+	mountedAppsMu sync.RWMutex
+	// index caches the index page entries along the spec, guarded by
+	// indexMu.
+	index   resourceIndex
+	indexMu sync.Mutex
 }
 
 func NewPlugin() plugin.Plugin {
@@ -28,52 +44,108 @@ func (p *OpenAPIPlugin) Name() string {
 	return "openapi"
 }
 
+// Initialize configures the plugin from its config map. Unknown keys, values
+// of the wrong type and inconsistent options (see Options.Validate) are all
+// reported together, and leave the plugin unchanged.
 func (p *OpenAPIPlugin) Initialize(cfg map[string]interface{}) error {
-	if dtosDir, ok := cfg["dtos_directory"].(string); ok {
-		p.dtosDirectory = dtosDir
-	}
+	configErr := validateConfig(cfg)
 
-	if limit, ok := cfg["pagination_limit"].(int); ok {
-		p.paginationLimit = limit
-	}
-	if maxLimit, ok := cfg["pagination_max_limit"].(int); ok {
-		p.paginationMaxLimit = maxLimit
-	}
+	opts := DefaultOptions()
+	opts.decodeConfig(cfg)
 
-	if registry, ok := cfg["plugin_registry"].(*plugin.PluginRegistry); ok {
-		p.pluginRegistry = registry
+	envErr := opts.applyEnv(os.LookupEnv)
+	if err := errors.Join(configErr, envErr, opts.Validate()); err != nil {
+		return err
 	}
 
-	if title, ok := cfg["title"].(string); ok {
-		p.title = title
-	} else {
-		p.title = "GoREST API"
+	p.applyOptions(opts)
+	return nil
+}
+
+// parseServer reads a servers entry: url, description and variables, each
+// variable holding default, enum and description.
+func parseServer(raw map[string]interface{}) Server {
+	server := Server{}
+	server.URL, _ = raw["url"].(string)
+	server.Description, _ = raw["description"].(string)
+
+	variables, _ := raw["variables"].(map[string]interface{})
+	for name, value := range variables {
+		v, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		variable := ServerVariable{}
+		variable.Default, _ = v["default"].(string)
+		variable.Description, _ = v["description"].(string)
+		if enum, ok := v["enum"].([]interface{}); ok {
+			for _, option := range enum {
+				if s, ok := option.(string); ok {
+					variable.Enum = append(variable.Enum, s)
+				}
+			}
+		}
+		if server.Variables == nil {
+			server.Variables = make(map[string]ServerVariable)
+		}
+		server.Variables[name] = variable
 	}
-	if version, ok := cfg["version"].(string); ok {
-		p.version = version
-	} else {
-		p.version = "1.0.0"
+	return server
+}
+
+func parseSecurityScheme(raw map[string]interface{}) SecurityScheme {
+	scheme := SecurityScheme{}
+	scheme.Type, _ = raw["type"].(string)
+	scheme.Description, _ = raw["description"].(string)
+	scheme.Name, _ = raw["name"].(string)
+	scheme.In, _ = raw["in"].(string)
+	scheme.Scheme, _ = raw["scheme"].(string)
+	scheme.BearerFormat, _ = raw["bearer_format"].(string)
+	scheme.OpenIDConnectURL, _ = raw["openid_connect_url"].(string)
+
+	if flows, ok := raw["flows"].(map[string]interface{}); ok {
+		scheme.Flows = &OAuthFlows{
+			Implicit:          parseOAuthFlow(flows["implicit"]),
+			Password:          parseOAuthFlow(flows["password"]),
+			ClientCredentials: parseOAuthFlow(flows["client_credentials"]),
+			AuthorizationCode: parseOAuthFlow(flows["authorization_code"]),
+		}
 	}
-	if description, ok := cfg["description"].(string); ok {
-		p.description = description
-	} else {
-		p.description = "Auto-generated REST API with full CRUD operations"
+	return scheme
+}
+
+func parseOAuthFlow(value interface{}) *OAuthFlow {
+	raw, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
 	}
 
-	// Default to true to hide OpenAPI endpoints in production
-	p.hideOnProduction = true
-	if hide, ok := cfg["hide_on_production"].(bool); ok {
-		p.hideOnProduction = hide
+	flow := &OAuthFlow{Scopes: map[string]string{}}
+	flow.AuthorizationURL, _ = raw["authorization_url"].(string)
+	flow.TokenURL, _ = raw["token_url"].(string)
+	flow.RefreshURL, _ = raw["refresh_url"].(string)
+	scopes, _ := raw["scopes"].(map[string]interface{})
+	for scope, description := range scopes {
+		flow.Scopes[scope], _ = description.(string)
 	}
+	return flow
+}
 
-	// Get server environment (defaults to "development" if not set)
-	if env, ok := cfg["environment"].(string); ok {
-		p.environment = env
-	} else {
-		p.environment = "development"
+// extensionsByKey reads a map of extension maps, e.g. the extensions per
+// "METHOD /path" operation key.
+func extensionsByKey(raw interface{}) map[string]map[string]interface{} {
+	entries, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
 	}
 
-	return nil
+	byKey := make(map[string]map[string]interface{}, len(entries))
+	for key, value := range entries {
+		if extensions, ok := value.(map[string]interface{}); ok {
+			byKey[key] = extensions
+		}
+	}
+	return byKey
 }
 
 // Handler returns a no-op middleware
@@ -85,81 +157,394 @@ func (p *OpenAPIPlugin) Handler() fiber.Handler {
 
 // SetupEndpoints implements the EndpointSetup interface
 func (p *OpenAPIPlugin) SetupEndpoints(router fiber.Router) error {
-	// Environment-aware endpoint control:
-	// - Development: Always enable OpenAPI endpoints
-	// - Production: Only disable if hide_on_production=true
-	// - Other/Unknown: Enable by default
-
-	if p.environment == "production" && p.hideOnProduction {
-		logger.Log.Info("OpenAPI endpoints disabled in production (hide_on_production=true)")
+	mode := p.docsMode()
+	if mode == docsModeOff {
+		logger.Log.Info("OpenAPI endpoints disabled", "environment", p.opts.Environment)
+		// the spec file is still written, e.g. for a gateway or client
+		// generator reading it in production
+		if p.opts.OutputFile != "" {
+			p.internalCache, p.cache = p.newSpecCaches(router, p.opts.Locale)
+			return p.writeOutputFile()
+		}
 		return nil
 	}
 
-	// Setup OpenAPI UI endpoint
-	router.Get("/openapi", func(c fiber.Ctx) error {
-		// Override CSP to allow loading external scripts and styles for Scalar UI
-		c.Set("Content-Security-Policy",
-			"default-src 'self'; "+
-				"script-src 'self' 'unsafe-inline' 'unsafe-eval' https://cdn.jsdelivr.net; "+
-				"style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net; "+
-				"font-src 'self' https://cdn.jsdelivr.net data:; "+
-				"img-src 'self' data: https:; "+
-				"connect-src 'self' https:;")
-
-		html := `<!DOCTYPE html>
-<html>
-<head>
-    <title>GoREST API Documentation</title>
-    <meta charset="utf-8"/>
-    <meta name="viewport" content="width=device-width, initial-scale=1">
-    <style>
-        body {
-            margin: 0;
-            padding: 0;
-        }
-    </style>
-</head>
-<body>
-    <script id="api-reference" data-url="/openapi.json"></script>
-    <script src="https://cdn.jsdelivr.net/npm/@scalar/api-reference"></script>
-</body>
-</html>`
-		c.Set("Content-Type", "text/html")
-		return c.SendString(html)
+	docs := resolveDocPaths(p.opts.DocsPath, p.opts.SpecPath)
+	guards := p.docsGuards()
+
+	if mode == docsModeFull {
+		if err := p.setupUIEndpoints(router, docs, guards); err != nil {
+			return err
+		}
+	}
+
+	if p.opts.InternalSpecPath != "" && p.opts.InternalDocsToken == "" {
+		return fmt.Errorf("internal_spec_path %s requires an internal_docs_token", p.opts.InternalSpecPath)
+	}
+
+	p.internalCache, p.cache = p.newSpecCaches(router, p.opts.Locale)
+	p.localeCaches = make(map[string]localeSpecCaches, len(p.opts.Locales))
+	for _, locale := range p.opts.Locales {
+		if locale == p.opts.Locale {
+			continue
+		}
+		internal, public := p.newSpecCaches(router, locale)
+		p.localeCaches[locale] = localeSpecCaches{internal: internal, public: public}
+	}
+
+	if p.opts.WatchDTOs {
+		if err := p.startDTOWatcher(); err != nil {
+			return fmt.Errorf("failed to watch DTOs directory: %w", err)
+		}
+	}
+
+	if p.opts.OutputFile != "" {
+		if err := p.writeOutputFile(); err != nil {
+			return err
+		}
+	}
+
+	getGuarded(router, docs.JSON, guards, func(c fiber.Ctx) error {
+		_, cache, err := p.specCaches(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		format, contentType := negotiateSpecFormat(c)
+		return serveSpec(c, cache, format, contentType)
 	})
 
-	cache := newSpecCache(func() (map[string]interface{}, error) {
-		return buildStaticSpec(router, GeneratorConfig{
-			DTOsDirectory:      p.dtosDirectory,
-			PluginRegistry:     p.pluginRegistry,
-			PaginationLimit:    p.paginationLimit,
-			PaginationMaxLimit: p.paginationMaxLimit,
-			Title:              p.title,
-			Version:            p.version,
-			Description:        p.description,
+	logger.Log.Info("Api spec available (json format)", "url", fmt.Sprintf("http://localhost:%s%s", "8000", docs.JSON))
+
+	if p.opts.InternalSpecPath != "" {
+		internalGuards := append(slices.Clone(guards), tokenGuard(p.opts.InternalDocsToken))
+		getGuarded(router, p.opts.InternalSpecPath, internalGuards, func(c fiber.Ctx) error {
+			cache, _, err := p.specCaches(c)
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+			}
+			format, contentType := negotiateSpecFormat(c)
+			return serveSpec(c, cache, format, contentType)
 		})
+	}
+
+	if mode == docsModeJSON {
+		return nil
+	}
+
+	getGuarded(router, docs.YAML, guards, func(c fiber.Ctx) error {
+		_, cache, err := p.specCaches(c)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		}
+		return serveSpec(c, cache, specFormatYAML, yamlContentType)
 	})
 
-	router.Get("/openapi.json", func(c fiber.Ctx) error {
-		protocol := "http"
-		if c.Protocol() == "https" {
-			protocol = "https"
+	logger.Log.Info("Api spec available", "url", fmt.Sprintf("http://localhost:%s%s", "8000", docs.UI))
+	logger.Log.Info("Api spec available (yaml format)", "url", fmt.Sprintf("http://localhost:%s%s", "8000", docs.YAML))
+
+	return nil
+}
+
+// newSpecCaches returns the internal and public spec caches generated in
+// locale. The internal cache holds the full spec; the public one derives from
+// it without the x-internal elements.
+func (p *OpenAPIPlugin) newSpecCaches(router fiber.Router, locale string) (internal, public *specCache) {
+	internal = newSpecCache(func() (map[string]interface{}, error) {
+		cfg := p.generatorConfig()
+		cfg.Locale = locale
+		return buildStaticSpec(router, cfg)
+	})
+	public = newSpecCache(func() (map[string]interface{}, error) {
+		full, err := internal.static()
+		if err != nil {
+			return nil, err
+		}
+		return filterInternalSpec(full), nil
+	})
+	return internal, public
+}
+
+// generatorConfig maps the plugin options onto the generator's, along with
+// the servers, resources, routes and apps known at runtime.
+func (p *OpenAPIPlugin) generatorConfig() GeneratorConfig {
+	cfg := p.opts.generatorConfig()
+	cfg.Servers = p.specServers()
+	cfg.Resources = p.registeredResources()
+	cfg.RouteOperations = p.describedRoutes()
+	cfg.MountedApps = p.registeredMountedApps()
+	return cfg
+}
+
+// localeSpecCaches holds the spec caches of a locale served through ?lang=
+// next to the default one.
+type localeSpecCaches struct {
+	internal *specCache
+	public   *specCache
+}
+
+// specCaches returns the internal and public caches for the ?lang= query of
+// the request, the default locale's when it is absent.
+func (p *OpenAPIPlugin) specCaches(c fiber.Ctx) (internal, public *specCache, err error) {
+	lang := c.Query("lang")
+	if lang == "" || lang == p.opts.Locale {
+		return p.internalCache, p.cache, nil
+	}
+	caches, ok := p.localeCaches[lang]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported locale %q (supported: %s)", lang, strings.Join(p.servedLocales(), ", "))
+	}
+	return caches.internal, caches.public, nil
+}
+
+// servedLocales lists the default locale followed by the other served ones.
+func (p *OpenAPIPlugin) servedLocales() []string {
+	locales := []string{p.opts.Locale}
+	for _, locale := range p.opts.Locales {
+		if locale != p.opts.Locale {
+			locales = append(locales, locale)
 		}
-		serverURL := fmt.Sprintf("%s://%s", protocol, c.Hostname())
+	}
+	return locales
+}
+
+// specServers returns the configured servers list, or a single entry for the
+// configured server URL. Without either, the spec is served with the host of
+// each request.
+func (p *OpenAPIPlugin) specServers() []Server {
+	if len(p.opts.Servers) == 0 && p.opts.ServerURL != "" {
+		return []Server{{URL: p.opts.ServerURL, Description: "API server"}}
+	}
+	return p.opts.Servers
+}
+
+// Documentation route sets registered by SetupEndpoints.
+const (
+	docsModeOff  = "off"
+	docsModeJSON = "json"
+	docsModeFull = "full"
+)
+
+// docsMode decides which documentation routes the current environment gets:
+// none when disabled, only the JSON spec in json_only_environments, and all
+// of them otherwise. When environments is set it lists where the docs are
+// served; otherwise hide_on_production turns them off in production.
+func (p *OpenAPIPlugin) docsMode() string {
+	switch {
+	case p.opts.Disabled:
+		return docsModeOff
+	case len(p.opts.Environments) > 0 && !slices.Contains(p.opts.Environments, p.opts.Environment):
+		return docsModeOff
+	case len(p.opts.Environments) == 0 && p.opts.Environment == "production" && p.opts.HideOnProduction:
+		return docsModeOff
+	case slices.Contains(p.opts.JSONOnlyEnvironments, p.opts.Environment):
+		return docsModeJSON
+	}
+	return docsModeFull
+}
+
+// docsGuards returns the access checks run before every documentation route,
+// in order: the basic auth or static token credentials, either of which
+// grants access, then the host-supplied guard.
+func (p *OpenAPIPlugin) docsGuards() []fiber.Handler {
+	var guards []fiber.Handler
+	if p.opts.DocsBasicAuth.Username != "" || p.opts.DocsToken != "" {
+		guards = append(guards, credentialsGuard(p.opts.DocsBasicAuth, p.opts.DocsToken))
+	}
+	if p.opts.DocsGuard != nil {
+		guards = append(guards, p.opts.DocsGuard)
+	}
+	return guards
+}
+
+// Invalidate discards the cached spec so the next request regenerates it from
+// the current routes and DTO files. It is a no-op before SetupEndpoints.
+func (p *OpenAPIPlugin) Invalidate() {
+	if p.internalCache != nil {
+		p.internalCache.invalidate()
+	}
+	if p.cache != nil {
+		p.cache.invalidate()
+	}
+	for _, caches := range p.localeCaches {
+		caches.internal.invalidate()
+		caches.public.invalidate()
+	}
+}
+
+// startDTOWatcher watches the DTOs directory on disk, rebuilding the spec in
+// the background when its files change. DTOs embedded with dtos_fs cannot
+// change, so they are not watched. A single watcher serves every
+// SetupEndpoints call, rebuilding the caches of the latest one.
+func (p *OpenAPIPlugin) startDTOWatcher() error {
+	if p.dtoWatcher != nil {
+		return nil
+	}
+	if p.opts.DTOsFS != nil || p.opts.DTOsDirectory == "" {
+		logger.Log.Info("DTO watcher disabled: no DTOs directory on disk")
+		return nil
+	}
+
+	dir := resolveDTOsDirectory(p.opts.DTOsDirectory, p.opts.DTOsBaseDirectory)
+	watcher, err := watchDTODirectory(dir, p.opts.RecursiveDTOs, p.rebuildSpec)
+	if err != nil {
+		return err
+	}
+	p.dtoWatcher = watcher
+	logger.Log.Info("Watching DTOs for changes", "directory", dir)
+	return nil
+}
+
+// rebuildSpec discards the cached spec and generates it again, so the next
+// request is served the updated documentation without waiting, and rewrites
+// output_file.
+func (p *OpenAPIPlugin) rebuildSpec() {
+	p.Invalidate()
+	if _, err := p.cache.static(); err != nil {
+		logger.Log.Warn("Failed to rebuild the OpenAPI spec", "error", err)
+		return
+	}
+	logger.Log.Info("OpenAPI spec rebuilt after DTO changes")
+
+	if p.opts.OutputFile != "" {
+		if err := p.writeOutputFile(); err != nil {
+			logger.Log.Warn("Failed to write OpenAPI spec", "error", err)
+		}
+	}
+}
+
+// resourceIndex returns the entries of the index page, listed again only
+// once the cached spec has been invalidated.
+func (p *OpenAPIPlugin) resourceIndex() ([]resourceIndexEntry, error) {
+	_, generation, err := p.cache.staticGeneration()
+	if err != nil {
+		return nil, err
+	}
+
+	p.indexMu.Lock()
+	defer p.indexMu.Unlock()
+	if p.index.built && p.index.generation == generation {
+		return p.index.entries, nil
+	}
+	entries, err := loadResourceIndex(p.generatorConfig())
+	if err != nil {
+		return nil, err
+	}
+	p.index = resourceIndex{built: true, generation: generation, entries: entries}
+	return entries, nil
+}
+
+// writeOutputFile writes the public spec to the output_file.
+func (p *OpenAPIPlugin) writeOutputFile() error {
+	if err := writeSpecFile(p.cache, p.opts.OutputFile, p.opts.OutputServerURL); err != nil {
+		return fmt.Errorf("failed to write OpenAPI spec to %s: %w", p.opts.OutputFile, err)
+	}
+	logger.Log.Info("Api spec written", "file", p.opts.OutputFile)
+	return nil
+}
 
-		raw, err := cache.bytes(serverURL, encoderFrom(c))
+// Close stops watching the DTOs directory. It is a no-op unless watch_dtos
+// is enabled. gorest does not close its plugins, so hosts enabling
+// watch_dtos call it on shutdown.
+func (p *OpenAPIPlugin) Close() error {
+	if p.dtoWatcher == nil {
+		return nil
+	}
+	return p.dtoWatcher.Close()
+}
+
+// setupUIEndpoints registers the documentation page, with its resource index
+// when enabled.
+func (p *OpenAPIPlugin) setupUIEndpoints(router fiber.Router, docs docPaths, guards []fiber.Handler) error {
+	renderer := p.opts.UIRenderer
+	if renderer == nil {
+		renderer = ScalarUIRenderer{}
+	}
+
+	// Setup OpenAPI UI endpoint
+	getGuarded(router, docs.UI, guards, func(c fiber.Ctx) error {
+		html, err := renderer.RenderUI(docs.JSON)
 		if err != nil {
 			return c.Status(500).JSON(fiber.Map{
-				"error": fmt.Sprintf("Failed to generate OpenAPI spec: %v", err),
+				"error": fmt.Sprintf("Failed to render documentation UI: %v", err),
 			})
 		}
 
-		c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSONCharsetUTF8)
-		return c.Send(raw)
+		// Override CSP with the directives the UI needs (external scripts, styles...)
+		if csp := contentSecurityPolicy(renderer.CSPDirectives()); csp != "" {
+			c.Set("Content-Security-Policy", csp)
+		}
+		c.Set("Content-Type", "text/html")
+		return c.SendString(html)
 	})
 
-	logger.Log.Info("Api spec available", "url", fmt.Sprintf("http://localhost:%s/%s", "8000", "openapi"))
-	logger.Log.Info("Api spec available (json format)", "url", fmt.Sprintf("http://localhost:%s/%s", "8000", "openapi.json"))
+	if p.opts.IndexPage {
+		getGuarded(router, docs.Index, guards, func(c fiber.Ctx) error {
+			resources, err := p.resourceIndex()
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{
+					"error": fmt.Sprintf("Failed to list resources: %v", err),
+				})
+			}
+
+			html, err := renderResourceIndex(p.opts.Title, docs.UI, resources)
+			if err != nil {
+				return c.Status(500).JSON(fiber.Map{
+					"error": fmt.Sprintf("Failed to render resource index: %v", err),
+				})
+			}
+
+			c.Set("Content-Type", "text/html")
+			return c.SendString(html)
+		})
+	}
 
 	return nil
 }
+
+// serveSpec answers with the cached spec in the given format and content
+// type, for the server URL of the request and the spec version of its
+// ?version= query.
+func serveSpec(c fiber.Ctx, cache *specCache, format, contentType string) error {
+	protocol := "http"
+	if c.Protocol() == "https" {
+		protocol = "https"
+	}
+	serverURL := fmt.Sprintf("%s://%s", protocol, c.Hostname())
+
+	version, err := normalizeSpecVersion(c.Query("version"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	encode := specEncoder(encodeCanonicalJSON)
+	if format == specFormatYAML {
+		encode = encodeYAML
+	}
+
+	var raw []byte
+	contentEncoding := negotiateSpecEncoding(c)
+	if contentEncoding != "" {
+		raw, err = cache.encoded(serverURL, version, format, contentEncoding, encode)
+	} else {
+		raw, err = cache.bytes(serverURL, version, format, encode)
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": fmt.Sprintf("Failed to generate OpenAPI spec: %v", err),
+		})
+	}
+
+	// Each content coding is a distinct representation with its own ETag
+	c.Vary(fiber.HeaderAcceptEncoding)
+	etag := specETag(raw)
+	c.Set(fiber.HeaderETag, etag)
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	if contentEncoding != "" {
+		c.Set(fiber.HeaderContentEncoding, contentEncoding)
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	return c.Send(raw)
+}
@@ -0,0 +1,77 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Spec versions that can be requested from the served endpoints.
+const (
+	SpecVersion30 = "3.0"
+	SpecVersion31 = "3.1"
+)
+
+// normalizeSpecVersion maps a requested version ("", "3.0", "3.1.0", ...) to
+// one of the supported SpecVersion constants.
+func normalizeSpecVersion(version string) (string, error) {
+	switch version {
+	case "", "3", "3.0", "3.0.0":
+		return SpecVersion30, nil
+	case "3.1", "3.1.0":
+		return SpecVersion31, nil
+	}
+	return "", fmt.Errorf("unsupported OpenAPI version %q (supported: %s, %s)", version, SpecVersion30, SpecVersion31)
+}
+
+// convertToOpenAPI31 rewrites a generated 3.0 document as OpenAPI 3.1: the
+// nullable keyword is replaced by a "null" member of the schema type, and a
+// nullable enum lists null among its values.
+func convertToOpenAPI31(doc map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var converted map[string]interface{}
+	if err := json.Unmarshal(raw, &converted); err != nil {
+		return nil, err
+	}
+
+	convertSchemas31(converted)
+	converted["openapi"] = "3.1.0"
+	return converted, nil
+}
+
+func convertSchemas31(node interface{}) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if nullable, ok := n["nullable"].(bool); ok {
+			delete(n, "nullable")
+			if typ, ok := n["type"].(string); ok && nullable {
+				n["type"] = []interface{}{typ, "null"}
+			}
+			if enum, ok := n["enum"].([]interface{}); ok && nullable && !slices.Contains(enum, nil) {
+				n["enum"] = append(enum, nil)
+			}
+			// A nullable union admits null as one more member
+			for _, keyword := range []string{"oneOf", "anyOf"} {
+				if members, ok := n[keyword].([]interface{}); ok && nullable && n["type"] == nil {
+					n[keyword] = append(members, map[string]interface{}{"type": "null"})
+				}
+			}
+			// A nullable $ref is wrapped in allOf, which has no type to extend
+			if allOf, ok := n["allOf"].([]interface{}); ok && nullable && n["type"] == nil {
+				delete(n, "allOf")
+				n["anyOf"] = append(allOf, map[string]interface{}{"type": "null"})
+			}
+		}
+		for _, child := range n {
+			convertSchemas31(child)
+		}
+	case []interface{}:
+		for _, child := range n {
+			convertSchemas31(child)
+		}
+	}
+}
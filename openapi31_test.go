@@ -0,0 +1,179 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeSpecVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{version: "", want: SpecVersion30},
+		{version: "3.0", want: SpecVersion30},
+		{version: "3.0.0", want: SpecVersion30},
+		{version: "3.1", want: SpecVersion31},
+		{version: "3.1.0", want: SpecVersion31},
+		{version: "2.0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := normalizeSpecVersion(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeSpecVersion(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeSpecVersion(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertToOpenAPI31(t *testing.T) {
+	doc := map[string]interface{}{
+		"openapi": "3.0.0",
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"User": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{"type": "string", "nullable": false},
+						"bio":  map[string]interface{}{"type": "string", "nullable": true},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := convertToOpenAPI31(doc)
+	if err != nil {
+		t.Fatalf("convertToOpenAPI31() error = %v", err)
+	}
+
+	if got["openapi"] != "3.1.0" {
+		t.Errorf("openapi = %v, want 3.1.0", got["openapi"])
+	}
+
+	properties := got["components"].(map[string]interface{})["schemas"].(map[string]interface{})["User"].(map[string]interface{})["properties"].(map[string]interface{})
+	if want := map[string]interface{}{"type": "string"}; !reflect.DeepEqual(properties["name"], want) {
+		t.Errorf("name = %v, want %v", properties["name"], want)
+	}
+	if want := map[string]interface{}{"type": []interface{}{"string", "null"}}; !reflect.DeepEqual(properties["bio"], want) {
+		t.Errorf("bio = %v, want %v", properties["bio"], want)
+	}
+
+	if doc["openapi"] != "3.0.0" {
+		t.Error("convertToOpenAPI31() must not mutate the source document")
+	}
+}
+
+func TestConvertToOpenAPI31_NullableEnum(t *testing.T) {
+	type order struct {
+		Status *string `json:"status" validate:"oneof=pending shipped"`
+	}
+
+	doc := map[string]interface{}{
+		"openapi": "3.0.0",
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Order": buildSchemaFromModel(order{}),
+				"Legacy": map[string]interface{}{
+					"type":     "string",
+					"nullable": true,
+					"enum":     []string{"a", "b"},
+				},
+			},
+		},
+	}
+
+	got, err := convertToOpenAPI31(doc)
+	if err != nil {
+		t.Fatalf("convertToOpenAPI31() error = %v", err)
+	}
+
+	schemas := got["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	status := schemas["Order"].(map[string]interface{})["properties"].(map[string]interface{})["status"].(map[string]interface{})
+	want := map[string]interface{}{
+		"type": []interface{}{"string", "null"},
+		"enum": []interface{}{"pending", "shipped", nil},
+	}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("status = %v, want %v", status, want)
+	}
+
+	legacy := schemas["Legacy"].(map[string]interface{})
+	if wantEnum := []interface{}{"a", "b", nil}; !reflect.DeepEqual(legacy["enum"], wantEnum) {
+		t.Errorf("Legacy enum = %v, want %v", legacy["enum"], wantEnum)
+	}
+}
+
+func TestConvertToOpenAPI31_NullableUnion(t *testing.T) {
+	doc := map[string]interface{}{
+		"openapi": "3.0.0",
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Payment": map[string]interface{}{
+					"oneOf": []interface{}{
+						map[string]interface{}{"$ref": "#/components/schemas/Card"},
+					},
+					"nullable": true,
+				},
+			},
+		},
+	}
+
+	got, err := convertToOpenAPI31(doc)
+	if err != nil {
+		t.Fatalf("convertToOpenAPI31() error = %v", err)
+	}
+
+	payment := got["components"].(map[string]interface{})["schemas"].(map[string]interface{})["Payment"]
+	want := map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"$ref": "#/components/schemas/Card"},
+			map[string]interface{}{"type": "null"},
+		},
+	}
+	if !reflect.DeepEqual(payment, want) {
+		t.Errorf("Payment = %v, want %v", payment, want)
+	}
+}
+
+func TestConvertToOpenAPI31_NullableRef(t *testing.T) {
+	doc := map[string]interface{}{
+		"openapi": "3.0.0",
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"Post": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"editor": map[string]interface{}{
+							"allOf":    []interface{}{map[string]interface{}{"$ref": "#/components/schemas/Author"}},
+							"nullable": true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got, err := convertToOpenAPI31(doc)
+	if err != nil {
+		t.Fatalf("convertToOpenAPI31() error = %v", err)
+	}
+
+	post := got["components"].(map[string]interface{})["schemas"].(map[string]interface{})["Post"].(map[string]interface{})
+	editor := post["properties"].(map[string]interface{})["editor"]
+	want := map[string]interface{}{
+		"anyOf": []interface{}{
+			map[string]interface{}{"$ref": "#/components/schemas/Author"},
+			map[string]interface{}{"type": "null"},
+		},
+	}
+	if !reflect.DeepEqual(editor, want) {
+		t.Errorf("editor = %v, want %v", editor, want)
+	}
+}
@@ -1,16 +1,22 @@
 package openapi
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"io"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/andybalholm/brotli"
 	"github.com/gofiber/fiber/v3"
+	"gopkg.in/yaml.v3"
 )
 
 func TestNewPlugin(t *testing.T) {
@@ -52,6 +58,9 @@ func TestOpenAPIPlugin_Initialize(t *testing.T) {
 				"title":                "Custom API",
 				"version":              "2.0.0",
 				"description":          "Custom Description",
+				"terms_of_service":     "https://example.com/terms",
+				"contact":              map[string]interface{}{"name": "API Support", "email": "support@example.com"},
+				"license":              map[string]interface{}{"name": "MIT"},
 			},
 			wantErr:  false,
 			validate: validateAllConfigValues,
@@ -73,7 +82,7 @@ func TestOpenAPIPlugin_Initialize(t *testing.T) {
 			validate: validateOptionalDTOsDirectory,
 		},
 		{
-			name: "ignore invalid config types",
+			name: "reject invalid config types",
 			cfg: map[string]interface{}{
 				"dtos_directory":       "/path/to/dtos",
 				"pagination_limit":     "not_an_int",
@@ -81,8 +90,21 @@ func TestOpenAPIPlugin_Initialize(t *testing.T) {
 				"title":                123,
 				"version":              true,
 			},
+			wantErr: true,
+		},
+		{
+			name: "host injected keys are accepted",
+			cfg: map[string]interface{}{
+				"database":      nil,
+				"config":        struct{}{},
+				"server_host":   "localhost",
+				"server_port":   8000,
+				"__version":     "0.6.4",
+				"title":         "Test API",
+				"health_schema": nil,
+			},
 			wantErr:  false,
-			validate: validateInvalidConfigTypes,
+			validate: validateOptionalDTOsDirectory,
 		},
 	}
 
@@ -104,62 +126,133 @@ func TestOpenAPIPlugin_Initialize(t *testing.T) {
 }
 
 func validateAllConfigValues(t *testing.T, p *OpenAPIPlugin) {
-	if p.paginationLimit != 30 {
-		t.Errorf("paginationLimit = %v, want 30", p.paginationLimit)
+	if p.opts.PaginationLimit != 30 {
+		t.Errorf("paginationLimit = %v, want 30", p.opts.PaginationLimit)
+	}
+	if p.opts.PaginationMaxLimit != 200 {
+		t.Errorf("paginationMaxLimit = %v, want 200", p.opts.PaginationMaxLimit)
+	}
+	if p.opts.DTOsDirectory != "/path/to/dtos" {
+		t.Errorf("dtosDirectory = %v, want '/path/to/dtos'", p.opts.DTOsDirectory)
+	}
+	if p.opts.Title != "Custom API" {
+		t.Errorf("title = %v, want 'Custom API'", p.opts.Title)
 	}
-	if p.paginationMaxLimit != 200 {
-		t.Errorf("paginationMaxLimit = %v, want 200", p.paginationMaxLimit)
+	if p.opts.Version != "2.0.0" {
+		t.Errorf("version = %v, want '2.0.0'", p.opts.Version)
 	}
-	if p.dtosDirectory != "/path/to/dtos" {
-		t.Errorf("dtosDirectory = %v, want '/path/to/dtos'", p.dtosDirectory)
+	if p.opts.Description != "Custom Description" {
+		t.Errorf("description = %v, want 'Custom Description'", p.opts.Description)
 	}
-	if p.title != "Custom API" {
-		t.Errorf("title = %v, want 'Custom API'", p.title)
+	if !p.opts.HideOnProduction {
+		t.Errorf("hideOnProduction = %v, want true (default)", p.opts.HideOnProduction)
 	}
-	if p.version != "2.0.0" {
-		t.Errorf("version = %v, want '2.0.0'", p.version)
+	if p.opts.TermsOfService != "https://example.com/terms" {
+		t.Errorf("termsOfService = %v, want 'https://example.com/terms'", p.opts.TermsOfService)
 	}
-	if p.description != "Custom Description" {
-		t.Errorf("description = %v, want 'Custom Description'", p.description)
+	if want := map[string]string{"name": "API Support", "email": "support@example.com"}; !reflect.DeepEqual(p.opts.Contact, want) {
+		t.Errorf("contact = %v, want %v", p.opts.Contact, want)
 	}
-	if !p.hideOnProduction {
-		t.Errorf("hideOnProduction = %v, want true (default)", p.hideOnProduction)
+	if want := map[string]string{"name": "MIT"}; !reflect.DeepEqual(p.opts.License, want) {
+		t.Errorf("license = %v, want %v", p.opts.License, want)
 	}
 }
 
 func validateMinimalConfig(t *testing.T, p *OpenAPIPlugin) {
-	if p.dtosDirectory != "/path/to/dtos" {
-		t.Errorf("dtosDirectory = %v, want '/path/to/dtos'", p.dtosDirectory)
+	if p.opts.DTOsDirectory != "/path/to/dtos" {
+		t.Errorf("dtosDirectory = %v, want '/path/to/dtos'", p.opts.DTOsDirectory)
 	}
-	if p.title != "GoREST API" {
-		t.Errorf("title = %v, want 'GoREST API' (default)", p.title)
+	if p.opts.Title != "GoREST API" {
+		t.Errorf("title = %v, want 'GoREST API' (default)", p.opts.Title)
 	}
-	if p.version != "1.0.0" {
-		t.Errorf("version = %v, want '1.0.0' (default)", p.version)
+	if p.opts.Version != "1.0.0" {
+		t.Errorf("version = %v, want '1.0.0' (default)", p.opts.Version)
 	}
-	if p.description != "Auto-generated REST API with full CRUD operations" {
-		t.Errorf("description = %v, want default description", p.description)
+	if p.opts.Description != "Auto-generated REST API with full CRUD operations" {
+		t.Errorf("description = %v, want default description", p.opts.Description)
 	}
-	if !p.hideOnProduction {
-		t.Errorf("hideOnProduction = %v, want true (default)", p.hideOnProduction)
+	if !p.opts.HideOnProduction {
+		t.Errorf("hideOnProduction = %v, want true (default)", p.opts.HideOnProduction)
 	}
 }
 
 func validateOptionalDTOsDirectory(t *testing.T, p *OpenAPIPlugin) {
-	if p.dtosDirectory != "" {
-		t.Errorf("dtosDirectory = %v, want empty string when not provided", p.dtosDirectory)
+	if p.opts.DTOsDirectory != "" {
+		t.Errorf("dtosDirectory = %v, want empty string when not provided", p.opts.DTOsDirectory)
 	}
 }
 
-func validateInvalidConfigTypes(t *testing.T, p *OpenAPIPlugin) {
-	if p.paginationLimit != 0 {
-		t.Errorf("paginationLimit = %v, want 0 (invalid type ignored)", p.paginationLimit)
-	}
-	if p.title != "GoREST API" {
-		t.Errorf("title = %v, want 'GoREST API' (invalid type, use default)", p.title)
+func TestOpenAPIPlugin_Initialize_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     map[string]interface{}
+		wantErr []string
+	}{
+		{
+			name: "unknown keys",
+			cfg:  map[string]interface{}{"titel": "API", "pagination": 20},
+			wantErr: []string{
+				`unknown config key "pagination"`,
+				`unknown config key "titel"`,
+			},
+		},
+		{
+			name: "wrong types",
+			cfg: map[string]interface{}{
+				"pagination_limit": "not_an_int",
+				"hide_fields":      []interface{}{"password", 42},
+				"contact":          "support@example.com",
+				"ui_renderer":      "scalar",
+			},
+			wantErr: []string{
+				`config key "contact" must be a map of strings, got string`,
+				`config key "hide_fields" must be a list of strings, got []interface {}`,
+				`config key "pagination_limit" must be an integer, got string`,
+				`config key "ui_renderer" must be a UIRenderer, got string`,
+			},
+		},
+		{
+			name: "inconsistent values",
+			cfg: map[string]interface{}{
+				"pagination_limit":     100,
+				"pagination_max_limit": 20,
+				"internal_spec_path":   "/openapi/internal.json",
+			},
+			wantErr: []string{
+				"PaginationMaxLimit 20 is lower than PaginationLimit 100",
+				"InternalSpecPath /openapi/internal.json requires an InternalDocsToken",
+			},
+		},
+		{
+			name: "all errors reported together",
+			cfg: map[string]interface{}{
+				"unknown":              true,
+				"title":                123,
+				"pagination_limit":     50,
+				"pagination_max_limit": 10,
+			},
+			wantErr: []string{
+				`config key "title" must be a string, got int`,
+				`unknown config key "unknown"`,
+				"PaginationMaxLimit 10 is lower than PaginationLimit 50",
+			},
+		},
 	}
-	if !p.hideOnProduction {
-		t.Errorf("hideOnProduction = %v, want true (default)", p.hideOnProduction)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &OpenAPIPlugin{}
+			err := plugin.Initialize(tt.cfg)
+			if err == nil {
+				t.Fatalf("Initialize() error = nil, want %v", tt.wantErr)
+			}
+			if got := strings.Split(err.Error(), "\n"); !reflect.DeepEqual(got, tt.wantErr) {
+				t.Errorf("Initialize() errors = %q, want %q", got, tt.wantErr)
+			}
+			if !reflect.DeepEqual(plugin, &OpenAPIPlugin{}) {
+				t.Error("a rejected config should leave the plugin unchanged")
+			}
+		})
 	}
 }
 
@@ -245,13 +338,15 @@ func TestOpenAPIPlugin_SetupEndpoints(t *testing.T) {
 func setupOpenAPIHTMLTest(t *testing.T) (*OpenAPIPlugin, *fiber.App) {
 	tempDir := t.TempDir()
 	plugin := &OpenAPIPlugin{
-		dtosDirectory:      tempDir,
-		paginationLimit:    20,
-		paginationMaxLimit: 100,
-		title:              "Test API",
-		version:            "1.0.0",
-		description:        "Test Description",
-		hideOnProduction:   false,
+		opts: Options{
+			DTOsDirectory:      tempDir,
+			PaginationLimit:    20,
+			PaginationMaxLimit: 100,
+			Title:              "Test API",
+			Version:            "1.0.0",
+			Description:        "Test Description",
+			HideOnProduction:   false,
+		},
 	}
 	app := fiber.New()
 	return plugin, app
@@ -313,13 +408,15 @@ type UserDTO struct {
 	}
 
 	plugin := &OpenAPIPlugin{
-		dtosDirectory:      tempDir,
-		paginationLimit:    20,
-		paginationMaxLimit: 100,
-		title:              "Test API",
-		version:            "1.0.0",
-		description:        "Test Description",
-		hideOnProduction:   false,
+		opts: Options{
+			DTOsDirectory:      tempDir,
+			PaginationLimit:    20,
+			PaginationMaxLimit: 100,
+			Title:              "Test API",
+			Version:            "1.0.0",
+			Description:        "Test Description",
+			HideOnProduction:   false,
+		},
 	}
 	app := fiber.New()
 	return plugin, app
@@ -381,13 +478,15 @@ func validateOpenAPIComponents(t *testing.T, spec map[string]interface{}) {
 
 func setupOpenAPIErrorTest(t *testing.T) (*OpenAPIPlugin, *fiber.App) {
 	plugin := &OpenAPIPlugin{
-		dtosDirectory:      "/non/existent/directory",
-		paginationLimit:    20,
-		paginationMaxLimit: 100,
-		title:              "Test API",
-		version:            "1.0.0",
-		description:        "Test Description",
-		hideOnProduction:   false,
+		opts: Options{
+			DTOsDirectory:      "/non/existent/directory",
+			PaginationLimit:    20,
+			PaginationMaxLimit: 100,
+			Title:              "Test API",
+			Version:            "1.0.0",
+			Description:        "Test Description",
+			HideOnProduction:   false,
+		},
 	}
 	app := fiber.New()
 	return plugin, app
@@ -451,14 +550,16 @@ type TestDTO struct {
 			}
 
 			plugin := &OpenAPIPlugin{
-				dtosDirectory:      tempDir,
-				paginationLimit:    20,
-				paginationMaxLimit: 100,
-				title:              "Test API",
-				version:            "1.0.0",
-				description:        "Test Description",
-				hideOnProduction:   tt.hideOnProduction,
-				environment:        tt.environment,
+				opts: Options{
+					DTOsDirectory:      tempDir,
+					PaginationLimit:    20,
+					PaginationMaxLimit: 100,
+					Title:              "Test API",
+					Version:            "1.0.0",
+					Description:        "Test Description",
+					HideOnProduction:   tt.hideOnProduction,
+					Environment:        tt.environment,
+				},
 			}
 
 			app := fiber.New()
@@ -506,6 +607,117 @@ type TestDTO struct {
 	}
 }
 
+func TestOpenAPIPlugin_DocsMode(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		want   string
+	}{
+		{
+			name:   "enabled by default",
+			config: map[string]interface{}{},
+			want:   docsModeFull,
+		},
+		{
+			name:   "disabled",
+			config: map[string]interface{}{"enabled": false},
+			want:   docsModeOff,
+		},
+		{
+			name:   "hidden in production by default",
+			config: map[string]interface{}{"environment": "production"},
+			want:   docsModeOff,
+		},
+		{
+			name: "listed environment",
+			config: map[string]interface{}{
+				"environment":  "staging",
+				"environments": []interface{}{"development", "staging"},
+			},
+			want: docsModeFull,
+		},
+		{
+			name: "unlisted environment",
+			config: map[string]interface{}{
+				"environment":  "qa",
+				"environments": []interface{}{"development", "staging"},
+			},
+			want: docsModeOff,
+		},
+		{
+			name: "environments take precedence over hide_on_production",
+			config: map[string]interface{}{
+				"environment":  "production",
+				"environments": []interface{}{"production"},
+			},
+			want: docsModeFull,
+		},
+		{
+			name: "json only environment",
+			config: map[string]interface{}{
+				"environment":            "production",
+				"hide_on_production":     false,
+				"json_only_environments": []interface{}{"production"},
+			},
+			want: docsModeJSON,
+		},
+		{
+			name: "disabled wins over json only",
+			config: map[string]interface{}{
+				"enabled":                false,
+				"json_only_environments": []interface{}{"development"},
+			},
+			want: docsModeOff,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &OpenAPIPlugin{}
+			if err := plugin.Initialize(tt.config); err != nil {
+				t.Fatalf("Initialize() error = %v", err)
+			}
+			if got := plugin.docsMode(); got != tt.want {
+				t.Errorf("docsMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_JSONOnly(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{
+		"environment":            "staging",
+		"index_page":             true,
+		"json_only_environments": []interface{}{"staging"},
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	app := fiber.New()
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: "/openapi.json", wantStatus: 200},
+		{path: "/openapi", wantStatus: 404},
+		{path: "/openapi.yaml", wantStatus: 404},
+		{path: "/openapi/index", wantStatus: 404},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+		if err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		if resp.StatusCode != tt.wantStatus {
+			t.Errorf("GET %s status = %v, want %v", tt.path, resp.StatusCode, tt.wantStatus)
+		}
+	}
+}
+
 func TestOpenAPIPlugin_Initialize_HideOnProduction(t *testing.T) {
 	tests := []struct {
 		name                     string
@@ -541,8 +753,8 @@ func TestOpenAPIPlugin_Initialize_HideOnProduction(t *testing.T) {
 				t.Fatalf("Initialize() error = %v", err)
 			}
 
-			if plugin.hideOnProduction != tt.expectedHideOnProduction {
-				t.Errorf("hideOnProduction = %v, want %v", plugin.hideOnProduction, tt.expectedHideOnProduction)
+			if plugin.opts.HideOnProduction != tt.expectedHideOnProduction {
+				t.Errorf("hideOnProduction = %v, want %v", plugin.opts.HideOnProduction, tt.expectedHideOnProduction)
 			}
 		})
 	}
@@ -582,13 +794,15 @@ type TestDTO struct {
 			}
 
 			plugin := &OpenAPIPlugin{
-				dtosDirectory:      tempDir,
-				paginationLimit:    20,
-				paginationMaxLimit: 100,
-				title:              "Test API",
-				version:            "1.0.0",
-				description:        "Test Description",
-				hideOnProduction:   false,
+				opts: Options{
+					DTOsDirectory:      tempDir,
+					PaginationLimit:    20,
+					PaginationMaxLimit: 100,
+					Title:              "Test API",
+					Version:            "1.0.0",
+					Description:        "Test Description",
+					HideOnProduction:   false,
+				},
 			}
 
 			app := fiber.New()
@@ -632,3 +846,1082 @@ type TestDTO struct {
 		})
 	}
 }
+
+func TestOpenAPIPlugin_SetupEndpoints_WithoutDTOsDirectory(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/status", func(c fiber.Ctx) error { return c.SendString("up") })
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	req.Host = "localhost"
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Test request failed: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("Status code = %v, want 200", resp.StatusCode)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var spec map[string]interface{}
+	if err := json.Unmarshal(body, &spec); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	if _, ok := paths["/status"]; !ok {
+		t.Error("spec missing discovered /status route")
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_VersionSelection(t *testing.T) {
+	plugin, app := setupOpenAPIJSONTest(t)
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	tests := []struct {
+		query       string
+		wantStatus  int
+		wantVersion string
+	}{
+		{query: "", wantStatus: 200, wantVersion: "3.0.0"},
+		{query: "?version=3.0", wantStatus: 200, wantVersion: "3.0.0"},
+		{query: "?version=3.1", wantStatus: 200, wantVersion: "3.1.0"},
+		{query: "?version=2.0", wantStatus: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run("version"+tt.query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/openapi.json"+tt.query, nil)
+			req.Host = "localhost"
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Test request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Status code = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != 200 {
+				return
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			var spec map[string]interface{}
+			if err := json.Unmarshal(body, &spec); err != nil {
+				t.Fatalf("Failed to parse JSON response: %v", err)
+			}
+			if spec["openapi"] != tt.wantVersion {
+				t.Errorf("openapi = %v, want %v", spec["openapi"], tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_LocaleSelection(t *testing.T) {
+	plugin := NewPlugin().(*OpenAPIPlugin)
+	err := plugin.Initialize(map[string]interface{}{
+		"hide_on_production": false,
+		"locales":            []interface{}{"fr", "de"},
+		"translations": map[string]interface{}{
+			"de": map[string]interface{}{"route.action.POST": "Erstellen"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	app := fiber.New()
+	app.Post("/auth/login", func(c fiber.Ctx) error { return nil })
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	tests := []struct {
+		query       string
+		wantStatus  int
+		wantSummary string
+	}{
+		{query: "", wantStatus: 200, wantSummary: "Create or execute auth login"},
+		{query: "?lang=en", wantStatus: 200, wantSummary: "Create or execute auth login"},
+		{query: "?lang=fr", wantStatus: 200, wantSummary: "Créer ou exécuter auth login"},
+		{query: "?lang=de", wantStatus: 200, wantSummary: "Erstellen auth login"},
+		{query: "?lang=es", wantStatus: 400},
+	}
+
+	for _, tt := range tests {
+		t.Run("lang"+tt.query, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/openapi.json"+tt.query, nil)
+			req.Host = "localhost"
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Test request failed: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Status code = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != 200 {
+				return
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			var spec map[string]interface{}
+			if err := json.Unmarshal(body, &spec); err != nil {
+				t.Fatalf("Failed to parse JSON response: %v", err)
+			}
+			op := spec["paths"].(map[string]interface{})["/auth/login"].(map[string]interface{})["post"].(map[string]interface{})
+			if op["summary"] != tt.wantSummary {
+				t.Errorf("summary = %v, want %v", op["summary"], tt.wantSummary)
+			}
+		})
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_YAML(t *testing.T) {
+	plugin, app := setupOpenAPIJSONTest(t)
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	fetch := func(path string) (*http.Response, []byte) {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = "localhost"
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return resp, body
+	}
+
+	jsonResp, jsonBody := fetch("/openapi.json")
+	yamlResp, yamlBody := fetch("/openapi.yaml")
+	if yamlResp.StatusCode != 200 {
+		t.Fatalf("Status code = %v, want 200", yamlResp.StatusCode)
+	}
+	if ct := yamlResp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/yaml") {
+		t.Errorf("Content-Type = %v, want application/yaml", ct)
+	}
+	if ct := jsonResp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("/openapi.json Content-Type = %v after serving YAML, want application/json", ct)
+	}
+
+	var fromJSON, fromYAML interface{}
+	if err := json.Unmarshal(jsonBody, &fromJSON); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	if err := yaml.Unmarshal(yamlBody, &fromYAML); err != nil {
+		t.Fatalf("Failed to parse YAML response: %v", err)
+	}
+
+	// Compare through JSON so YAML integers and JSON float64s line up
+	normalized, _ := json.Marshal(fromYAML)
+	var yamlAsJSON interface{}
+	if err := json.Unmarshal(normalized, &yamlAsJSON); err != nil {
+		t.Fatalf("Failed to normalize YAML response: %v", err)
+	}
+	if !reflect.DeepEqual(yamlAsJSON, fromJSON) {
+		t.Error("/openapi.yaml and /openapi.json should describe the same spec")
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_AcceptNegotiation(t *testing.T) {
+	plugin, app := setupOpenAPIJSONTest(t)
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		accept          string
+		wantContentType string
+		unmarshal       func([]byte, interface{}) error
+	}{
+		{name: "no accept header", accept: "", wantContentType: "application/json", unmarshal: json.Unmarshal},
+		{name: "any", accept: "*/*", wantContentType: "application/json", unmarshal: json.Unmarshal},
+		{name: "json", accept: "application/json", wantContentType: "application/json", unmarshal: json.Unmarshal},
+		{name: "yaml", accept: "application/yaml", wantContentType: "application/yaml", unmarshal: yaml.Unmarshal},
+		{
+			name:            "openapi media type",
+			accept:          "application/vnd.oai.openapi",
+			wantContentType: "application/vnd.oai.openapi",
+			unmarshal:       yaml.Unmarshal,
+		},
+		{
+			name:            "openapi json media type",
+			accept:          "application/vnd.oai.openapi+json",
+			wantContentType: "application/vnd.oai.openapi+json",
+			unmarshal:       json.Unmarshal,
+		},
+		{
+			name:            "quality ordering",
+			accept:          "application/json;q=0.5, application/yaml",
+			wantContentType: "application/yaml",
+			unmarshal:       yaml.Unmarshal,
+		},
+		{name: "unsupported falls back to json", accept: "text/html", wantContentType: "application/json", unmarshal: json.Unmarshal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/openapi.json", nil)
+			req.Host = "localhost"
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Test request failed: %v", err)
+			}
+			if resp.StatusCode != 200 {
+				t.Fatalf("Status code = %v, want 200", resp.StatusCode)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != tt.wantContentType+"; charset=utf-8" {
+				t.Errorf("Content-Type = %v, want %v", ct, tt.wantContentType)
+			}
+			if vary := resp.Header.Get("Vary"); !strings.Contains(vary, "Accept") {
+				t.Errorf("Vary = %q, want it to list Accept", vary)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			var spec map[string]interface{}
+			if err := tt.unmarshal(body, &spec); err != nil {
+				t.Fatalf("Failed to parse response: %v", err)
+			}
+			if spec["openapi"] != "3.0.0" {
+				t.Errorf("openapi = %v, want 3.0.0", spec["openapi"])
+			}
+		})
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_ETag(t *testing.T) {
+	plugin, app := setupOpenAPIJSONTest(t)
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	fetch := func(path, ifNoneMatch string) *http.Response {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = "localhost"
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		return resp
+	}
+
+	for _, path := range []string{"/openapi.json", "/openapi.yaml"} {
+		t.Run(path, func(t *testing.T) {
+			first := fetch(path, "")
+			etag := first.Header.Get("ETag")
+			if etag == "" {
+				t.Fatal("expected an ETag header")
+			}
+			if again := fetch(path, "").Header.Get("ETag"); again != etag {
+				t.Errorf("ETag changed between requests: %v then %v", etag, again)
+			}
+
+			tests := []struct {
+				name        string
+				ifNoneMatch string
+				wantStatus  int
+			}{
+				{name: "matching", ifNoneMatch: etag, wantStatus: 304},
+				{name: "weak", ifNoneMatch: "W/" + etag, wantStatus: 304},
+				{name: "listed", ifNoneMatch: `"stale", ` + etag, wantStatus: 304},
+				{name: "stale", ifNoneMatch: `"stale"`, wantStatus: 200},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					resp := fetch(path, tt.ifNoneMatch)
+					if resp.StatusCode != tt.wantStatus {
+						t.Errorf("Status code = %v, want %v", resp.StatusCode, tt.wantStatus)
+					}
+					body, _ := io.ReadAll(resp.Body)
+					if tt.wantStatus == 304 && len(body) != 0 {
+						t.Errorf("304 response should have no body, got %d bytes", len(body))
+					}
+				})
+			}
+		})
+	}
+
+	if fetch("/openapi.json", "").Header.Get("ETag") == fetch("/openapi.yaml", "").Header.Get("ETag") {
+		t.Error("JSON and YAML representations should carry distinct ETags")
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_ConfiguredPaths(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{
+		"docs_path": "/docs",
+		"spec_path": "/docs/spec.json",
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/status", func(c fiber.Ctx) error { return c.SendString("up") })
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	fetch := func(path string) (int, []byte) {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = "localhost"
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, body
+	}
+
+	for _, path := range []string{"/openapi", "/openapi.json", "/openapi.yaml"} {
+		if status, _ := fetch(path); status != 404 {
+			t.Errorf("GET %s status = %v, want 404", path, status)
+		}
+	}
+
+	status, body := fetch("/docs")
+	if status != 200 {
+		t.Fatalf("GET /docs status = %v, want 200", status)
+	}
+	if !strings.Contains(string(body), `data-url="/docs/spec.json"`) {
+		t.Error("UI should load the spec from the configured spec path")
+	}
+
+	if status, _ := fetch("/docs/spec.yaml"); status != 200 {
+		t.Errorf("GET /docs/spec.yaml status = %v, want 200", status)
+	}
+
+	status, body = fetch("/docs/spec.json")
+	if status != 200 {
+		t.Fatalf("GET /docs/spec.json status = %v, want 200", status)
+	}
+	var spec map[string]interface{}
+	if err := json.Unmarshal(body, &spec); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+	paths := spec["paths"].(map[string]interface{})
+	if _, ok := paths["/status"]; !ok {
+		t.Error("expected /status to be documented")
+	}
+	for _, path := range []string{"/docs", "/docs/spec.json", "/docs/spec.yaml"} {
+		if _, ok := paths[path]; ok {
+			t.Errorf("documentation route %s should not be documented", path)
+		}
+	}
+}
+
+type stubUIRenderer struct{}
+
+func (stubUIRenderer) RenderUI(specURL string) (string, error) {
+	return "<html>spec at " + specURL + "</html>", nil
+}
+
+func (stubUIRenderer) CSPDirectives() []string {
+	return []string{"default-src 'self'"}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_UIRenderer(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{
+		"ui_renderer": stubUIRenderer{},
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	app := fiber.New()
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/openapi", nil))
+	if err != nil {
+		t.Fatalf("Test request failed: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "<html>spec at /openapi.json</html>" {
+		t.Errorf("body = %q, want the custom renderer's page", body)
+	}
+	if csp := resp.Header.Get("Content-Security-Policy"); csp != "default-src 'self';" {
+		t.Errorf("Content-Security-Policy = %q, want the custom renderer's directives", csp)
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_AccessControl(t *testing.T) {
+	docPaths := []string{"/openapi", "/openapi.json", "/openapi.yaml", "/openapi/index"}
+
+	tests := []struct {
+		name          string
+		config        map[string]interface{}
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:       "open by default",
+			config:     map[string]interface{}{},
+			wantStatus: 200,
+		},
+		{
+			name: "basic auth rejects anonymous requests",
+			config: map[string]interface{}{
+				"docs_basic_auth": map[string]interface{}{"username": "docs", "password": "secret"},
+			},
+			wantStatus: 401,
+		},
+		{
+			name: "basic auth accepts credentials",
+			config: map[string]interface{}{
+				"docs_basic_auth": map[string]interface{}{"username": "docs", "password": "secret"},
+			},
+			authorization: basicAuthHeader("docs", "secret"),
+			wantStatus:    200,
+		},
+		{
+			name:          "token accepts bearer",
+			config:        map[string]interface{}{"docs_token": "s3cr3t"},
+			authorization: "Bearer s3cr3t",
+			wantStatus:    200,
+		},
+		{
+			name:       "token rejects anonymous requests",
+			config:     map[string]interface{}{"docs_token": "s3cr3t"},
+			wantStatus: 401,
+		},
+		{
+			name: "basic auth or token accepts credentials",
+			config: map[string]interface{}{
+				"docs_basic_auth": map[string]interface{}{"username": "docs", "password": "secret"},
+				"docs_token":      "s3cr3t",
+			},
+			authorization: basicAuthHeader("docs", "secret"),
+			wantStatus:    200,
+		},
+		{
+			name: "basic auth or token accepts bearer",
+			config: map[string]interface{}{
+				"docs_basic_auth": map[string]interface{}{"username": "docs", "password": "secret"},
+				"docs_token":      "s3cr3t",
+			},
+			authorization: "Bearer s3cr3t",
+			wantStatus:    200,
+		},
+		{
+			name: "basic auth or token rejects anonymous requests",
+			config: map[string]interface{}{
+				"docs_basic_auth": map[string]interface{}{"username": "docs", "password": "secret"},
+				"docs_token":      "s3cr3t",
+			},
+			wantStatus: 401,
+		},
+		{
+			name: "custom guard",
+			config: map[string]interface{}{
+				"docs_guard": fiber.Handler(func(c fiber.Ctx) error {
+					return c.SendStatus(fiber.StatusForbidden)
+				}),
+			},
+			wantStatus: 403,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["index_page"] = true
+			plugin := &OpenAPIPlugin{}
+			if err := plugin.Initialize(tt.config); err != nil {
+				t.Fatalf("Initialize() error = %v", err)
+			}
+			app := fiber.New()
+			app.Get("/status", func(c fiber.Ctx) error { return c.SendString("up") })
+			if err := plugin.SetupEndpoints(app); err != nil {
+				t.Fatalf("SetupEndpoints() error = %v", err)
+			}
+
+			for _, path := range docPaths {
+				req := httptest.NewRequest("GET", path, nil)
+				if tt.authorization != "" {
+					req.Header.Set("Authorization", tt.authorization)
+				}
+				resp, err := app.Test(req)
+				if err != nil {
+					t.Fatalf("Test request failed: %v", err)
+				}
+				if resp.StatusCode != tt.wantStatus {
+					t.Errorf("GET %s status = %v, want %v", path, resp.StatusCode, tt.wantStatus)
+				}
+			}
+
+			// Application routes are left alone
+			resp, err := app.Test(httptest.NewRequest("GET", "/status", nil))
+			if err != nil {
+				t.Fatalf("Test request failed: %v", err)
+			}
+			if resp.StatusCode != 200 {
+				t.Errorf("GET /status status = %v, want 200", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_InternalSpec(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{
+		"internal_operations": []interface{}{"* /admin/*"},
+		"internal_spec_path":  "/openapi/internal.json",
+		"internal_docs_token": "s3cr3t",
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/status", func(c fiber.Ctx) error { return c.SendString("up") })
+	app.Get("/admin/stats", func(c fiber.Ctx) error { return c.SendString("{}") })
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	fetchPaths := func(path, authorization string) (int, map[string]interface{}) {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = "localhost"
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		if resp.StatusCode != 200 {
+			return resp.StatusCode, nil
+		}
+		var spec map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+			t.Fatalf("Failed to parse JSON response: %v", err)
+		}
+		return resp.StatusCode, spec["paths"].(map[string]interface{})
+	}
+
+	_, public := fetchPaths("/openapi.json", "")
+	if _, ok := public["/admin/stats"]; ok {
+		t.Error("internal operations should be left out of the public spec")
+	}
+	if _, ok := public["/status"]; !ok {
+		t.Error("public operations should stay in the public spec")
+	}
+
+	if status, _ := fetchPaths("/openapi/internal.json", ""); status != 401 {
+		t.Errorf("internal spec without token status = %v, want 401", status)
+	}
+
+	_, internal := fetchPaths("/openapi/internal.json", "Bearer s3cr3t")
+	admin, ok := internal["/admin/stats"].(map[string]interface{})
+	if !ok {
+		t.Fatal("internal operations should be in the internal spec")
+	}
+	if admin["get"].(map[string]interface{})["x-internal"] != true {
+		t.Error("internal operations should carry x-internal")
+	}
+	if _, ok := internal["/openapi/internal.json"]; ok {
+		t.Error("the internal spec route should not be documented")
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_InternalSpecRequiresToken(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{
+		"internal_spec_path": "/openapi/internal.json",
+	}); err == nil {
+		t.Error("Initialize() should refuse an unprotected internal spec")
+	}
+
+	plugin = &OpenAPIPlugin{opts: Options{InternalSpecPath: "/openapi/internal.json"}}
+	if err := plugin.SetupEndpoints(fiber.New()); err == nil {
+		t.Error("SetupEndpoints() should refuse an unprotected internal spec")
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_Servers(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{
+		"servers": []interface{}{
+			map[string]interface{}{
+				"url":         "https://{environment}.api.example.com",
+				"description": "Per-environment API",
+				"variables": map[string]interface{}{
+					"environment": map[string]interface{}{
+						"default": "production",
+						"enum":    []interface{}{"production", "staging"},
+					},
+				},
+			},
+			"not a server",
+		},
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	wantServers := []Server{{
+		URL:         "https://{environment}.api.example.com",
+		Description: "Per-environment API",
+		Variables: map[string]ServerVariable{
+			"environment": {Default: "production", Enum: []string{"production", "staging"}},
+		},
+	}}
+	if !reflect.DeepEqual(plugin.opts.Servers, wantServers) {
+		t.Fatalf("servers = %+v, want %+v", plugin.opts.Servers, wantServers)
+	}
+
+	app := fiber.New()
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/openapi.json", nil)
+	req.Host = "docs.internal"
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Test request failed: %v", err)
+	}
+	var spec map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+		t.Fatalf("Failed to parse JSON response: %v", err)
+	}
+
+	servers := spec["servers"].([]interface{})
+	if len(servers) != 1 || servers[0].(map[string]interface{})["url"] != "https://{environment}.api.example.com" {
+		t.Errorf("servers = %v, want the configured list instead of the request host", servers)
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_ConfiguredInfoAndServerURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     map[string]interface{}
+		wantURL string
+	}{
+		{
+			name:    "request host by default",
+			cfg:     map[string]interface{}{},
+			wantURL: "http://docs.internal",
+		},
+		{
+			name:    "server_url",
+			cfg:     map[string]interface{}{"server_url": "https://api.example.com"},
+			wantURL: "https://api.example.com",
+		},
+		{
+			name: "servers win over server_url",
+			cfg: map[string]interface{}{
+				"server_url": "https://api.example.com",
+				"servers":    []interface{}{map[string]interface{}{"url": "https://eu.api.example.com"}},
+			},
+			wantURL: "https://eu.api.example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := map[string]interface{}{
+				"title":       "Orders API",
+				"version":     "3.2.1",
+				"description": "Order management",
+			}
+			maps.Copy(cfg, tt.cfg)
+
+			plugin := &OpenAPIPlugin{}
+			if err := plugin.Initialize(cfg); err != nil {
+				t.Fatalf("Initialize() error = %v", err)
+			}
+			app := fiber.New()
+			if err := plugin.SetupEndpoints(app); err != nil {
+				t.Fatalf("SetupEndpoints() error = %v", err)
+			}
+
+			req := httptest.NewRequest("GET", "/openapi.json", nil)
+			req.Host = "docs.internal"
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Test request failed: %v", err)
+			}
+			var spec map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+				t.Fatalf("Failed to parse JSON response: %v", err)
+			}
+
+			info := spec["info"].(map[string]interface{})
+			if info["title"] != "Orders API" || info["version"] != "3.2.1" || info["description"] != "Order management" {
+				t.Errorf("info = %v, want the configured title, version and description", info)
+			}
+			servers := spec["servers"].([]interface{})
+			if len(servers) != 1 || servers[0].(map[string]interface{})["url"] != tt.wantURL {
+				t.Errorf("servers = %v, want a single %s entry", servers, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestOpenAPIPlugin_Initialize_OperationTexts(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{
+		"operation_texts": map[string]interface{}{
+			"list": map[string]interface{}{"summary": "List {plural}", "description": "Retrieve a page of {plural}"},
+			"get":  map[string]interface{}{"summary": "Fetch a {name}"},
+		},
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	want := map[string]OperationText{
+		OperationTextList: {Summary: "List {plural}", Description: "Retrieve a page of {plural}"},
+		OperationTextGet:  {Summary: "Fetch a {name}"},
+	}
+	if !reflect.DeepEqual(plugin.opts.OperationTexts, want) {
+		t.Errorf("operationTexts = %+v, want %+v", plugin.opts.OperationTexts, want)
+	}
+
+	err := (&OpenAPIPlugin{}).Initialize(map[string]interface{}{
+		"operation_texts": map[string]interface{}{"fetch": map[string]interface{}{"summary": "x"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), `OperationTexts key "fetch" is not supported`) {
+		t.Errorf("Initialize() error = %v, want an unsupported key error", err)
+	}
+}
+
+func TestOpenAPIPlugin_Initialize_SecuritySchemes(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{
+		"security_schemes": map[string]interface{}{
+			"partnerKey": map[string]interface{}{"type": "apiKey", "in": "cookie", "name": "session"},
+			"oauth": map[string]interface{}{
+				"type": "oauth2",
+				"flows": map[string]interface{}{
+					"authorization_code": map[string]interface{}{
+						"authorization_url": "https://auth.example.com/authorize",
+						"token_url":         "https://auth.example.com/token",
+						"scopes":            map[string]interface{}{"orders:read": "Read orders"},
+					},
+					"password": map[string]interface{}{"token_url": "https://auth.example.com/token"},
+				},
+			},
+			"oidc": map[string]interface{}{"type": "openIdConnect", "openid_connect_url": "https://auth.example.com/.well-known/openid-configuration"},
+		},
+		"security_scopes":  map[string]interface{}{"oauth": []interface{}{"orders:read"}},
+		"operation_scopes": map[string]interface{}{"GET /orders": []interface{}{"openid", "orders:read"}},
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	want := map[string]SecurityScheme{
+		"partnerKey": {Type: "apiKey", In: "cookie", Name: "session"},
+		"oauth": {Type: "oauth2", Flows: &OAuthFlows{
+			AuthorizationCode: &OAuthFlow{
+				AuthorizationURL: "https://auth.example.com/authorize",
+				TokenURL:         "https://auth.example.com/token",
+				Scopes:           map[string]string{"orders:read": "Read orders"},
+			},
+			Password: &OAuthFlow{TokenURL: "https://auth.example.com/token", Scopes: map[string]string{}},
+		}},
+		"oidc": {Type: "openIdConnect", OpenIDConnectURL: "https://auth.example.com/.well-known/openid-configuration"},
+	}
+	if !reflect.DeepEqual(plugin.opts.SecuritySchemes, want) {
+		t.Errorf("securitySchemes = %+v, want %+v", plugin.opts.SecuritySchemes, want)
+	}
+	if want := map[string][]string{"oauth": {"orders:read"}}; !reflect.DeepEqual(plugin.opts.SecurityScopes, want) {
+		t.Errorf("securityScopes = %v, want %v", plugin.opts.SecurityScopes, want)
+	}
+	if want := map[string][]string{"GET /orders": {"openid", "orders:read"}}; !reflect.DeepEqual(plugin.opts.OperationScopes, want) {
+		t.Errorf("operationScopes = %v, want %v", plugin.opts.OperationScopes, want)
+	}
+}
+
+func TestOpenAPIPlugin_Initialize_Extensions(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{
+		"extensions": map[string]interface{}{
+			"root":       map[string]interface{}{"x-api-id": "orders"},
+			"info":       map[string]interface{}{"x-audience": "partner"},
+			"paths":      map[string]interface{}{"/users": map[string]interface{}{"x-owner": "identity"}, "/bad": "x"},
+			"operations": map[string]interface{}{"GET /users": map[string]interface{}{"x-rate-limit": 100}},
+		},
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	want := Extensions{
+		Root:       map[string]interface{}{"x-api-id": "orders"},
+		Info:       map[string]interface{}{"x-audience": "partner"},
+		Paths:      map[string]map[string]interface{}{"/users": {"x-owner": "identity"}},
+		Operations: map[string]map[string]interface{}{"GET /users": {"x-rate-limit": 100}},
+	}
+	if !reflect.DeepEqual(plugin.opts.Extensions, want) {
+		t.Errorf("extensions = %+v, want %+v", plugin.opts.Extensions, want)
+	}
+}
+
+func TestOpenAPIPlugin_Invalidate(t *testing.T) {
+	plugin, app := setupOpenAPIJSONTest(t)
+	// Safe before the cache exists
+	plugin.Invalidate()
+
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	hasProducts := func() bool {
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+		req.Host = "localhost"
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		var spec map[string]interface{}
+		if err := json.NewDecoder(resp.Body).Decode(&spec); err != nil {
+			t.Fatalf("Failed to parse JSON response: %v", err)
+		}
+		_, ok := spec["paths"].(map[string]interface{})["/products"]
+		return ok
+	}
+
+	if hasProducts() {
+		t.Fatal("/products should not be documented before its DTO exists")
+	}
+
+	dtoContent := `package dto
+
+type ProductDTO struct {
+	ID int64 ` + "`json:\"id\"`" + `
+}`
+	if err := os.WriteFile(filepath.Join(plugin.opts.DTOsDirectory, "product.go"), []byte(dtoContent), 0644); err != nil {
+		t.Fatalf("Failed to create test DTO: %v", err)
+	}
+
+	if hasProducts() {
+		t.Error("the spec should stay cached until Invalidate is called")
+	}
+
+	plugin.Invalidate()
+	if !hasProducts() {
+		t.Error("the spec should be rebuilt after Invalidate")
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_Compression(t *testing.T) {
+	plugin, app := setupOpenAPIJSONTest(t)
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+
+	fetch := func(acceptEncoding string) *http.Response {
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+		req.Host = "localhost"
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		return resp
+	}
+
+	plain, _ := io.ReadAll(fetch("").Body)
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		wantEncoding   string
+		decode         func(io.Reader) (io.Reader, error)
+	}{
+		{name: "no preference", acceptEncoding: "", wantEncoding: ""},
+		{name: "identity only", acceptEncoding: "identity", wantEncoding: ""},
+		{
+			name:           "gzip",
+			acceptEncoding: "gzip",
+			wantEncoding:   "gzip",
+			decode:         func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		},
+		{
+			name:           "brotli",
+			acceptEncoding: "br",
+			wantEncoding:   "br",
+			decode:         func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+		},
+		{
+			name:           "brotli refused",
+			acceptEncoding: "gzip, br;q=0",
+			wantEncoding:   "gzip",
+			decode:         func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := fetch(tt.acceptEncoding)
+			if got := resp.Header.Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if vary := resp.Header.Get("Vary"); !strings.Contains(vary, "Accept-Encoding") {
+				t.Errorf("Vary = %q, want it to list Accept-Encoding", vary)
+			}
+
+			var body io.Reader = resp.Body
+			if tt.decode != nil {
+				decoded, err := tt.decode(resp.Body)
+				if err != nil {
+					t.Fatalf("Failed to decode body: %v", err)
+				}
+				body = decoded
+			}
+			got, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("Failed to read body: %v", err)
+			}
+			if !bytes.Equal(got, plain) {
+				t.Error("decoded body should match the uncompressed spec")
+			}
+		})
+	}
+
+	gzipETag := fetch("gzip").Header.Get("ETag")
+	if gzipETag == fetch("").Header.Get("ETag") {
+		t.Error("compressed and plain representations should carry distinct ETags")
+	}
+	if resp := fetch("gzip"); resp.Header.Get("ETag") != gzipETag {
+		t.Error("the gzip variant should be served from the cache with a stable ETag")
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_DeterministicOutput(t *testing.T) {
+	fetch := func(app *fiber.App) []byte {
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+		req.Host = "localhost"
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return body
+	}
+
+	plugin, app := setupOpenAPIJSONTest(t)
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+	want := fetch(app)
+
+	for i := 0; i < 5; i++ {
+		plugin.Invalidate()
+		if got := fetch(app); !bytes.Equal(got, want) {
+			t.Fatal("regenerated spec should be byte-identical")
+		}
+	}
+
+	// The app's JSON encoder does not affect the spec bytes
+	other := &OpenAPIPlugin{
+		opts: Options{
+			DTOsDirectory:      plugin.opts.DTOsDirectory,
+			PaginationLimit:    plugin.opts.PaginationLimit,
+			PaginationMaxLimit: plugin.opts.PaginationMaxLimit,
+			Title:              plugin.opts.Title,
+			Version:            plugin.opts.Version,
+			Description:        plugin.opts.Description,
+		},
+	}
+	customApp := fiber.New(fiber.Config{
+		JSONEncoder: func(v any) ([]byte, error) { return []byte("{}"), nil },
+	})
+	if err := other.SetupEndpoints(customApp); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+	if got := fetch(customApp); !bytes.Equal(got, want) {
+		t.Error("spec bytes should not depend on the app's JSON encoder")
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_OutputFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		fileName  string
+		unmarshal func([]byte, interface{}) error
+		disabled  bool
+	}{
+		{name: "json", fileName: "openapi.json", unmarshal: json.Unmarshal},
+		{name: "yaml", fileName: "openapi.yaml", unmarshal: yaml.Unmarshal},
+		{name: "yml", fileName: "spec.yml", unmarshal: yaml.Unmarshal},
+		{name: "docs disabled", fileName: "openapi.json", unmarshal: json.Unmarshal, disabled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outputFile := filepath.Join(t.TempDir(), tt.fileName)
+
+			plugin := &OpenAPIPlugin{}
+			if err := plugin.Initialize(map[string]interface{}{
+				"output_file":       outputFile,
+				"output_server_url": "https://api.example.com",
+				"enabled":           !tt.disabled,
+			}); err != nil {
+				t.Fatalf("Initialize() error = %v", err)
+			}
+
+			app := fiber.New()
+			app.Get("/status", func(c fiber.Ctx) error { return c.SendString("up") })
+			if err := plugin.SetupEndpoints(app); err != nil {
+				t.Fatalf("SetupEndpoints() error = %v", err)
+			}
+
+			raw, err := os.ReadFile(outputFile)
+			if err != nil {
+				t.Fatalf("spec file not written: %v", err)
+			}
+
+			var spec map[string]interface{}
+			if err := tt.unmarshal(raw, &spec); err != nil {
+				t.Fatalf("Failed to parse spec file: %v", err)
+			}
+			if spec["openapi"] != "3.0.0" {
+				t.Errorf("openapi = %v, want 3.0.0", spec["openapi"])
+			}
+			servers := spec["servers"].([]interface{})
+			if url := servers[0].(map[string]interface{})["url"]; url != "https://api.example.com" {
+				t.Errorf("servers[0].url = %v, want https://api.example.com", url)
+			}
+			if _, ok := spec["paths"].(map[string]interface{})["/status"]; !ok {
+				t.Error("spec file missing discovered /status route")
+			}
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/openapi.json", nil))
+			if err != nil {
+				t.Fatalf("Test request failed: %v", err)
+			}
+			if served := resp.StatusCode == 200; served == tt.disabled {
+				t.Errorf("GET /openapi.json status = %d, want the spec served only with the docs enabled", resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestOpenAPIPlugin_SetupEndpoints_OutputFileError(t *testing.T) {
+	plugin := &OpenAPIPlugin{}
+	if err := plugin.Initialize(map[string]interface{}{
+		"output_file": filepath.Join(t.TempDir(), "missing", "openapi.json"),
+	}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	if err := plugin.SetupEndpoints(fiber.New()); err == nil {
+		t.Error("SetupEndpoints() expected an error for an unwritable output file")
+	}
+}
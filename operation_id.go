@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// Naming strategies supported by GeneratorConfig.OperationIDStrategy.
+const (
+	// OperationIDCamelCase joins a verb and the resource: listUsers, getUser.
+	OperationIDCamelCase = "camelCase"
+	// OperationIDSnakeCase is the same in snake_case: list_users, get_user.
+	OperationIDSnakeCase = "snake_case"
+	// OperationIDRouteName uses the Fiber route name (app.Get(...).Name("x"))
+	// and falls back to camelCase for unnamed routes.
+	OperationIDRouteName = "route_name"
+)
+
+// applyOperationIDs gives every operation without one an operationId built
+// with strategy. Resource operations are named after their CRUD action
+// (list, get, create, update, patch, delete, count, check) and other routes
+// after their HTTP method. Duplicates get a numeric suffix (getUser2), in
+// path then method order so the result is stable.
+func applyOperationIDs(paths map[string]interface{}, cfg GeneratorConfig, resourcePaths map[string]bool, routeNames map[string]string) {
+	seen := make(map[string]bool)
+	forEachOperation(paths, func(_, _ string, op map[string]interface{}) {
+		if id, ok := op["operationId"].(string); ok {
+			seen[id] = true
+		}
+	})
+
+	forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+		if _, ok := op["operationId"]; ok {
+			return
+		}
+
+		id := ""
+		if cfg.OperationIDStrategy == OperationIDRouteName {
+			id = routeNames[strings.ToUpper(method)+" "+fiberPath(path)]
+		}
+		if id == "" {
+			words := operationWords(stripBasePath(cfg.BasePath, path), method, resourcePaths[fiberPath(path)], cfg.PluralOverrides)
+			id = joinOperationWords(words, cfg.OperationIDStrategy == OperationIDSnakeCase)
+		}
+
+		unique := id
+		for n := 2; seen[unique]; n++ {
+			unique = fmt.Sprintf("%s%d", id, n)
+		}
+		seen[unique] = true
+		op["operationId"] = unique
+	})
+}
+
+// operationWords splits an operation into its verb followed by the words of
+// its static path segments, singularizing a segment addressed by the
+// parameter after it (/users/{id}/posts -> user, posts).
+func operationWords(path, method string, resource bool, pluralOverrides map[string]string) []string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var words []string
+	item := false
+	for i, segment := range segments {
+		if segment == "" || isPathParameter(segment) {
+			continue
+		}
+		parts := strings.FieldsFunc(strings.ToLower(segment), func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		})
+		if i+1 < len(segments) && isPathParameter(segments[i+1]) && len(parts) > 0 {
+			parts[len(parts)-1] = singularize(parts[len(parts)-1], pluralOverrides)
+		}
+		words = append(words, parts...)
+	}
+	if len(segments) > 0 {
+		item = isPathParameter(segments[len(segments)-1])
+	}
+
+	verb := strings.ToLower(method)
+	if resource {
+		switch {
+		case method == "get" && item:
+			verb = "get"
+		case method == "get":
+			verb = "list"
+		case method == "post":
+			verb = "create"
+		case method == "put":
+			verb = "update"
+		case method == "head" && item:
+			verb = "check"
+		case method == "head":
+			verb = "count"
+		}
+	}
+	return append([]string{verb}, words...)
+}
+
+func joinOperationWords(words []string, snakeCase bool) string {
+	if snakeCase {
+		return strings.Join(words, "_")
+	}
+
+	var b strings.Builder
+	for i, word := range words {
+		if i > 0 && word != "" {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+func isPathParameter(segment string) bool {
+	return strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "{") || segment == "*"
+}
+
+// fiberPath turns OpenAPI path parameters ({id}) into Fiber ones (:id).
+func fiberPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + segment[1:len(segment)-1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// openAPIPath turns Fiber path parameters (:id) into OpenAPI templates
+// ({id}), the form of the documented path keys.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// routeNames maps "METHOD /path" to the name of the routes given one, for
+// each path discovery documents the route with: constraints stripped, with
+// and without optional parameters, wildcards as parameters.
+func routeNames(routes []fiber.Route) map[string]string {
+	names := make(map[string]string)
+	for _, route := range routes {
+		if route.Name == "" {
+			continue
+		}
+		for _, variant := range routePathVariants(route.Path) {
+			key := strings.ToUpper(route.Method) + " " + stripParamConstraints(variant)
+			// as in discovery, a route registered with the variant's path wins
+			if _, ok := names[key]; ok && variant != route.Path {
+				continue
+			}
+			names[key] = route.Name
+		}
+	}
+	return names
+}
@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestOperationWords(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		method   string
+		resource bool
+		want     []string
+	}{
+		{name: "list", path: "/users", method: "get", resource: true, want: []string{"list", "users"}},
+		{name: "get item", path: "/users/{id}", method: "get", resource: true, want: []string{"get", "user"}},
+		{name: "create", path: "/users", method: "post", resource: true, want: []string{"create", "users"}},
+		{name: "update", path: "/users/{id}", method: "put", resource: true, want: []string{"update", "user"}},
+		{name: "patch", path: "/users/{id}", method: "patch", resource: true, want: []string{"patch", "user"}},
+		{name: "delete", path: "/users/{id}", method: "delete", resource: true, want: []string{"delete", "user"}},
+		{name: "count", path: "/users", method: "head", resource: true, want: []string{"count", "users"}},
+		{name: "check", path: "/users/{id}", method: "head", resource: true, want: []string{"check", "user"}},
+		{name: "batch", path: "/order-items/batch", method: "post", resource: true, want: []string{"create", "order", "items", "batch"}},
+		{name: "nested route", path: "/users/:id/posts", method: "get", want: []string{"get", "user", "posts"}},
+		{name: "route", path: "/auth/login", method: "post", want: []string{"post", "auth", "login"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := operationWords(tt.path, tt.method, tt.resource, nil); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("operationWords() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyOperationIDs(t *testing.T) {
+	newPaths := func() map[string]interface{} {
+		return map[string]interface{}{
+			"/api/users": map[string]interface{}{
+				"get":  map[string]interface{}{},
+				"post": map[string]interface{}{},
+			},
+			"/api/users/{id}": map[string]interface{}{
+				"get":    map[string]interface{}{},
+				"delete": map[string]interface{}{"operationId": "removeUser"},
+			},
+			"/api/users/{userId}": map[string]interface{}{
+				"get": map[string]interface{}{},
+			},
+			"/health": map[string]interface{}{
+				"get": map[string]interface{}{},
+			},
+		}
+	}
+	resourcePaths := map[string]bool{"/api/users": true, "/api/users/:id": true}
+	names := map[string]string{"GET /health": "healthCheck"}
+
+	tests := []struct {
+		name     string
+		strategy string
+		want     map[string]string
+	}{
+		{
+			name: "camelCase by default",
+			want: map[string]string{
+				"GET /api/users":          "listUsers",
+				"POST /api/users":         "createUsers",
+				"GET /api/users/{id}":     "getUser",
+				"DELETE /api/users/{id}":  "removeUser",
+				"GET /api/users/{userId}": "getUser2",
+				"GET /health":             "getHealth",
+			},
+		},
+		{
+			name:     "snake_case",
+			strategy: OperationIDSnakeCase,
+			want: map[string]string{
+				"GET /api/users":          "list_users",
+				"POST /api/users":         "create_users",
+				"GET /api/users/{id}":     "get_user",
+				"DELETE /api/users/{id}":  "removeUser",
+				"GET /api/users/{userId}": "get_user2",
+				"GET /health":             "get_health",
+			},
+		},
+		{
+			name:     "route names",
+			strategy: OperationIDRouteName,
+			want: map[string]string{
+				"GET /api/users":          "listUsers",
+				"POST /api/users":         "createUsers",
+				"GET /api/users/{id}":     "getUser",
+				"DELETE /api/users/{id}":  "removeUser",
+				"GET /api/users/{userId}": "getUser2",
+				"GET /health":             "healthCheck",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := newPaths()
+			applyOperationIDs(paths, GeneratorConfig{OperationIDStrategy: tt.strategy, BasePath: "/api"}, resourcePaths, names)
+
+			got := map[string]string{}
+			forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+				got[strings.ToUpper(method)+" "+path], _ = op["operationId"].(string)
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("operationIds = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/health", want: "/health"},
+		{path: "/users/:id", want: "/users/{id}"},
+		{path: "/users/:userId/posts/:postId", want: "/users/{userId}/posts/{postId}"},
+		{path: "/files/:path", want: "/files/{path}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := openAPIPath(tt.path)
+			if got != tt.want {
+				t.Errorf("openAPIPath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+			if back := fiberPath(got); back != tt.path {
+				t.Errorf("fiberPath(%q) = %q, want %q", got, back, tt.path)
+			}
+		})
+	}
+}
+
+func TestRouteNames(t *testing.T) {
+	app := fiber.New()
+	app.Get("/health", func(c fiber.Ctx) error { return nil }).Name("healthCheck")
+	app.Post("/auth/login", func(c fiber.Ctx) error { return nil })
+	app.Get("/orders/:id<int>", func(c fiber.Ctx) error { return nil }).Name("showOrder")
+	app.Get("/archive/:year?", func(c fiber.Ctx) error { return nil }).Name("archive")
+	app.Get("/archive", func(c fiber.Ctx) error { return nil }).Name("archiveIndex")
+	app.Get("/files/*", func(c fiber.Ctx) error { return nil }).Name("files")
+
+	want := map[string]string{
+		"GET /health":        "healthCheck",
+		"GET /orders/:id":    "showOrder",
+		"GET /archive/:year": "archive",
+		"GET /archive":       "archiveIndex",
+		"GET /files/:path":   "files",
+	}
+	if got := routeNames(app.GetRoutes(true)); !reflect.DeepEqual(got, want) {
+		t.Errorf("routeNames() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package openapi
+
+import "strings"
+
+// Operations whose summary and description GeneratorConfig.OperationTexts
+// can override. Resource templates accept {name} and {plural} (e.g. "List
+// {plural}", "Fetch a {name}"); OperationTextRoute, used for discovered
+// routes, accepts {action} (Get, Update...), {words} (the path as words),
+// {method} and {path}.
+const (
+	OperationTextList        = "list"
+	OperationTextCreate      = "create"
+	OperationTextGet         = "get"
+	OperationTextUpdate      = "update"
+	OperationTextDelete      = "delete"
+	OperationTextBatchCreate = "batch_create"
+	OperationTextCount       = "count"
+	OperationTextCheck       = "check"
+	OperationTextRoute       = "route"
+)
+
+// OperationText holds the summary and description templates of an
+// operation. An empty template keeps the message of the configured locale.
+type OperationText struct {
+	Summary     string
+	Description string
+}
+
+// operationTexts renders the summary and description templates of
+// operations with a fixed set of placeholder values.
+type operationTexts struct {
+	cfg      GeneratorConfig
+	replacer *strings.Replacer
+}
+
+// resourceTexts renders the texts of a resource's operations.
+func resourceTexts(cfg GeneratorConfig, name, pluralName string) operationTexts {
+	return operationTexts{
+		cfg:      cfg,
+		replacer: strings.NewReplacer("{name}", name, "{plural}", pluralName),
+	}
+}
+
+// routeTexts renders the texts of a discovered route.
+func routeTexts(cfg GeneratorConfig, path, method string) operationTexts {
+	return operationTexts{
+		cfg:      cfg,
+		replacer: strings.NewReplacer("{action}", routeAction(method, cfg), "{words}", routeWords(path), "{method}", method, "{path}", path),
+	}
+}
+
+func (t operationTexts) summary(key string) string {
+	template := t.cfg.OperationTexts[key].Summary
+	if template == "" {
+		template = message(t.cfg, key+".summary")
+	}
+	return t.replacer.Replace(template)
+}
+
+func (t operationTexts) description(key string) string {
+	template := t.cfg.OperationTexts[key].Description
+	if template == "" {
+		template = message(t.cfg, key+".description")
+	}
+	return t.replacer.Replace(template)
+}
@@ -0,0 +1,66 @@
+package openapi
+
+import "testing"
+
+func TestResourceTexts(t *testing.T) {
+	tests := []struct {
+		name            string
+		overrides       map[string]OperationText
+		key             string
+		wantSummary     string
+		wantDescription string
+	}{
+		{
+			name:            "defaults",
+			key:             OperationTextList,
+			wantSummary:     "List users",
+			wantDescription: "Retrieve a list of users",
+		},
+		{
+			name:            "summary override keeps default description",
+			overrides:       map[string]OperationText{OperationTextGet: {Summary: "Fetch a {name}"}},
+			key:             OperationTextGet,
+			wantSummary:     "Fetch a user",
+			wantDescription: "Retrieve a single user by ID",
+		},
+		{
+			name:            "both overridden",
+			overrides:       map[string]OperationText{OperationTextBatchCreate: {Summary: "Bulk create {plural}", Description: "Up to 100 {plural} at once"}},
+			key:             OperationTextBatchCreate,
+			wantSummary:     "Bulk create users",
+			wantDescription: "Up to 100 users at once",
+		},
+		{
+			name:            "other operations untouched",
+			overrides:       map[string]OperationText{OperationTextGet: {Summary: "Fetch a {name}"}},
+			key:             OperationTextDelete,
+			wantSummary:     "Delete user by ID",
+			wantDescription: "Delete an existing user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			texts := resourceTexts(GeneratorConfig{OperationTexts: tt.overrides}, "user", "users")
+			if got := texts.summary(tt.key); got != tt.wantSummary {
+				t.Errorf("summary() = %q, want %q", got, tt.wantSummary)
+			}
+			if got := texts.description(tt.key); got != tt.wantDescription {
+				t.Errorf("description() = %q, want %q", got, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestRouteTexts(t *testing.T) {
+	cfg := GeneratorConfig{OperationTexts: map[string]OperationText{
+		OperationTextRoute: {Summary: "{method} {words}", Description: "Handles {action} on {path}"},
+	}}
+
+	if got := generateSummary("/auth/login", "POST", cfg); got != "POST auth login" {
+		t.Errorf("generateSummary() = %q, want %q", got, "POST auth login")
+	}
+	if got := generateDescription("/auth/login", "POST", cfg); got != "Handles Create or execute on /auth/login" {
+		t.Errorf("generateDescription() = %q, want %q", got, "Handles Create or execute on /auth/login")
+	}
+}
@@ -0,0 +1,627 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/nicolasbonnici/gorest/plugin"
+)
+
+// Options is the typed configuration of the plugin, for Go callers building
+// it with NewPluginWithOptions instead of the Initialize config map. Each
+// field matches the config key of the same name (DTOsDirectory is
+// dtos_directory); those shared with GeneratorConfig are detailed there.
+// Start from DefaultOptions: the zero value leaves the title, version and
+// description empty and serves the docs in production.
+type Options struct {
+	// PaginationLimit and PaginationMaxLimit are the default and maximum of
+	// the documented limit query parameter.
+	PaginationLimit    int
+	PaginationMaxLimit int
+	// DTOsDirectory holds the DTO files documented as resources; empty
+	// documents the discovered routes only.
+	DTOsDirectory string
+	// PluginRegistry lists the plugins whose OpenAPI resources are documented.
+	PluginRegistry *plugin.PluginRegistry
+	// Title, Version and Description fill the info block of the spec.
+	Title       string
+	Version     string
+	Description string
+	// HideOnProduction leaves the documentation routes out when Environment
+	// is "production".
+	HideOnProduction bool
+	// Environment is the environment the application runs in, matched
+	// against HideOnProduction, Environments and JSONOnlyEnvironments.
+	Environment string
+	// HealthSchema overrides the response schema of health routes.
+	HealthSchema map[string]interface{}
+	// MainDTO maps resource names to the type name of their main DTO.
+	MainDTO map[string]string
+	// CountHead documents a HEAD operation counting the items of collections.
+	CountHead bool
+	// HideFields lists property names, or patterns, dropped from schemas.
+	HideFields []string
+	// ResponseContentTypes lists extra media types offered by responses.
+	ResponseContentTypes []string
+	// AcceptHeader documents the Accept header of multi-content operations.
+	AcceptHeader bool
+	// LoadExamples reads <resource>.example.json files as schema examples.
+	LoadExamples bool
+	// RequestBodyDescription is the template describing request bodies.
+	RequestBodyDescription string
+	// CollectionFormat is CollectionFormatHydra or CollectionFormatLinkHeader.
+	CollectionFormat string
+	// ConditionalGet documents ETag revalidation on GET operations.
+	ConditionalGet bool
+	// SharedExamples moves loaded examples under components/examples.
+	SharedExamples bool
+	// IDParamDescription is the template describing the id path parameter.
+	IDParamDescription string
+	// IndexPage serves the resource index page next to the UI.
+	IndexPage bool
+	// BooleansOptional leaves non-pointer bool fields out of required.
+	BooleansOptional bool
+	// MaxRequestBodySize, in bytes, documents a 413 on operations with a body.
+	MaxRequestBodySize int
+	// BatchCreate lists the resources exposing POST {base}/batch.
+	BatchCreate []string
+	// DTOsBaseDirectory anchors a relative DTOsDirectory.
+	DTOsBaseDirectory string
+	// UnauthorizedResponse documents a 401 on operations requiring
+	// authentication.
+	UnauthorizedResponse bool
+	// ErrorSchema replaces the built-in Error schema.
+	ErrorSchema map[string]interface{}
+	// TagDescriptions describes tags by name.
+	TagDescriptions map[string]string
+	// IdempotentOverrides sets x-idempotent by "METHOD /path".
+	IdempotentOverrides map[string]bool
+	// OffsetMax caps the documented offset query parameter.
+	OffsetMax int
+	// ItemHead documents a HEAD operation checking that items exist.
+	ItemHead bool
+	// InterfaceSchema is InterfaceSchemaObject or InterfaceSchemaAny.
+	InterfaceSchema string
+	// RecursiveDTOs scans the subdirectories of DTOsDirectory as well.
+	RecursiveDTOs bool
+	// DirectoryTags tags DTO resources after their subdirectory.
+	DirectoryTags bool
+	// Security lists the alternative security requirements of operations.
+	Security []string
+	// APIKeyHeader registers the apiKey scheme read from this header.
+	APIKeyHeader string
+	// OperationSecurity overrides Security by "METHOD /path".
+	OperationSecurity map[string][]string
+	// SecurityScopes lists the scopes required by oauth2 and openIdConnect
+	// schemes, by scheme name.
+	SecurityScopes map[string][]string
+	// OperationScopes overrides SecurityScopes by "METHOD /path".
+	OperationScopes map[string][]string
+	// SynthesizeExamples builds examples from the property types.
+	SynthesizeExamples bool
+	// DTOVariants overrides the name patterns of the DTO variant roles.
+	DTOVariants map[string]string
+	// OutputFile is written with the spec at startup, as YAML for .yaml and
+	// .yml files and JSON otherwise.
+	OutputFile string
+	// OutputServerURL is the server of the spec written to OutputFile.
+	OutputServerURL string
+	// DocsPath and SpecPath are the routes of the UI and the JSON spec.
+	DocsPath string
+	SpecPath string
+	// UIRenderer renders the documentation page; Scalar when nil.
+	UIRenderer UIRenderer
+	// DocsBasicAuth and DocsToken protect the documentation routes, either
+	// granting access when both are set.
+	DocsBasicAuth BasicAuth
+	DocsToken     string
+	// DocsGuard must pass on every documentation route as well.
+	DocsGuard fiber.Handler
+	// Disabled turns every documentation route off (enabled: false).
+	Disabled bool
+	// Environments lists the environments serving the documentation,
+	// overriding HideOnProduction.
+	Environments []string
+	// JSONOnlyEnvironments lists the environments serving the JSON spec
+	// without the UI.
+	JSONOnlyEnvironments []string
+	// InternalOperations and InternalSchemas flag operations and schemas
+	// with x-internal, leaving them out of the public spec.
+	InternalOperations []string
+	InternalSchemas    []string
+	// InternalSpecPath serves the internal spec, behind InternalDocsToken.
+	InternalSpecPath  string
+	InternalDocsToken string
+	// Contact, License and TermsOfService are emitted under info.
+	Contact        map[string]string
+	License        map[string]string
+	TermsOfService string
+	// Servers replaces the server detected from each request.
+	Servers []Server
+	// BasePath prefixes every resource path.
+	BasePath string
+	// TagExternalDocs links tags by name to external documentation.
+	TagExternalDocs map[string]ExternalDocs
+	// TagOrder lists tag names in display order.
+	TagOrder []string
+	// Extensions injects vendor extensions into the spec.
+	Extensions Extensions
+	// SecuritySchemes declares additional security schemes by name.
+	SecuritySchemes map[string]SecurityScheme
+	// NoGlobalSecurity omits the top-level security requirement.
+	NoGlobalSecurity bool
+	// ServerURL is the single server of the spec; the host of each request
+	// when empty.
+	ServerURL string
+	// OperationIDStrategy is one of the OperationID constants.
+	OperationIDStrategy string
+	// OperationTexts overrides the summary and description templates.
+	OperationTexts map[string]OperationText
+	// Locale is the language of the generated texts; DefaultLocale when
+	// empty.
+	Locale string
+	// Locales lists the other languages served with ?lang=.
+	Locales []string
+	// Translations adds or overrides messages by locale and key.
+	Translations map[string]map[string]string
+	// PluralOverrides sets the plural of DTO resources by file name.
+	PluralOverrides map[string]string
+	// PathStyle is one of the PathStyle constants.
+	PathStyle string
+	// SingularPaths names resource paths after the singular resource.
+	SingularPaths bool
+	// ResourcePaths sets the collection path of resources by name.
+	ResourcePaths map[string]string
+	// EmbeddedStructs is EmbeddedFlatten or EmbeddedAllOf.
+	EmbeddedStructs string
+	// TypeFormats sets the format of DTO fields by Go type name.
+	TypeFormats map[string]string
+	// DeprecatedOperations lists the deprecated "METHOD /path" operations.
+	DeprecatedOperations []string
+	// DTOsFS reads DTOsDirectory from a file system instead of the disk.
+	DTOsFS fs.FS
+	// XMLContent offers bodies as application/xml as well.
+	XMLContent bool
+	// MainDTOPatterns lists the name patterns selecting main DTOs.
+	MainDTOPatterns []string
+	// ResourceNames names DTO resources by file name or relative path.
+	ResourceNames map[string]string
+	// WatchDTOs rebuilds the spec when the DTO files change; call Close to
+	// stop watching.
+	WatchDTOs bool
+	// GroupTags tags discovered routes by route group prefix.
+	GroupTags map[string]string
+	// IncludeRoutes and ExcludeRoutes filter discovered routes by path.
+	IncludeRoutes []string
+	ExcludeRoutes []string
+	// DiscoverMethods documents or leaves out discovered routes by method.
+	DiscoverMethods map[string]bool
+	// ResolveImportedTypes type-checks the types DTOs import from other
+	// packages.
+	ResolveImportedTypes bool
+}
+
+// BasicAuth holds the credentials protecting the documentation routes.
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+// DefaultOptions returns the options Initialize starts from.
+func DefaultOptions() Options {
+	return Options{
+		Title:            "GoREST API",
+		Version:          "1.0.0",
+		Description:      "Auto-generated REST API with full CRUD operations",
+		HideOnProduction: true,
+		Environment:      "development",
+		OutputServerURL:  "http://localhost:8000",
+	}
+}
+
+// NewPluginWithOptions returns a plugin configured from opts, overridden by
+// the OPENAPI_* environment variables, or the errors reported by
+// opts.Validate.
+func NewPluginWithOptions(opts Options) (*OpenAPIPlugin, error) {
+	envErr := opts.applyEnv(os.LookupEnv)
+	if err := errors.Join(envErr, opts.Validate()); err != nil {
+		return nil, err
+	}
+
+	p := &OpenAPIPlugin{}
+	p.applyOptions(opts)
+	return p, nil
+}
+
+// Validate checks the options for values the generator cannot honor and
+// returns every problem found, joined.
+func (o Options) Validate() error {
+	return errors.Join(
+		o.validateLimits(),
+		o.validateEnums(),
+		o.validateLocales(),
+		o.validateDTOs(),
+		o.validateMetadata(),
+		o.validateRouteFilters(),
+		o.validateDocsRoutes(),
+		o.validateSecurity(),
+		o.validateScopes(),
+	)
+}
+
+// validateLimits checks the pagination and size limits.
+func (o Options) validateLimits() error {
+	var errs []error
+	for _, limit := range []struct {
+		name  string
+		value int
+	}{
+		{"PaginationLimit", o.PaginationLimit},
+		{"PaginationMaxLimit", o.PaginationMaxLimit},
+		{"OffsetMax", o.OffsetMax},
+		{"MaxRequestBodySize", o.MaxRequestBodySize},
+	} {
+		if limit.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %d", limit.name, limit.value))
+		}
+	}
+	if o.PaginationMaxLimit > 0 && o.PaginationLimit > o.PaginationMaxLimit {
+		errs = append(errs, fmt.Errorf("PaginationMaxLimit %d is lower than PaginationLimit %d", o.PaginationMaxLimit, o.PaginationLimit))
+	}
+	return errors.Join(errs...)
+}
+
+// validateEnums checks the options taking one of a set of values.
+func (o Options) validateEnums() error {
+	var errs []error
+	if o.InterfaceSchema != "" && o.InterfaceSchema != InterfaceSchemaObject && o.InterfaceSchema != InterfaceSchemaAny {
+		errs = append(errs, fmt.Errorf("InterfaceSchema %q is not supported (supported: %s, %s)", o.InterfaceSchema, InterfaceSchemaObject, InterfaceSchemaAny))
+	}
+	if o.OperationIDStrategy != "" && o.OperationIDStrategy != OperationIDCamelCase && o.OperationIDStrategy != OperationIDSnakeCase && o.OperationIDStrategy != OperationIDRouteName {
+		errs = append(errs, fmt.Errorf("OperationIDStrategy %q is not supported (supported: %s, %s, %s)", o.OperationIDStrategy, OperationIDCamelCase, OperationIDSnakeCase, OperationIDRouteName))
+	}
+	for _, operation := range sortedKeys(o.OperationTexts) {
+		if !isOperationText(operation) {
+			errs = append(errs, fmt.Errorf("OperationTexts key %q is not supported", operation))
+		}
+	}
+	if o.EmbeddedStructs != "" && o.EmbeddedStructs != EmbeddedFlatten && o.EmbeddedStructs != EmbeddedAllOf {
+		errs = append(errs, fmt.Errorf("EmbeddedStructs %q is not supported (supported: %s, %s)", o.EmbeddedStructs, EmbeddedFlatten, EmbeddedAllOf))
+	}
+	if o.PathStyle != "" && o.PathStyle != PathStyleKebabCase && o.PathStyle != PathStyleSnakeCase {
+		errs = append(errs, fmt.Errorf("PathStyle %q is not supported (supported: %s, %s)", o.PathStyle, PathStyleKebabCase, PathStyleSnakeCase))
+	}
+	if o.CollectionFormat != "" && o.CollectionFormat != CollectionFormatHydra && o.CollectionFormat != CollectionFormatLinkHeader {
+		errs = append(errs, fmt.Errorf("CollectionFormat %q is not supported (supported: %s, %s)", o.CollectionFormat, CollectionFormatHydra, CollectionFormatLinkHeader))
+	}
+	for _, role := range sortedKeys(o.DTOVariants) {
+		if role != DTOVariantCreate && role != DTOVariantUpdate && role != DTOVariantList {
+			errs = append(errs, fmt.Errorf("DTOVariants role %q is not supported (supported: %s, %s, %s)", role, DTOVariantCreate, DTOVariantUpdate, DTOVariantList))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateLocales checks that the served locales have a message catalog.
+func (o Options) validateLocales() error {
+	var errs []error
+	if o.Locale != "" && !knownLocale(o.Locale, o.Translations) {
+		errs = append(errs, fmt.Errorf("Locale %q has no catalog (supported: %s)", o.Locale, strings.Join(supportedLocales(o.Translations), ", ")))
+	}
+	for _, locale := range o.Locales {
+		if !knownLocale(locale, o.Translations) {
+			errs = append(errs, fmt.Errorf("Locales entry %q has no catalog (supported: %s)", locale, strings.Join(supportedLocales(o.Translations), ", ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateDTOs checks the options naming and formatting DTO resources.
+func (o Options) validateDTOs() error {
+	var errs []error
+	for _, goType := range sortedKeys(o.TypeFormats) {
+		if o.TypeFormats[goType] == "" {
+			errs = append(errs, fmt.Errorf("TypeFormats entry %q has no format", goType))
+		}
+	}
+	for _, file := range sortedKeys(o.ResourceNames) {
+		if o.ResourceNames[file] == "" {
+			errs = append(errs, fmt.Errorf("ResourceNames entry %q has no name", file))
+		}
+	}
+	for _, resource := range sortedKeys(o.ResourcePaths) {
+		if strings.Trim(o.ResourcePaths[resource], "/") == "" {
+			errs = append(errs, fmt.Errorf("ResourcePaths entry %q has no path", resource))
+		}
+	}
+	for _, pattern := range o.MainDTOPatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("MainDTOPatterns pattern %q is invalid: %w", pattern, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateMetadata checks the tags and servers documented by the spec.
+func (o Options) validateMetadata() error {
+	var errs []error
+	for _, tag := range sortedKeys(o.TagExternalDocs) {
+		if o.TagExternalDocs[tag].URL == "" {
+			errs = append(errs, fmt.Errorf("TagExternalDocs entry %q has no URL", tag))
+		}
+	}
+	for i, server := range o.Servers {
+		if server.URL == "" {
+			errs = append(errs, fmt.Errorf("Servers[%d] has no URL", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateRouteFilters checks the options selecting and tagging discovered
+// routes.
+func (o Options) validateRouteFilters() error {
+	var errs []error
+	for _, prefix := range sortedKeys(o.GroupTags) {
+		if strings.Trim(prefix, "/") == "" {
+			errs = append(errs, fmt.Errorf("GroupTags entry %q has no path prefix", prefix))
+		}
+	}
+	discoverMethods := make(map[string]string, len(o.DiscoverMethods))
+	for _, method := range sortedKeys(o.DiscoverMethods) {
+		upper := strings.ToUpper(method)
+		switch {
+		case !slices.Contains(fiber.DefaultMethods, upper):
+			errs = append(errs, fmt.Errorf("DiscoverMethods entry %q is not an HTTP method", method))
+		case discoverMethods[upper] != "":
+			errs = append(errs, fmt.Errorf("DiscoverMethods entries %q and %q name the same method", discoverMethods[upper], method))
+		case o.DiscoverMethods[method] && !slices.Contains(openAPIMethods, upper):
+			errs = append(errs, fmt.Errorf("DiscoverMethods entry %q cannot be documented by OpenAPI", method))
+		}
+		discoverMethods[upper] = method
+	}
+	for _, routes := range []struct {
+		name     string
+		patterns []string
+	}{{"IncludeRoutes", o.IncludeRoutes}, {"ExcludeRoutes", o.ExcludeRoutes}} {
+		for _, pattern := range routes.patterns {
+			if _, err := compileRoutePattern(pattern); err != nil {
+				errs = append(errs, fmt.Errorf("%s pattern %q is invalid: %w", routes.name, pattern, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateDocsRoutes checks the documentation routes and their access
+// control.
+func (o Options) validateDocsRoutes() error {
+	var errs []error
+	if strings.HasSuffix(o.SpecPath, ".yaml") {
+		errs = append(errs, fmt.Errorf("SpecPath %q must name the JSON spec, the YAML one is derived from it", o.SpecPath))
+	} else if docs := resolveDocPaths(o.DocsPath, o.SpecPath); docs.UI == docs.JSON || docs.UI == docs.YAML {
+		errs = append(errs, fmt.Errorf("DocsPath and SpecPath must not serve the same route %q", docs.UI))
+	}
+	if (o.DocsBasicAuth.Username == "") != (o.DocsBasicAuth.Password == "") {
+		errs = append(errs, errors.New("DocsBasicAuth requires both a username and a password"))
+	}
+	if o.InternalSpecPath != "" && o.InternalDocsToken == "" {
+		errs = append(errs, fmt.Errorf("InternalSpecPath %s requires an InternalDocsToken", o.InternalSpecPath))
+	}
+	return errors.Join(errs...)
+}
+
+// validateSecurity checks the security schemes and the requirements
+// referencing them.
+func (o Options) validateSecurity() error {
+	var errs []error
+	for _, name := range sortedKeys(o.SecuritySchemes) {
+		if err := o.SecuritySchemes[name].validate(); err != nil {
+			errs = append(errs, fmt.Errorf("SecuritySchemes %q: %w", name, err))
+		}
+	}
+	schemes := o.declaredSecuritySchemes()
+	for _, name := range o.Security {
+		if name != "" && !slices.Contains(schemes, name) {
+			errs = append(errs, fmt.Errorf("Security references undeclared scheme %q", name))
+		}
+	}
+	for _, operation := range sortedKeys(o.OperationSecurity) {
+		for _, name := range o.OperationSecurity[operation] {
+			if name != "" && !slices.Contains(schemes, name) {
+				errs = append(errs, fmt.Errorf("OperationSecurity %q references undeclared scheme %q", operation, name))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateScopes checks the scopes required from oauth2 and openIdConnect
+// schemes.
+func (o Options) validateScopes() error {
+	var errs []error
+	var scoped []SecurityScheme
+	for _, name := range sortedKeys(o.SecuritySchemes) {
+		if scheme := o.SecuritySchemes[name]; scheme.Type == "oauth2" || scheme.Type == "openIdConnect" {
+			scoped = append(scoped, scheme)
+		}
+	}
+	for _, name := range sortedKeys(o.SecurityScopes) {
+		scheme, ok := o.SecuritySchemes[name]
+		if !ok || (scheme.Type != "oauth2" && scheme.Type != "openIdConnect") {
+			errs = append(errs, fmt.Errorf("SecurityScopes entry %q is not an oauth2 or openIdConnect scheme", name))
+			continue
+		}
+		for _, scope := range o.SecurityScopes[name] {
+			if !scheme.declaresScope(scope) {
+				errs = append(errs, fmt.Errorf("SecurityScopes entry %q requests scope %q its flows do not declare", name, scope))
+			}
+		}
+	}
+	for _, operation := range sortedKeys(o.OperationScopes) {
+		for _, scope := range o.OperationScopes[operation] {
+			if !slices.ContainsFunc(scoped, func(s SecurityScheme) bool { return s.declaresScope(scope) }) {
+				errs = append(errs, fmt.Errorf("OperationScopes %q requests scope %q no oauth2 or openIdConnect scheme declares", operation, scope))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// declaresScope reports whether a scope can be required from the scheme: one
+// of the scopes of an oauth2 flow, or any scope of an openIdConnect scheme,
+// listed by its discovery document.
+func (s SecurityScheme) declaresScope(scope string) bool {
+	switch s.Type {
+	case "openIdConnect":
+		return true
+	case "oauth2":
+		if s.Flows == nil {
+			return false
+		}
+		for _, flow := range []*OAuthFlow{s.Flows.Implicit, s.Flows.Password, s.Flows.ClientCredentials, s.Flows.AuthorizationCode} {
+			if flow == nil {
+				continue
+			}
+			if _, ok := flow.Scopes[scope]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// declaredSecuritySchemes lists the names of the security schemes the
+// generated spec declares.
+func (o Options) declaredSecuritySchemes() []string {
+	var names []string
+	if !o.NoGlobalSecurity {
+		names = append(names, "bearerAuth")
+	}
+	if o.APIKeyHeader != "" {
+		names = append(names, "apiKey")
+	}
+	for name := range o.SecuritySchemes {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (s SecurityScheme) validate() error {
+	switch s.Type {
+	case "apiKey":
+		if s.Name == "" {
+			return errors.New("apiKey scheme requires a name")
+		}
+		if s.In != "header" && s.In != "query" && s.In != "cookie" {
+			return fmt.Errorf("apiKey location %q is not supported (supported: header, query, cookie)", s.In)
+		}
+	case "http":
+		if s.Scheme == "" {
+			return errors.New("http scheme requires a scheme (e.g. basic, bearer)")
+		}
+	case "oauth2":
+		if s.Flows == nil || (s.Flows.Implicit == nil && s.Flows.Password == nil && s.Flows.ClientCredentials == nil && s.Flows.AuthorizationCode == nil) {
+			return errors.New("oauth2 scheme requires at least one flow")
+		}
+	case "openIdConnect":
+		if s.OpenIDConnectURL == "" {
+			return errors.New("openIdConnect scheme requires an OpenID Connect URL")
+		}
+	default:
+		return fmt.Errorf("type %q is not supported (supported: apiKey, http, oauth2, openIdConnect)", s.Type)
+	}
+	return nil
+}
+
+// generatorConfig maps the options shared with the generator onto a
+// GeneratorConfig.
+func (o Options) generatorConfig() GeneratorConfig {
+	return GeneratorConfig{
+		DTOsDirectory:          o.DTOsDirectory,
+		PluginRegistry:         o.PluginRegistry,
+		PaginationLimit:        o.PaginationLimit,
+		PaginationMaxLimit:     o.PaginationMaxLimit,
+		Title:                  o.Title,
+		Version:                o.Version,
+		Description:            o.Description,
+		HealthSchema:           o.HealthSchema,
+		MainDTO:                o.MainDTO,
+		CountHead:              o.CountHead,
+		HideFields:             o.HideFields,
+		ResponseContentTypes:   o.ResponseContentTypes,
+		AcceptHeader:           o.AcceptHeader,
+		LoadExamples:           o.LoadExamples,
+		RequestBodyDescription: o.RequestBodyDescription,
+		CollectionFormat:       o.CollectionFormat,
+		ConditionalGet:         o.ConditionalGet,
+		SharedExamples:         o.SharedExamples,
+		IDParamDescription:     o.IDParamDescription,
+		BooleansOptional:       o.BooleansOptional,
+		MaxRequestBodySize:     o.MaxRequestBodySize,
+		BatchCreate:            o.BatchCreate,
+		DTOsBaseDirectory:      o.DTOsBaseDirectory,
+		UnauthorizedResponse:   o.UnauthorizedResponse,
+		ErrorSchema:            o.ErrorSchema,
+		TagDescriptions:        o.TagDescriptions,
+		IdempotentOverrides:    o.IdempotentOverrides,
+		OffsetMax:              o.OffsetMax,
+		ItemHead:               o.ItemHead,
+		InterfaceSchema:        o.InterfaceSchema,
+		RecursiveDTOs:          o.RecursiveDTOs,
+		DirectoryTags:          o.DirectoryTags,
+		Security:               o.Security,
+		APIKeyHeader:           o.APIKeyHeader,
+		OperationSecurity:      o.OperationSecurity,
+		SecurityScopes:         o.SecurityScopes,
+		OperationScopes:        o.OperationScopes,
+		SynthesizeExamples:     o.SynthesizeExamples,
+		DTOVariants:            o.DTOVariants,
+		DocsPath:               o.DocsPath,
+		SpecPath:               o.SpecPath,
+		InternalOperations:     o.InternalOperations,
+		InternalSchemas:        o.InternalSchemas,
+		InternalSpecPath:       o.InternalSpecPath,
+		Contact:                o.Contact,
+		License:                o.License,
+		TermsOfService:         o.TermsOfService,
+		BasePath:               o.BasePath,
+		TagExternalDocs:        o.TagExternalDocs,
+		TagOrder:               o.TagOrder,
+		Extensions:             o.Extensions,
+		SecuritySchemes:        o.SecuritySchemes,
+		NoGlobalSecurity:       o.NoGlobalSecurity,
+		OperationIDStrategy:    o.OperationIDStrategy,
+		OperationTexts:         o.OperationTexts,
+		Translations:           o.Translations,
+		PluralOverrides:        o.PluralOverrides,
+		PathStyle:              o.PathStyle,
+		SingularPaths:          o.SingularPaths,
+		ResourcePaths:          o.ResourcePaths,
+		EmbeddedStructs:        o.EmbeddedStructs,
+		TypeFormats:            o.TypeFormats,
+		DeprecatedOperations:   o.DeprecatedOperations,
+		DTOsFS:                 o.DTOsFS,
+		XMLContent:             o.XMLContent,
+		MainDTOPatterns:        o.MainDTOPatterns,
+		ResourceNames:          o.ResourceNames,
+		GroupTags:              o.GroupTags,
+		IncludeRoutes:          o.IncludeRoutes,
+		ExcludeRoutes:          o.ExcludeRoutes,
+		DiscoverMethods:        o.DiscoverMethods,
+		ResolveImportedTypes:   o.ResolveImportedTypes,
+	}
+}
+
+func (p *OpenAPIPlugin) applyOptions(opts Options) {
+	if opts.Locale == "" {
+		opts.Locale = DefaultLocale
+	}
+	p.opts = opts
+}
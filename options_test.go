@@ -0,0 +1,216 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefaultOptions_MatchesInitialize(t *testing.T) {
+	fromOptions, err := NewPluginWithOptions(DefaultOptions())
+	if err != nil {
+		t.Fatalf("NewPluginWithOptions() error = %v", err)
+	}
+
+	fromConfig := &OpenAPIPlugin{}
+	if err := fromConfig.Initialize(map[string]interface{}{}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(fromOptions, fromConfig) {
+		t.Errorf("NewPluginWithOptions(DefaultOptions()) = %+v, want %+v", fromOptions, fromConfig)
+	}
+}
+
+func TestNewPluginWithOptions(t *testing.T) {
+	opts := DefaultOptions()
+	opts.DTOsDirectory = "./dtos"
+	opts.PaginationLimit = 20
+	opts.PaginationMaxLimit = 100
+	opts.DocsBasicAuth = BasicAuth{Username: "docs", Password: "secret"}
+	opts.Disabled = true
+
+	plugin, err := NewPluginWithOptions(opts)
+	if err != nil {
+		t.Fatalf("NewPluginWithOptions() error = %v", err)
+	}
+
+	if plugin.opts.DTOsDirectory != "./dtos" || plugin.opts.PaginationLimit != 20 || plugin.opts.PaginationMaxLimit != 100 {
+		t.Errorf("plugin = %+v, want the configured directory and limits", plugin)
+	}
+	if plugin.opts.DocsBasicAuth != opts.DocsBasicAuth {
+		t.Errorf("docsBasicAuth = %+v, want %+v", plugin.opts.DocsBasicAuth, opts.DocsBasicAuth)
+	}
+	if plugin.docsMode() != docsModeOff {
+		t.Errorf("docsMode() = %v, want %v", plugin.docsMode(), docsModeOff)
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(o *Options)
+		wantErr []string
+	}{
+		{
+			name:   "defaults",
+			modify: func(o *Options) {},
+		},
+		{
+			name: "negative limits",
+			modify: func(o *Options) {
+				o.PaginationLimit = -1
+				o.MaxRequestBodySize = -5
+			},
+			wantErr: []string{"PaginationLimit must not be negative", "MaxRequestBodySize must not be negative"},
+		},
+		{
+			name: "max limit lower than default limit",
+			modify: func(o *Options) {
+				o.PaginationLimit = 50
+				o.PaginationMaxLimit = 10
+			},
+			wantErr: []string{"PaginationMaxLimit 10 is lower than PaginationLimit 50"},
+		},
+		{
+			name: "unsupported enumerations",
+			modify: func(o *Options) {
+				o.InterfaceSchema = "string"
+				o.CollectionFormat = "csv"
+				o.PathStyle = "camelCase"
+				o.EmbeddedStructs = "inline"
+				o.ResourcePaths = map[string]string{"order_item": "/"}
+				o.TypeFormats = map[string]string{"Email": ""}
+				o.TagExternalDocs = map[string]ExternalDocs{"User": {Description: "User guide"}}
+				o.DTOVariants = map[string]string{"delete": "*Delete*"}
+				o.MainDTOPatterns = []string{"*Response[DTO"}
+				o.ResourceNames = map[string]string{"user_dtos": ""}
+				o.GroupTags = map[string]string{"/": "Root"}
+				o.IncludeRoutes = []string{"api/**"}
+				o.ExcludeRoutes = []string{"^/debug/(", "/[metrics"}
+				o.DiscoverMethods = map[string]bool{"head": false, "HEAD": true, "FETCH": false, "CONNECT": true}
+			},
+			wantErr: []string{`ResourceNames entry "user_dtos" has no name`, `GroupTags entry "/" has no path prefix`, `InterfaceSchema "string"`, `CollectionFormat "csv"`, `PathStyle "camelCase"`, `EmbeddedStructs "inline"`, `ResourcePaths entry "order_item" has no path`, `TypeFormats entry "Email" has no format`, `TagExternalDocs entry "User" has no URL`, `DTOVariants role "delete"`, `MainDTOPatterns pattern "*Response[DTO"`, `IncludeRoutes pattern "api/**"`, `ExcludeRoutes pattern "^/debug/("`, `ExcludeRoutes pattern "/[metrics"`, `DiscoverMethods entry "FETCH" is not an HTTP method`, `DiscoverMethods entries "HEAD" and "head" name the same method`, `DiscoverMethods entry "CONNECT" cannot be documented by OpenAPI`},
+		},
+		{
+			name: "locales without catalog",
+			modify: func(o *Options) {
+				o.Locale = "es"
+				o.Locales = []string{"fr", "it"}
+			},
+			wantErr: []string{`Locale "es" has no catalog`, `Locales entry "it" has no catalog`},
+		},
+		{
+			name: "incomplete access control",
+			modify: func(o *Options) {
+				o.DocsBasicAuth = BasicAuth{Username: "docs"}
+				o.InternalSpecPath = "/internal/openapi.json"
+			},
+			wantErr: []string{"DocsBasicAuth requires both", "requires an InternalDocsToken"},
+		},
+		{
+			name: "YAML spec path",
+			modify: func(o *Options) {
+				o.SpecPath = "/docs/spec.yaml"
+			},
+			wantErr: []string{`SpecPath "/docs/spec.yaml" must name the JSON spec`},
+		},
+		{
+			name: "docs path serving the spec",
+			modify: func(o *Options) {
+				o.DocsPath = "/docs"
+				o.SpecPath = "/docs"
+			},
+			wantErr: []string{`DocsPath and SpecPath must not serve the same route "/docs"`},
+		},
+		{
+			name: "servers without URL",
+			modify: func(o *Options) {
+				o.Servers = []Server{{URL: "https://api.example.com"}, {Description: "missing"}}
+			},
+			wantErr: []string{"Servers[1] has no URL"},
+		},
+		{
+			name: "invalid security schemes",
+			modify: func(o *Options) {
+				o.SecuritySchemes = map[string]SecurityScheme{
+					"key":    {Type: "apiKey", Name: "api_key", In: "body"},
+					"oauth":  {Type: "oauth2"},
+					"oidc":   {Type: "openIdConnect"},
+					"custom": {Type: "mutualTLS"},
+				}
+			},
+			wantErr: []string{`"key": apiKey location "body"`, `"oauth": oauth2 scheme requires at least one flow`, `"oidc": openIdConnect scheme requires`, `"custom": type "mutualTLS"`},
+		},
+		{
+			name: "undeclared security references",
+			modify: func(o *Options) {
+				o.NoGlobalSecurity = true
+				o.Security = []string{"bearerAuth", ""}
+				o.OperationSecurity = map[string][]string{"GET /users": {"apiKey"}}
+			},
+			wantErr: []string{`Security references undeclared scheme "bearerAuth"`, `"GET /users" references undeclared scheme "apiKey"`},
+		},
+		{
+			name: "invalid security scopes",
+			modify: func(o *Options) {
+				o.APIKeyHeader = "X-API-Key"
+				o.SecuritySchemes = map[string]SecurityScheme{
+					"oauth": {Type: "oauth2", Flows: &OAuthFlows{
+						Implicit: &OAuthFlow{AuthorizationURL: "https://auth.example.com/authorize", Scopes: map[string]string{"orders:read": "Read orders"}},
+					}},
+				}
+				o.SecurityScopes = map[string][]string{"apiKey": {"orders:read"}, "oauth": {"orders:write"}}
+				o.OperationScopes = map[string][]string{"GET /orders": {"orders:read", "admin"}}
+			},
+			wantErr: []string{`SecurityScopes entry "apiKey" is not an oauth2 or openIdConnect scheme`, `SecurityScopes entry "oauth" requests scope "orders:write"`, `OperationScopes "GET /orders" requests scope "admin"`},
+		},
+		{
+			name: "declared security scopes",
+			modify: func(o *Options) {
+				o.SecuritySchemes = map[string]SecurityScheme{
+					"oauth": {Type: "oauth2", Flows: &OAuthFlows{
+						Implicit: &OAuthFlow{AuthorizationURL: "https://auth.example.com/authorize", Scopes: map[string]string{"orders:read": "Read orders"}},
+					}},
+					"oidc": {Type: "openIdConnect", OpenIDConnectURL: "https://auth.example.com/.well-known/openid-configuration"},
+				}
+				o.SecurityScopes = map[string][]string{"oauth": {"orders:read"}, "oidc": {"openid"}}
+				o.OperationScopes = map[string][]string{"GET /orders": {"profile"}}
+			},
+		},
+		{
+			name: "declared security references",
+			modify: func(o *Options) {
+				o.APIKeyHeader = "X-API-Key"
+				o.SecuritySchemes = map[string]SecurityScheme{"basicAuth": {Type: "http", Scheme: "basic"}}
+				o.Security = []string{"bearerAuth", "apiKey", "basicAuth"}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tt.modify(&opts)
+
+			err := opts.Validate()
+			if len(tt.wantErr) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() error = nil, want %v", tt.wantErr)
+			}
+			for _, want := range tt.wantErr {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate() error = %q, want it to contain %q", err, want)
+				}
+			}
+			if _, err := NewPluginWithOptions(opts); err == nil {
+				t.Error("NewPluginWithOptions() should reject invalid options")
+			}
+		})
+	}
+}
@@ -11,8 +11,11 @@ func loadResourcesFromPlugins(registry *plugin.PluginRegistry) []plugin.OpenAPIR
 
 	var resources []plugin.OpenAPIResource
 
-	for _, p := range registry.GetAll() {
-		provider, ok := p.(plugin.OpenAPIProvider)
+	// Walk plugins by name so resources, and the spec built from them, come
+	// out in the same order on every run
+	plugins := registry.GetAll()
+	for _, name := range sortedKeys(plugins) {
+		provider, ok := plugins[name].(plugin.OpenAPIProvider)
 		if !ok {
 			continue
 		}
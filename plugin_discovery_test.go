@@ -0,0 +1,72 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/nicolasbonnici/gorest/plugin"
+)
+
+type stubResourcePlugin struct {
+	name      string
+	resources []plugin.OpenAPIResource
+}
+
+func (p stubResourcePlugin) Name() string                                  { return p.name }
+func (p stubResourcePlugin) Initialize(map[string]any) error               { return nil }
+func (p stubResourcePlugin) Handler() fiber.Handler                        { return nil }
+func (p stubResourcePlugin) GetOpenAPIResources() []plugin.OpenAPIResource { return p.resources }
+
+func TestLoadResourcesFromPlugins(t *testing.T) {
+	tests := []struct {
+		name    string
+		plugins []plugin.Plugin
+		want    []string
+	}{
+		{
+			name: "nil registry",
+			want: nil,
+		},
+		{
+			name: "plugins walked by name",
+			plugins: []plugin.Plugin{
+				stubResourcePlugin{name: "comments", resources: []plugin.OpenAPIResource{{Name: "comment"}}},
+				stubResourcePlugin{name: "auth", resources: []plugin.OpenAPIResource{{Name: "user"}, {Name: "session"}}},
+				stubResourcePlugin{name: "blog", resources: []plugin.OpenAPIResource{{Name: "post"}}},
+			},
+			want: []string{"user", "session", "post", "comment"},
+		},
+		{
+			name: "plugins without resources skipped",
+			plugins: []plugin.Plugin{
+				&OpenAPIPlugin{},
+				stubResourcePlugin{name: "blog", resources: []plugin.OpenAPIResource{{Name: "post"}}},
+			},
+			want: []string{"post"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var registry *plugin.PluginRegistry
+			if tt.plugins != nil {
+				registry = plugin.NewPluginRegistry()
+				for _, p := range tt.plugins {
+					registry.Register(p)
+				}
+			}
+
+			// Map iteration order varies between runs, so check a few times
+			for range 10 {
+				var got []string
+				for _, resource := range loadResourcesFromPlugins(registry) {
+					got = append(got, resource.Name)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("loadResourcesFromPlugins() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package openapi
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// queryTag is the struct tag naming the query parameter a field binds, the
+// one fiber's query binder reads.
+const queryTag = "query"
+
+// queryParameters documents the fields of a params struct tagged
+// `query:"name"` as query parameters: their type from the field type, enums,
+// bounds and formats from the validate tag, plus the default and description
+// tags. Fields without a query tag are left out, and embedded structs have
+// their fields promoted.
+func queryParameters(params interface{}) []map[string]interface{} {
+	t := modelType(params)
+	if t == nil {
+		return nil
+	}
+	return appendQueryParameters(nil, t, map[reflect.Type]bool{t: true})
+}
+
+func appendQueryParameters(parameters []map[string]interface{}, t reflect.Type, visiting map[reflect.Type]bool) []map[string]interface{} {
+	models := newModelSchemaBuilder(nil)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get(queryTag)
+		if field.Anonymous && tag == "" && indirectKind(field.Type) == reflect.Struct {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if !visiting[embedded] {
+				visiting[embedded] = true
+				parameters = appendQueryParameters(parameters, embedded, visiting)
+				delete(visiting, embedded)
+			}
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		isPointer := fieldType.Kind() == reflect.Ptr
+		if isPointer {
+			fieldType = fieldType.Elem()
+		}
+
+		schema := models.propertySchema(fieldType)
+		validateTag := field.Tag.Get("validate")
+		applyValidationRules(schema, validateTag)
+		applyEnumLabels(schema, field.Tag.Get("enum_labels"))
+		applyDefaultTag(schema, field.Tag.Get("default"))
+
+		parameter := map[string]interface{}{
+			"name":     name,
+			"in":       "query",
+			"required": !isPointer && slices.Contains(strings.Split(validateTag, ","), "required"),
+			"schema":   schema,
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			parameter["description"] = description
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters
+}
+
+// mergeParameters adds parameters to an operation's existing ones, replacing
+// those of the same name and location.
+func mergeParameters(existing interface{}, parameters []map[string]interface{}) []map[string]interface{} {
+	current, _ := existing.([]map[string]interface{})
+	merged := slices.DeleteFunc(slices.Clone(current), func(param map[string]interface{}) bool {
+		return slices.ContainsFunc(parameters, func(added map[string]interface{}) bool {
+			return added["name"] == param["name"] && added["in"] == param["in"]
+		})
+	})
+	return append(merged, parameters...)
+}
@@ -0,0 +1,97 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type pageParams struct {
+	Page  int `query:"page" default:"1" validate:"min=1"`
+	Limit int `query:"limit" default:"20" validate:"min=1,max=100"`
+}
+
+type searchParams struct {
+	pageParams
+	Term   string   `query:"q" validate:"required" description:"Search terms"`
+	Status *string  `query:"status" validate:"oneof=draft live"`
+	Tags   []string `query:"tags"`
+	Debug  bool     `query:"-"`
+	Cursor string
+	secret string `query:"secret"`
+}
+
+func TestQueryParameters(t *testing.T) {
+	got := queryParameters(&searchParams{})
+
+	want := []map[string]interface{}{
+		{"name": "page", "in": "query", "required": false, "schema": map[string]interface{}{"type": "integer", "format": "int32", "minimum": 1, "default": int64(1)}},
+		{"name": "limit", "in": "query", "required": false, "schema": map[string]interface{}{"type": "integer", "format": "int32", "minimum": 1, "maximum": 100, "default": int64(20)}},
+		{"name": "q", "in": "query", "required": true, "description": "Search terms", "schema": map[string]interface{}{"type": "string"}},
+		{"name": "status", "in": "query", "required": false, "schema": map[string]interface{}{"type": "string", "enum": []string{"draft", "live"}}},
+		{"name": "tags", "in": "query", "required": false, "schema": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("queryParameters() = %v, want %v", got, want)
+	}
+
+	if params := queryParameters("page"); params != nil {
+		t.Errorf("queryParameters(non-struct) = %v, want nil", params)
+	}
+}
+
+func TestMergeParameters(t *testing.T) {
+	id := map[string]interface{}{"name": "id", "in": "path"}
+	oldPage := map[string]interface{}{"name": "page", "in": "query", "schema": map[string]interface{}{"type": "string"}}
+	page := map[string]interface{}{"name": "page", "in": "query", "schema": map[string]interface{}{"type": "integer"}}
+
+	tests := []struct {
+		name     string
+		existing interface{}
+		want     []map[string]interface{}
+	}{
+		{name: "no parameters", existing: nil, want: []map[string]interface{}{page}},
+		{name: "path parameters kept", existing: []map[string]interface{}{id}, want: []map[string]interface{}{id, page}},
+		{name: "same name replaced", existing: []map[string]interface{}{oldPage, id}, want: []map[string]interface{}{id, page}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeParameters(tt.existing, []map[string]interface{}{page}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeParameters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribe_QueryParams(t *testing.T) {
+	app := fiber.New()
+	app.Get("/search/:scope", func(c fiber.Ctx) error { return nil })
+
+	p := NewPlugin().(*OpenAPIPlugin)
+	if err := p.Initialize(map[string]interface{}{}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if err := p.Describe("GET", "/search/:scope", Operation{Query: "q"}); err == nil {
+		t.Error("Describe() with non-struct query params should fail")
+	}
+	if err := p.Describe("GET", "/search/:scope", Operation{Query: searchParams{}}); err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(app, p.generatorConfig())
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	op := spec["paths"].(map[string]interface{})["/search/{scope}"].(map[string]interface{})["get"].(map[string]interface{})
+	var names []string
+	for _, param := range op["parameters"].([]map[string]interface{}) {
+		names = append(names, param["in"].(string)+":"+param["name"].(string))
+	}
+	want := []string{"path:scope", "query:page", "query:limit", "query:q", "query:status", "query:tags"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("parameters = %v, want %v", names, want)
+	}
+}
@@ -1,34 +1,75 @@
 package openapi
 
 import (
+	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/nicolasbonnici/gorest/plugin"
 )
 
-func buildSchemaFromModel(model interface{}) map[string]interface{} {
+// modelSchemaBuilder builds schemas from Go types by reflection. Fields typed
+// with a struct listed in refs are emitted as a $ref to its component schema,
+// which lets recursive models reference themselves; visiting holds the
+// structs being expanded so embedding cycles terminate.
+type modelSchemaBuilder struct {
+	refs     map[reflect.Type]string
+	visiting map[reflect.Type]bool
+}
+
+func newModelSchemaBuilder(refs map[reflect.Type]string) *modelSchemaBuilder {
+	return &modelSchemaBuilder{refs: refs, visiting: make(map[reflect.Type]bool)}
+}
+
+// modelType returns the struct type of a model, dereferencing pointers, or
+// nil for anything else.
+func modelType(model interface{}) reflect.Type {
 	if model == nil {
-		return map[string]interface{}{"type": "object"}
+		return nil
 	}
-
 	t := reflect.TypeOf(model)
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
-
 	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// modelRefs maps the response model type of each plugin resource to its
+// component schema name, so fields typed with a resource model reference it.
+func modelRefs(resources []plugin.OpenAPIResource) map[reflect.Type]string {
+	refs := make(map[reflect.Type]string)
+	for _, resource := range resources {
+		if t := modelType(resource.ResponseModel); t != nil {
+			refs[t] = strings.ToUpper(resource.Name[:1]) + resource.Name[1:]
+		}
+	}
+	return refs
+}
+
+func buildSchemaFromModel(model interface{}) map[string]interface{} {
+	return newModelSchemaBuilder(nil).build(model)
+}
+
+func (b *modelSchemaBuilder) build(model interface{}) map[string]interface{} {
+	t := modelType(model)
+	if t == nil {
 		return map[string]interface{}{"type": "object"}
 	}
+	return b.structSchema(t)
+}
 
+func (b *modelSchemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
 	properties := make(map[string]interface{})
 	required := []string{}
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		processStructField(field, properties, &required)
-	}
+	b.visiting[t] = true
+	b.addStructFields(t, properties, &required)
+	delete(b.visiting, t)
 
 	schema := map[string]interface{}{
 		"type":       "object",
@@ -42,7 +83,45 @@ func buildSchemaFromModel(model interface{}) map[string]interface{} {
 	return schema
 }
 
-func processStructField(field reflect.StructField, properties map[string]interface{}, required *[]string) {
+// addStructFields documents the fields of t, promoting those of untagged
+// embedded structs like encoding/json does: fields of t win over promoted
+// ones. A struct embedding itself, directly or not, is promoted once.
+func (b *modelSchemaBuilder) addStructFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" && indirectKind(field.Type) == reflect.Struct {
+			if field.Type.Kind() == reflect.Ptr {
+				embedded = append(embedded, field.Type.Elem())
+			} else {
+				embedded = append(embedded, field.Type)
+			}
+			continue
+		}
+		b.processStructField(field, properties, required)
+	}
+
+	for _, e := range embedded {
+		if b.visiting[e] {
+			continue
+		}
+		b.visiting[e] = true
+		promoted := make(map[string]interface{})
+		var promotedRequired []string
+		b.addStructFields(e, promoted, &promotedRequired)
+		delete(b.visiting, e)
+		for _, name := range sortedKeys(promoted) {
+			if _, ok := properties[name]; !ok {
+				properties[name] = promoted[name]
+				if slices.Contains(promotedRequired, name) {
+					*required = append(*required, name)
+				}
+			}
+		}
+	}
+}
+
+func (b *modelSchemaBuilder) processStructField(field reflect.StructField, properties map[string]interface{}, required *[]string) {
 	if !field.IsExported() {
 		return
 	}
@@ -65,13 +144,31 @@ func processStructField(field reflect.StructField, properties map[string]interfa
 		fieldType = fieldType.Elem()
 	}
 
-	property := buildPropertySchema(fieldType, field.Tag)
-	property["nullable"] = isPointer
+	property := b.propertySchema(fieldType)
+	if keyword, members := unionTag(field.Tag.Get(oneOfTypesTag), field.Tag.Get(anyOfTypesTag)); len(members) > 0 {
+		property = unionSchema(typeString(fieldType), keyword, members, GeneratorConfig{dtoRefs: b.refsByName()})
+	}
+	// nullable defaults to false, only pointers need it
+	if isPointer {
+		property["nullable"] = true
+	}
 
 	validateTag := field.Tag.Get("validate")
 	applyValidationRules(property, validateTag)
+	applyEnumLabels(property, field.Tag.Get("enum_labels"))
+	applyNullableEnum(property)
+	applyOpenAPITag(property, field.Tag.Get("openapi"))
+	if description := field.Tag.Get("description"); description != "" {
+		property["description"] = description
+	}
+	if deprecated, _ := strconv.ParseBool(field.Tag.Get("deprecated")); deprecated {
+		property["deprecated"] = true
+	}
+	applyDefaultTag(property, field.Tag.Get("default"))
+	applyXMLTag(property, field.Tag.Get("xml"))
+	applyAccessConvention(property, jsonName)
 
-	properties[jsonName] = property
+	properties[jsonName] = wrapRefSiblings(property)
 
 	if shouldBeRequired(jsonName, isPointer, isOmitEmpty, validateTag) {
 		*required = append(*required, jsonName)
@@ -91,15 +188,23 @@ func shouldBeRequired(jsonName string, isPointer, isOmitEmpty bool, validateTag
 	return true
 }
 
-func buildPropertySchema(t reflect.Type, tag reflect.StructTag) map[string]interface{} {
+func (b *modelSchemaBuilder) propertySchema(t reflect.Type) map[string]interface{} {
+	if name, ok := b.refs[t]; ok {
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+
+	if property, ok := libraryTypeSchema(t.String()); ok {
+		return property
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	}
+
 	property := make(map[string]interface{})
 
 	switch t.Kind() {
 	case reflect.String:
 		property["type"] = "string"
-		if t == reflect.TypeOf(uuid.UUID{}) {
-			property["format"] = "uuid"
-		}
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
 		property["type"] = "integer"
 		property["format"] = "int32"
@@ -120,18 +225,23 @@ func buildPropertySchema(t reflect.Type, tag reflect.StructTag) map[string]inter
 		if t == reflect.TypeOf(time.Time{}) {
 			property["type"] = "string"
 			property["format"] = "date-time"
-		} else if t == reflect.TypeOf(uuid.UUID{}) {
-			property["type"] = "string"
-			property["format"] = "uuid"
+		} else if t.Name() == "" {
+			// Anonymous structs have no component to reference, inline them
+			property = b.structSchema(t)
 		} else {
 			property["type"] = "object"
 		}
 	case reflect.Slice, reflect.Array:
 		property["type"] = "array"
 		elemType := t.Elem()
-		property["items"] = buildPropertySchema(elemType, "")
+		property["items"] = b.propertySchema(elemType)
 	case reflect.Map:
 		property["type"] = "object"
+		if t.Elem().Kind() != reflect.Interface {
+			property["additionalProperties"] = b.propertySchema(t.Elem())
+		}
+	case reflect.Interface:
+		property["type"] = "object"
 	default:
 		property["type"] = "string"
 	}
@@ -139,6 +249,8 @@ func buildPropertySchema(t reflect.Type, tag reflect.StructTag) map[string]inter
 	return property
 }
 
+// applyValidationRules documents the rules of a `validate` struct tag on a
+// property schema, for plugin models and DTOs alike.
 func applyValidationRules(property map[string]interface{}, validateTag string) {
 	if validateTag == "" {
 		return
@@ -173,6 +285,10 @@ func applyValidationRule(property map[string]interface{}, ruleName, ruleValue st
 		property["format"] = "uri"
 	case "oneof":
 		applyOneOfRule(property, ruleValue)
+	case "unique":
+		if property["type"] == "array" {
+			property["uniqueItems"] = true
+		}
 	}
 }
 
@@ -219,3 +335,107 @@ func parseIntOrZero(s string) int {
 	}
 	return val
 }
+
+// applyEnumLabels reads an `enum_labels:"value=Label,..."` tag and emits the
+// labels as x-enum-descriptions, aligned index-for-index with the property's
+// enum, whose values are matched in their text form (1=Low for an int
+// enum). When no enum was declared (e.g. via oneof), the tag's values become
+// the enum in declaration order.
+func applyEnumLabels(property map[string]interface{}, labelsTag string) {
+	if labelsTag == "" {
+		return
+	}
+
+	labels := make(map[string]string)
+	var order []string
+	for _, pair := range strings.Split(labelsTag, ",") {
+		value, label, _ := strings.Cut(pair, "=")
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		labels[value] = strings.TrimSpace(label)
+		order = append(order, value)
+	}
+
+	var values []string
+	switch enum := property["enum"].(type) {
+	case []string:
+		values = enum
+	case []interface{}:
+		for _, value := range enum {
+			values = append(values, fmt.Sprint(value))
+		}
+	default:
+		values = order
+		property["enum"] = values
+	}
+
+	descriptions := make([]string, len(values))
+	for i, value := range values {
+		descriptions[i] = labels[value]
+	}
+	property["x-enum-descriptions"] = descriptions
+}
+
+// applyNullableEnum adds null to the enum of a nullable property: OpenAPI 3.0
+// validates enum before nullable, so a null value must be listed explicitly.
+// x-enum-descriptions gets an empty entry to stay aligned with the enum.
+func applyNullableEnum(property map[string]interface{}) {
+	if nullable, _ := property["nullable"].(bool); !nullable {
+		return
+	}
+	var enum []interface{}
+	switch values := property["enum"].(type) {
+	case []string:
+		enum = make([]interface{}, 0, len(values)+1)
+		for _, value := range values {
+			enum = append(enum, value)
+		}
+	case []interface{}:
+		if slices.Contains(values, nil) {
+			return
+		}
+		enum = slices.Clone(values)
+	default:
+		return
+	}
+	property["enum"] = append(enum, nil)
+
+	if descriptions, ok := property["x-enum-descriptions"].([]string); ok {
+		property["x-enum-descriptions"] = append(descriptions, "")
+	}
+}
+
+func indirectKind(t reflect.Type) reflect.Kind {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem().Kind()
+	}
+	return t.Kind()
+}
+
+// refsByName returns the referenced model components by type name, the way
+// union struct tags name their members.
+func (b *modelSchemaBuilder) refsByName() map[string]string {
+	refs := make(map[string]string, len(b.refs))
+	for t, name := range b.refs {
+		refs[t.Name()] = name
+	}
+	return refs
+}
+
+// typeString spells a reflected type the way the DTO parser does, so the
+// slices and maps around a union are kept: []interface {} -> []interface{}.
+func typeString(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeString(t.Elem())
+	case reflect.Slice, reflect.Array:
+		return "[]" + typeString(t.Elem())
+	case reflect.Map:
+		return "map[" + typeString(t.Key()) + "]" + typeString(t.Elem())
+	case reflect.Interface:
+		return "interface{}"
+	}
+	return t.String()
+}
@@ -0,0 +1,460 @@
+package openapi
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestApplyEnumLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		property  map[string]interface{}
+		labelsTag string
+		want      map[string]interface{}
+	}{
+		{
+			name:      "labels aligned with oneof enum order",
+			property:  map[string]interface{}{"type": "string", "enum": []string{"inactive", "active"}},
+			labelsTag: "active=Active user,inactive=Disabled account",
+			want: map[string]interface{}{
+				"type":                "string",
+				"enum":                []string{"inactive", "active"},
+				"x-enum-descriptions": []string{"Disabled account", "Active user"},
+			},
+		},
+		{
+			name:      "labels without enum declare it",
+			property:  map[string]interface{}{"type": "string"},
+			labelsTag: "draft=Draft,published=Published",
+			want: map[string]interface{}{
+				"type":                "string",
+				"enum":                []string{"draft", "published"},
+				"x-enum-descriptions": []string{"Draft", "Published"},
+			},
+		},
+		{
+			name:      "missing label yields empty description",
+			property:  map[string]interface{}{"type": "string", "enum": []string{"a", "b"}},
+			labelsTag: "a=First",
+			want: map[string]interface{}{
+				"type":                "string",
+				"enum":                []string{"a", "b"},
+				"x-enum-descriptions": []string{"First", ""},
+			},
+		},
+		{
+			name:      "labels matched against typed enum values",
+			property:  map[string]interface{}{"type": "integer", "enum": []interface{}{1, 2}},
+			labelsTag: "2=High,1=Low",
+			want: map[string]interface{}{
+				"type":                "integer",
+				"enum":                []interface{}{1, 2},
+				"x-enum-descriptions": []string{"Low", "High"},
+			},
+		},
+		{
+			name:      "empty tag is a no-op",
+			property:  map[string]interface{}{"type": "string"},
+			labelsTag: "",
+			want:      map[string]interface{}{"type": "string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applyEnumLabels(tt.property, tt.labelsTag)
+			if !reflect.DeepEqual(tt.property, tt.want) {
+				t.Errorf("applyEnumLabels() = %v, want %v", tt.property, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSchemaFromModel_EnumLabels(t *testing.T) {
+	type order struct {
+		Status string `json:"status" validate:"oneof=pending shipped" enum_labels:"pending=Awaiting shipment,shipped=On its way"`
+	}
+
+	schema := buildSchemaFromModel(order{})
+	status := schema["properties"].(map[string]interface{})["status"].(map[string]interface{})
+
+	want := []string{"Awaiting shipment", "On its way"}
+	if !reflect.DeepEqual(status["x-enum-descriptions"], want) {
+		t.Errorf("x-enum-descriptions = %v, want %v", status["x-enum-descriptions"], want)
+	}
+}
+
+func TestBuildSchemaFromModel_NullableEnum(t *testing.T) {
+	type order struct {
+		Status *string `json:"status" validate:"oneof=pending shipped" enum_labels:"pending=Awaiting shipment,shipped=On its way"`
+		Kind   string  `json:"kind" validate:"oneof=retail wholesale"`
+	}
+
+	schema := buildSchemaFromModel(order{})
+	properties := schema["properties"].(map[string]interface{})
+
+	status := properties["status"].(map[string]interface{})
+	if status["nullable"] != true {
+		t.Errorf("status nullable = %v, want true", status["nullable"])
+	}
+	if want := []interface{}{"pending", "shipped", nil}; !reflect.DeepEqual(status["enum"], want) {
+		t.Errorf("status enum = %v, want %v", status["enum"], want)
+	}
+	if want := []string{"Awaiting shipment", "On its way", ""}; !reflect.DeepEqual(status["x-enum-descriptions"], want) {
+		t.Errorf("status x-enum-descriptions = %v, want %v", status["x-enum-descriptions"], want)
+	}
+
+	kind := properties["kind"].(map[string]interface{})
+	if want := []string{"retail", "wholesale"}; !reflect.DeepEqual(kind["enum"], want) {
+		t.Errorf("kind enum = %v, want %v", kind["enum"], want)
+	}
+}
+
+func TestBuildSchemaFromModel_NullableOnlyOnPointers(t *testing.T) {
+	type profile struct {
+		Name string  `json:"name"`
+		Bio  *string `json:"bio"`
+	}
+
+	schema := buildSchemaFromModel(profile{})
+	properties := schema["properties"].(map[string]interface{})
+
+	if want := map[string]interface{}{"type": "string"}; !reflect.DeepEqual(properties["name"], want) {
+		t.Errorf("name = %v, want %v", properties["name"], want)
+	}
+	if want := map[string]interface{}{"type": "string", "nullable": true}; !reflect.DeepEqual(properties["bio"], want) {
+		t.Errorf("bio = %v, want %v", properties["bio"], want)
+	}
+}
+
+func TestBuildSchemaFromModel_UniqueItems(t *testing.T) {
+	type article struct {
+		Tags  []string `json:"tags" validate:"unique"`
+		Notes []string `json:"notes"`
+		Slug  string   `json:"slug" validate:"unique"`
+	}
+
+	schema := buildSchemaFromModel(article{})
+	properties := schema["properties"].(map[string]interface{})
+
+	tests := []struct {
+		field string
+		want  bool
+	}{
+		{field: "tags", want: true},
+		{field: "notes", want: false},
+		{field: "slug", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			_, got := properties[tt.field].(map[string]interface{})["uniqueItems"]
+			if got != tt.want {
+				t.Errorf("%s uniqueItems present = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPropertySchema_NamedInterface(t *testing.T) {
+	type envelope struct {
+		Payload fmt.Stringer `json:"payload"`
+	}
+
+	schema := buildSchemaFromModel(envelope{})
+	payload := schema["properties"].(map[string]interface{})["payload"].(map[string]interface{})
+
+	if payload["type"] != "object" {
+		t.Errorf("named interface type = %v, want object", payload["type"])
+	}
+}
+
+func TestBuildSchemaFromModel_LibraryTypes(t *testing.T) {
+	type account struct {
+		ID      uuid.UUID       `json:"id"`
+		Timeout time.Duration   `json:"timeout"`
+		Payload json.RawMessage `json:"payload"`
+		Avatar  []byte          `json:"avatar"`
+		Nick    sql.NullString  `json:"nick"`
+	}
+
+	properties := buildSchemaFromModel(account{})["properties"].(map[string]interface{})
+	want := map[string]interface{}{
+		"id":      map[string]interface{}{"type": "string", "format": "uuid", "readOnly": true},
+		"timeout": map[string]interface{}{"type": "integer", "format": "int64"},
+		"payload": map[string]interface{}{"type": "object"},
+		"avatar":  map[string]interface{}{"type": "string", "format": "byte"},
+		"nick":    map[string]interface{}{"type": "string", "nullable": true},
+	}
+	for name, schema := range want {
+		if !reflect.DeepEqual(properties[name], schema) {
+			t.Errorf("%s = %v, want %v", name, properties[name], schema)
+		}
+	}
+}
+
+func TestBuildSchemaFromModel_XMLTag(t *testing.T) {
+	type book struct {
+		ISBN    string   `json:"isbn" xml:"isbn,attr"`
+		Authors []string `json:"authors" xml:"authors>author"`
+	}
+
+	properties := buildSchemaFromModel(book{})["properties"].(map[string]interface{})
+	if want := map[string]interface{}{"name": "isbn", "attribute": true}; !reflect.DeepEqual(properties["isbn"].(map[string]interface{})["xml"], want) {
+		t.Errorf("isbn xml = %v, want %v", properties["isbn"].(map[string]interface{})["xml"], want)
+	}
+	if want := map[string]interface{}{"name": "authors", "wrapped": true}; !reflect.DeepEqual(properties["authors"].(map[string]interface{})["xml"], want) {
+		t.Errorf("authors xml = %v, want %v", properties["authors"].(map[string]interface{})["xml"], want)
+	}
+}
+
+func TestBuildSchemaFromModel_UnionTag(t *testing.T) {
+	type card struct {
+		Number string `json:"number"`
+	}
+	type payment struct {
+		Method  interface{}   `json:"method" oneof_types:"card,string"`
+		Refunds []interface{} `json:"refunds" anyof_types:"card"`
+	}
+
+	refs := map[reflect.Type]string{reflect.TypeOf(card{}): "Card"}
+	properties := newModelSchemaBuilder(refs).build(payment{})["properties"].(map[string]interface{})
+
+	ref := map[string]interface{}{"$ref": "#/components/schemas/Card"}
+	wantMethod := map[string]interface{}{"oneOf": []interface{}{ref, map[string]interface{}{"type": "string"}}}
+	if !reflect.DeepEqual(properties["method"], wantMethod) {
+		t.Errorf("method = %v, want %v", properties["method"], wantMethod)
+	}
+	wantRefunds := map[string]interface{}{"type": "array", "items": map[string]interface{}{"anyOf": []interface{}{ref}}}
+	if !reflect.DeepEqual(properties["refunds"], wantRefunds) {
+		t.Errorf("refunds = %v, want %v", properties["refunds"], wantRefunds)
+	}
+}
+
+func TestBuildSchemaFromModel_SliceOfInlineStruct(t *testing.T) {
+	type order struct {
+		Lines []struct {
+			Qty int    `json:"qty"`
+			SKU string `json:"sku"`
+		} `json:"lines"`
+	}
+
+	schema := buildSchemaFromModel(order{})
+	lines := schema["properties"].(map[string]interface{})["lines"].(map[string]interface{})
+
+	if lines["type"] != "array" {
+		t.Fatalf("lines type = %v, want array", lines["type"])
+	}
+	items := lines["items"].(map[string]interface{})
+	properties, ok := items["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatal("lines items missing inline properties")
+	}
+	for _, name := range []string{"qty", "sku"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("lines items missing %q property", name)
+		}
+	}
+	if !reflect.DeepEqual(items["required"], []string{"qty", "sku"}) {
+		t.Errorf("lines items required = %v, want [qty sku]", items["required"])
+	}
+}
+
+func TestBuildSchemaFromModel_TypedMaps(t *testing.T) {
+	type inventory struct {
+		Stock    map[string]int64       `json:"stock"`
+		Metadata map[string]interface{} `json:"metadata"`
+	}
+
+	properties := buildSchemaFromModel(inventory{})["properties"].(map[string]interface{})
+
+	wantStock := map[string]interface{}{
+		"type":                 "object",
+		"additionalProperties": map[string]interface{}{"type": "integer", "format": "int64"},
+	}
+	if !reflect.DeepEqual(properties["stock"], wantStock) {
+		t.Errorf("stock = %v, want %v", properties["stock"], wantStock)
+	}
+	if want := map[string]interface{}{"type": "object"}; !reflect.DeepEqual(properties["metadata"], want) {
+		t.Errorf("metadata = %v, want %v", properties["metadata"], want)
+	}
+}
+
+func TestBuildSchemaFromModel_Embedded(t *testing.T) {
+	type Audit struct {
+		By string `json:"by"`
+	}
+	type Base struct {
+		ID    int64  `json:"id"`
+		Label string `json:"label"`
+	}
+	type order struct {
+		Base
+		*Audit `json:"audit"`
+		Label  int `json:"label"`
+	}
+
+	properties := buildSchemaFromModel(order{})["properties"].(map[string]interface{})
+
+	for _, name := range []string{"id", "label", "audit"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("missing property %q", name)
+		}
+	}
+	if _, ok := properties["by"]; ok {
+		t.Error("fields of a tagged embedded struct must not be promoted")
+	}
+	if properties["label"].(map[string]interface{})["type"] != "integer" {
+		t.Errorf("label = %v, want the enclosing struct's integer field", properties["label"])
+	}
+}
+
+type category struct {
+	Name     string     `json:"name"`
+	Parent   *category  `json:"parent"`
+	Children []category `json:"children"`
+}
+
+type node struct {
+	*node
+	Value int `json:"value"`
+}
+
+func TestModelSchemaBuilder_Recursive(t *testing.T) {
+	t.Run("without refs", func(t *testing.T) {
+		properties := buildSchemaFromModel(category{})["properties"].(map[string]interface{})
+		if properties["parent"].(map[string]interface{})["type"] != "object" {
+			t.Errorf("parent = %v, want an object", properties["parent"])
+		}
+	})
+
+	t.Run("with refs", func(t *testing.T) {
+		refs := map[reflect.Type]string{reflect.TypeOf(category{}): "Category"}
+		properties := newModelSchemaBuilder(refs).build(&category{})["properties"].(map[string]interface{})
+
+		ref := map[string]interface{}{"$ref": "#/components/schemas/Category"}
+		wantParent := map[string]interface{}{"allOf": []interface{}{ref}, "nullable": true}
+		if !reflect.DeepEqual(properties["parent"], wantParent) {
+			t.Errorf("parent = %v, want %v", properties["parent"], wantParent)
+		}
+		if items := properties["children"].(map[string]interface{})["items"]; !reflect.DeepEqual(items, ref) {
+			t.Errorf("children items = %v, want %v", items, ref)
+		}
+	})
+
+	t.Run("self embedding", func(t *testing.T) {
+		properties := buildSchemaFromModel(node{})["properties"].(map[string]interface{})
+		if len(properties) != 1 || properties["value"] == nil {
+			t.Errorf("properties = %v, want only value", properties)
+		}
+	})
+}
+
+func TestBuildSchemaFromModel_Description(t *testing.T) {
+	type user struct {
+		Email string `json:"email" description:"Sign-in email"`
+		Name  string `json:"name"`
+	}
+
+	properties := buildSchemaFromModel(user{})["properties"].(map[string]interface{})
+
+	if description := properties["email"].(map[string]interface{})["description"]; description != "Sign-in email" {
+		t.Errorf("email description = %v, want Sign-in email", description)
+	}
+	if _, ok := properties["name"].(map[string]interface{})["description"]; ok {
+		t.Error("name should not carry a description")
+	}
+}
+
+func TestBuildSchemaFromModel_AccessMode(t *testing.T) {
+	type user struct {
+		ID       int64  `json:"id"`
+		Password string `json:"password"`
+		Slug     string `json:"slug" openapi:"readonly"`
+		Name     string `json:"name"`
+	}
+
+	properties := buildSchemaFromModel(user{})["properties"].(map[string]interface{})
+
+	for _, name := range []string{"id", "slug"} {
+		if readOnly := properties[name].(map[string]interface{})["readOnly"]; readOnly != true {
+			t.Errorf("%s readOnly = %v, want true", name, readOnly)
+		}
+	}
+	if writeOnly := properties["password"].(map[string]interface{})["writeOnly"]; writeOnly != true {
+		t.Errorf("password writeOnly = %v, want true", writeOnly)
+	}
+	if _, ok := properties["name"].(map[string]interface{})["readOnly"]; ok {
+		t.Error("name should not be readOnly")
+	}
+}
+
+func TestBuildSchemaFromModel_Deprecated(t *testing.T) {
+	type user struct {
+		Nickname string `json:"nickname" deprecated:"true"`
+		Name     string `json:"name" deprecated:"false"`
+	}
+
+	properties := buildSchemaFromModel(user{})["properties"].(map[string]interface{})
+
+	if deprecated := properties["nickname"].(map[string]interface{})["deprecated"]; deprecated != true {
+		t.Errorf("nickname deprecated = %v, want true", deprecated)
+	}
+	if _, ok := properties["name"].(map[string]interface{})["deprecated"]; ok {
+		t.Error("name should not be deprecated")
+	}
+}
+
+func TestBuildSchemaFromModel_Default(t *testing.T) {
+	type settings struct {
+		PageSize int     `json:"pageSize" default:"20"`
+		Ratio    float64 `json:"ratio" default:"0.5"`
+		Enabled  *bool   `json:"enabled" default:"true"`
+		Theme    string  `json:"theme" default:"dark"`
+	}
+
+	properties := buildSchemaFromModel(settings{})["properties"].(map[string]interface{})
+
+	want := map[string]interface{}{"pageSize": int64(20), "ratio": 0.5, "enabled": true, "theme": "dark"}
+	for name, value := range want {
+		if got := properties[name].(map[string]interface{})["default"]; got != value {
+			t.Errorf("%s default = %#v, want %#v", name, got, value)
+		}
+	}
+}
+
+func TestBuildSchemaFromModel_TypeOverride(t *testing.T) {
+	type cents struct{ Value int64 }
+	type order struct {
+		Reference [16]byte `json:"reference" openapi:"type=string,format=uuid"`
+		Total     cents    `json:"total" openapi:"type=integer,format=int64"`
+	}
+
+	properties := buildSchemaFromModel(order{})["properties"].(map[string]interface{})
+
+	if want := map[string]interface{}{"type": "string", "format": "uuid"}; !reflect.DeepEqual(properties["reference"], want) {
+		t.Errorf("reference = %v, want %v", properties["reference"], want)
+	}
+	if want := map[string]interface{}{"type": "integer", "format": "int64"}; !reflect.DeepEqual(properties["total"], want) {
+		t.Errorf("total = %v, want %v", properties["total"], want)
+	}
+}
+
+func TestBuildSchemaFromModel_Unit(t *testing.T) {
+	type product struct {
+		PriceCents int `json:"priceCents" openapi:"unit=cents"`
+	}
+
+	schema := buildSchemaFromModel(product{})
+	price := schema["properties"].(map[string]interface{})["priceCents"].(map[string]interface{})
+
+	if price["x-unit"] != "cents" {
+		t.Errorf("priceCents x-unit = %v, want cents", price["x-unit"])
+	}
+}
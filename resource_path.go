@@ -0,0 +1,66 @@
+package openapi
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Path segment styles supported by GeneratorConfig.PathStyle. When empty,
+// the DTO file name is used as is (order_item.go -> /order_items).
+const (
+	PathStyleKebabCase = "kebab-case"
+	PathStyleSnakeCase = "snake_case"
+)
+
+// resourcePath returns the collection path of a DTO resource, before the
+// base path: the ResourcePaths override when set, otherwise its plural (or
+// singular with SingularPaths) name in the configured PathStyle.
+func resourcePath(resource resourceDTOs, cfg GeneratorConfig) string {
+	if path, ok := cfg.ResourcePaths[resource.Name]; ok {
+		return "/" + strings.Trim(path, "/")
+	}
+
+	segment := resource.PluralName
+	if cfg.SingularPaths {
+		segment = resource.Name
+	}
+	switch cfg.PathStyle {
+	case PathStyleKebabCase:
+		segment = strings.Join(nameWords(segment), "-")
+	case PathStyleSnakeCase:
+		segment = strings.Join(nameWords(segment), "_")
+	}
+	return "/" + segment
+}
+
+// nameWords splits a name on _, - and camelCase boundaries into lowercase
+// words: orderItems -> order, items.
+func nameWords(name string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '_' || r == '-' {
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+			continue
+		}
+		// A word starts at an upper case letter following a lower case one,
+		// or ending an acronym (HTTPServer -> http, server)
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				words = append(words, string(current))
+				current = nil
+			}
+		}
+		current = append(current, unicode.ToLower(r))
+	}
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+	return words
+}
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResourcePath(t *testing.T) {
+	resource := resourceDTOs{Name: "order_item", PluralName: "order_items"}
+	camel := resourceDTOs{Name: "orderItem", PluralName: "orderItems"}
+
+	tests := []struct {
+		name     string
+		resource resourceDTOs
+		cfg      GeneratorConfig
+		want     string
+	}{
+		{name: "file name as is", resource: resource, want: "/order_items"},
+		{name: "kebab-case", resource: resource, cfg: GeneratorConfig{PathStyle: PathStyleKebabCase}, want: "/order-items"},
+		{name: "kebab-case from camelCase", resource: camel, cfg: GeneratorConfig{PathStyle: PathStyleKebabCase}, want: "/order-items"},
+		{name: "snake_case from camelCase", resource: camel, cfg: GeneratorConfig{PathStyle: PathStyleSnakeCase}, want: "/order_items"},
+		{name: "singular", resource: resource, cfg: GeneratorConfig{PathStyle: PathStyleKebabCase, SingularPaths: true}, want: "/order-item"},
+		{
+			name:     "explicit override",
+			resource: resource,
+			cfg:      GeneratorConfig{PathStyle: PathStyleSnakeCase, ResourcePaths: map[string]string{"order_item": "shop/lines/"}},
+			want:     "/shop/lines",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resourcePath(tt.resource, tt.cfg); got != tt.want {
+				t.Errorf("resourcePath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameWords(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{name: "users", want: []string{"users"}},
+		{name: "order_items", want: []string{"order", "items"}},
+		{name: "order-items", want: []string{"order", "items"}},
+		{name: "orderItems", want: []string{"order", "items"}},
+		{name: "HTTPServers", want: []string{"http", "servers"}},
+		{name: "v2Keys", want: []string{"v2", "keys"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nameWords(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("nameWords(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/nicolasbonnici/gorest/plugin"
+)
+
+// ResourceOption refines a resource registered with RegisterResource.
+type ResourceOption func(*plugin.OpenAPIResource)
+
+// WithCreateModel documents POST requests with the schema of model.
+func WithCreateModel(model interface{}) ResourceOption {
+	return func(r *plugin.OpenAPIResource) { r.CreateModel = model }
+}
+
+// WithUpdateModel documents PUT requests with the schema of model.
+func WithUpdateModel(model interface{}) ResourceOption {
+	return func(r *plugin.OpenAPIResource) { r.UpdateModel = model }
+}
+
+// WithPluralName replaces the generated plural of the resource name.
+func WithPluralName(plural string) ResourceOption {
+	return func(r *plugin.OpenAPIResource) { r.PluralName = plural }
+}
+
+// WithBasePath serves the resource under basePath instead of /{plural}.
+func WithBasePath(basePath string) ResourceOption {
+	return func(r *plugin.OpenAPIResource) { r.BasePath = basePath }
+}
+
+// WithTags tags the resource operations instead of the resource name.
+func WithTags(tags ...string) ResourceOption {
+	return func(r *plugin.OpenAPIResource) { r.Tags = tags }
+}
+
+// WithDescription describes the resource operations.
+func WithDescription(description string) ResourceOption {
+	return func(r *plugin.OpenAPIResource) { r.Description = description }
+}
+
+// WithListQueryParams documents extra query parameters on the collection GET.
+func WithListQueryParams(params ...plugin.QueryParam) ResourceOption {
+	return func(r *plugin.OpenAPIResource) { r.ListQueryParams = params }
+}
+
+// RegisterResource documents a resource whose schema is built by reflection
+// from model, a struct or pointer to struct, without parsing DTO files: the
+// way to document deployments shipping without their sources. Registered
+// resources are documented alongside those of the plugin registry, and
+// replace the DTOs directory. Registering a name twice fails. The cached spec
+// is regenerated.
+func (p *OpenAPIPlugin) RegisterResource(name string, model interface{}, opts ...ResourceOption) error {
+	if name == "" {
+		return errors.New("resource name is required")
+	}
+	if modelType(model) == nil {
+		return fmt.Errorf("resource %q: model must be a struct or a pointer to a struct, got %T", name, model)
+	}
+
+	resource := plugin.OpenAPIResource{Name: name, ResponseModel: model}
+	for _, opt := range opts {
+		opt(&resource)
+	}
+
+	p.resourcesMu.Lock()
+	registered := slices.ContainsFunc(p.resources, func(r plugin.OpenAPIResource) bool { return r.Name == name })
+	if !registered {
+		p.resources = append(p.resources, resource)
+	}
+	p.resourcesMu.Unlock()
+	if registered {
+		return fmt.Errorf("resource %q is already registered", name)
+	}
+
+	p.Invalidate()
+	return nil
+}
+
+// registeredResources returns the resources registered with RegisterResource,
+// with their plural name and base path defaulted from the resource name.
+func (p *OpenAPIPlugin) registeredResources() []plugin.OpenAPIResource {
+	p.resourcesMu.RLock()
+	resources := slices.Clone(p.resources)
+	p.resourcesMu.RUnlock()
+
+	for i := range resources {
+		resource := &resources[i]
+		if resource.PluralName == "" {
+			resource.PluralName = pluralize(resource.Name, p.opts.PluralOverrides)
+		}
+		if resource.BasePath == "" {
+			resource.BasePath = "/" + resource.PluralName
+		}
+	}
+	return resources
+}
@@ -0,0 +1,136 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/nicolasbonnici/gorest/plugin"
+)
+
+type registeredInvoice struct {
+	ID    string `json:"id"`
+	Total int    `json:"total"`
+}
+
+type createInvoiceRequest struct {
+	Total int `json:"total"`
+}
+
+func TestRegisterResource(t *testing.T) {
+	tests := []struct {
+		name      string
+		resource  string
+		model     interface{}
+		opts      []ResourceOption
+		overrides map[string]string
+		want      plugin.OpenAPIResource
+		wantErr   bool
+	}{
+		{
+			name:     "defaults from the name",
+			resource: "invoice",
+			model:    registeredInvoice{},
+			want:     plugin.OpenAPIResource{Name: "invoice", PluralName: "invoices", BasePath: "/invoices", ResponseModel: registeredInvoice{}},
+		},
+		{
+			name:      "plural override",
+			resource:  "person",
+			model:     &registeredInvoice{},
+			overrides: map[string]string{"person": "persons"},
+			want:      plugin.OpenAPIResource{Name: "person", PluralName: "persons", BasePath: "/persons", ResponseModel: &registeredInvoice{}},
+		},
+		{
+			name:     "options",
+			resource: "invoice",
+			model:    registeredInvoice{},
+			opts: []ResourceOption{
+				WithCreateModel(createInvoiceRequest{}),
+				WithUpdateModel(createInvoiceRequest{}),
+				WithPluralName("bills"),
+				WithBasePath("/billing/invoices"),
+				WithTags("Billing"),
+				WithDescription("Issued invoices"),
+				WithListQueryParams(plugin.QueryParam{Name: "paid", Type: "boolean"}),
+			},
+			want: plugin.OpenAPIResource{
+				Name:            "invoice",
+				PluralName:      "bills",
+				BasePath:        "/billing/invoices",
+				Tags:            []string{"Billing"},
+				ResponseModel:   registeredInvoice{},
+				CreateModel:     createInvoiceRequest{},
+				UpdateModel:     createInvoiceRequest{},
+				Description:     "Issued invoices",
+				ListQueryParams: []plugin.QueryParam{{Name: "paid", Type: "boolean"}},
+			},
+		},
+		{name: "empty name", model: registeredInvoice{}, wantErr: true},
+		{name: "non-struct model", resource: "invoice", model: "invoice", wantErr: true},
+		{name: "nil model", resource: "invoice", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &OpenAPIPlugin{opts: Options{PluralOverrides: tt.overrides}}
+			err := p.RegisterResource(tt.resource, tt.model, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RegisterResource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			resources := p.registeredResources()
+			if tt.wantErr {
+				if len(resources) != 0 {
+					t.Errorf("registeredResources() = %v, want none after an error", resources)
+				}
+				return
+			}
+			if len(resources) != 1 || !reflect.DeepEqual(resources[0], tt.want) {
+				t.Errorf("registeredResources() = %+v, want [%+v]", resources, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterResource_Spec(t *testing.T) {
+	p := NewPlugin().(*OpenAPIPlugin)
+	if err := p.Initialize(map[string]interface{}{}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if err := p.RegisterResource("invoice", registeredInvoice{}, WithCreateModel(createInvoiceRequest{})); err != nil {
+		t.Fatalf("RegisterResource() error = %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(fiber.New(), p.generatorConfig())
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for _, name := range []string{"Invoice", "CreateInvoiceRequest"} {
+		if _, ok := schemas[name]; !ok {
+			t.Errorf("schemas = %v, want %s", sortedKeys(schemas), name)
+		}
+	}
+	paths := spec["paths"].(map[string]interface{})
+	for _, path := range []string{"/invoices", "/invoices/{id}"} {
+		if _, ok := paths[path]; !ok {
+			t.Errorf("paths = %v, want %s", sortedKeys(paths), path)
+		}
+	}
+}
+
+func TestRegisterResource_Duplicate(t *testing.T) {
+	p := &OpenAPIPlugin{}
+	if err := p.RegisterResource("invoice", registeredInvoice{}); err != nil {
+		t.Fatalf("RegisterResource() error = %v", err)
+	}
+	err := p.RegisterResource("invoice", createInvoiceRequest{})
+	if err == nil || !strings.Contains(err.Error(), `resource "invoice" is already registered`) {
+		t.Fatalf("RegisterResource() error = %v, want the duplicate reported", err)
+	}
+	if resources := p.registeredResources(); len(resources) != 1 || resources[0].ResponseModel != (registeredInvoice{}) {
+		t.Errorf("registeredResources() = %+v, want the first registration only", resources)
+	}
+}
@@ -1,36 +1,222 @@
 package openapi
 
 import (
-	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v3"
 )
 
-func discoverNonResourceRoutes(app *fiber.App, resourcePaths map[string]bool) map[string]map[string]interface{} {
-	routes := app.GetRoutes(true)
+// Default paths of the documentation endpoints.
+const (
+	defaultDocsPath = "/openapi"
+	defaultSpecPath = "/openapi.json"
+)
+
+// docPaths holds the routes the plugin serves its documentation on.
+type docPaths struct {
+	UI    string
+	JSON  string
+	YAML  string
+	Index string
+	// Internal serves the internal spec, when configured.
+	Internal string
+}
+
+// resolveDocPaths derives every documentation route from the configured UI
+// and JSON spec paths: the YAML spec sits next to the JSON one (spec.json ->
+// spec.yaml) and the resource index below the UI.
+func resolveDocPaths(docsPath, specPath string) docPaths {
+	if docsPath == "" {
+		docsPath = defaultDocsPath
+	}
+	if specPath == "" {
+		specPath = defaultSpecPath
+	}
+
+	return docPaths{
+		UI:    docsPath,
+		JSON:  specPath,
+		YAML:  strings.TrimSuffix(specPath, ".json") + ".yaml",
+		Index: strings.TrimSuffix(docsPath, "/") + "/index",
+	}
+}
+
+func (d docPaths) contains(path string) bool {
+	if d.Internal != "" && path == d.Internal {
+		return true
+	}
+	return path == d.UI || path == d.JSON || path == d.YAML || path == d.Index
+}
+
+func discoverNonResourceRoutes(app *fiber.App, resourcePaths map[string]bool, cfg GeneratorConfig) map[string]map[string]interface{} {
+	routes := appRoutes(app, cfg.MountedApps)
+	docs := resolveDocPaths(cfg.DocsPath, cfg.SpecPath)
+	docs.Internal = cfg.InternalSpecPath
+	// Options.Validate reports invalid patterns; a filter left without them
+	// documents every route
+	filter, _ := newRouteFilter(cfg.IncludeRoutes, cfg.ExcludeRoutes)
 	discovered := make(map[string]map[string]interface{})
 
 	for _, route := range routes {
-		path := route.Path
 		method := strings.ToUpper(route.Method)
+		if !discoversMethod(cfg.DiscoverMethods, method) {
+			continue
+		}
+
+		for _, variant := range routePathVariants(route.Path) {
+			path := stripParamConstraints(variant)
+			documented := openAPIPath(path)
+			if shouldSkipRoute(path, resourcePaths, docs) || !filter.allows(documented) {
+				continue
+			}
+
+			if discovered[documented] == nil {
+				discovered[documented] = make(map[string]interface{})
+			}
+			// A route registered with the variant's path documents it
+			// better than an optional parameter left out
+			if _, ok := discovered[documented][strings.ToLower(method)]; ok && variant != route.Path {
+				continue
+			}
+
+			discovered[documented][strings.ToLower(method)] = generateRouteSpec(variant, method, cfg)
+		}
+	}
+
+	return discovered
+}
+
+// RegisterMountedApps documents the routes of apps mounted on the app
+// (app.Use("/billing", billing)) under their mount path. Fiber only adds
+// them to the app's routes when the server starts, so without registration
+// a spec generated before, such as the output_file one, misses them. Nested
+// mounted apps need registering too. The cached spec is regenerated, and
+// again once apps registered before being mounted are.
+func (p *OpenAPIPlugin) RegisterMountedApps(apps ...*fiber.App) {
+	for _, app := range apps {
+		if app.MountPath() == "" {
+			app.Hooks().OnMount(func(*fiber.App) error {
+				p.Invalidate()
+				return nil
+			})
+		}
+	}
+
+	p.mountedAppsMu.Lock()
+	p.mountedApps = append(p.mountedApps, apps...)
+	p.mountedAppsMu.Unlock()
+
+	p.Invalidate()
+}
+
+func (p *OpenAPIPlugin) registeredMountedApps() []*fiber.App {
+	p.mountedAppsMu.RLock()
+	defer p.mountedAppsMu.RUnlock()
+	return slices.Clone(p.mountedApps)
+}
 
-		if shouldSkipRoute(path, resourcePaths) {
+// appRoutes returns the routes of app along with those of the mounted apps,
+// prefixed with their mount path. Fiber only copies the routes of mounted
+// apps into the parent when the server starts, so a spec generated earlier
+// would miss them. Routes present on both are listed once, and apps not
+// mounted yet are left out.
+func appRoutes(app *fiber.App, mounted []*fiber.App) []fiber.Route {
+	routes := app.GetRoutes(true)
+	seen := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		seen[route.Method+" "+route.Path] = true
+	}
+
+	for _, subApp := range mounted {
+		mountPath := subApp.MountPath()
+		if mountPath == "" {
 			continue
 		}
+		// an app mounted on / adds its routes as they are
+		prefix := strings.TrimSuffix(mountPath, "/")
+		for _, route := range subApp.GetRoutes(true) {
+			// Joined the way fiber copies it: / of an app mounted on /billing
+			// is /billing/
+			route.Path = prefix + route.Path
+			if key := route.Method + " " + route.Path; !seen[key] {
+				seen[key] = true
+				routes = append(routes, route)
+			}
+		}
+	}
+	return routes
+}
 
-		if discovered[path] == nil {
-			discovered[path] = make(map[string]interface{})
+// openAPIMethods are the operations an OpenAPI 3.0 or 3.1 path item holds.
+// Routes of other methods, like the CONNECT and QUERY routes app.All
+// registers, cannot be documented.
+var openAPIMethods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH", "TRACE"}
+
+// discoversMethod reports whether discovered routes of method are
+// documented: those OpenAPI can express, unless methods turns them off,
+// matching method names case-insensitively.
+func discoversMethod(methods map[string]bool, method string) bool {
+	if !slices.Contains(openAPIMethods, strings.ToUpper(method)) {
+		return false
+	}
+	for _, name := range sortedKeys(methods) {
+		if strings.EqualFold(name, method) {
+			return methods[name]
 		}
+	}
+	return true
+}
 
-		discovered[path][strings.ToLower(method)] = generateRouteSpec(path, method)
+// routePathVariants returns the documented paths of a fiber route path.
+// OpenAPI has no optional path parameters, so each optional parameter (:id?)
+// yields the path with and without its segment, and wildcard segments (*,
+// +, *1...) become a path parameter: /files/* -> /files/:path.
+func routePathVariants(path string) []string {
+	if !strings.ContainsAny(path, "?*+") {
+		return []string{path}
 	}
 
-	return discovered
+	variants := []string{""}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		segment = wildcardParam(segment)
+		optional := strings.HasPrefix(segment, ":") && strings.HasSuffix(segment, "?")
+		segment = strings.TrimSuffix(segment, "?")
+
+		next := make([]string, 0, 2*len(variants))
+		for _, variant := range variants {
+			next = append(next, variant+"/"+segment)
+			if optional {
+				next = append(next, variant)
+			}
+		}
+		variants = next
+	}
+
+	for i, variant := range variants {
+		if variant == "" {
+			variants[i] = "/"
+		}
+	}
+	return variants
 }
 
-func shouldSkipRoute(path string, resourcePaths map[string]bool) bool {
-	if path == "/openapi" || path == "/openapi.json" {
+// wildcardParam names a wildcard segment (* or +, numbered when a route has
+// several: *1, +2) as the path parameter it captures: :path, :path1...
+func wildcardParam(segment string) string {
+	if segment == "" || (segment[0] != '*' && segment[0] != '+') {
+		return segment
+	}
+	number := segment[1:]
+	if strings.Trim(number, "0123456789") != "" {
+		return segment
+	}
+	return ":path" + number
+}
+
+func shouldSkipRoute(path string, resourcePaths map[string]bool, docs docPaths) bool {
+	if docs.contains(path) {
 		return true
 	}
 
@@ -45,10 +231,46 @@ func shouldSkipRoute(path string, resourcePaths map[string]bool) bool {
 	return false
 }
 
-func generateRouteSpec(path, method string) map[string]interface{} {
-	tag := determineTag(path)
-	summary := generateSummary(path, method)
-	description := generateDescription(path, method)
+// joinBasePath prefixes path with basePath, unless it already starts with it.
+func joinBasePath(basePath, path string) string {
+	basePath = strings.TrimSuffix(basePath, "/")
+	if basePath == "" {
+		return path
+	}
+	if !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+	if path == basePath || strings.HasPrefix(path, basePath+"/") {
+		return path
+	}
+	return basePath + path
+}
+
+// stripBasePath removes the basePath prefix from path, when present.
+func stripBasePath(basePath, path string) string {
+	basePath = "/" + strings.Trim(basePath, "/")
+	if basePath == "/" {
+		return path
+	}
+	if path == basePath {
+		return "/"
+	}
+	if rest, ok := strings.CutPrefix(path, basePath+"/"); ok {
+		return "/" + rest
+	}
+	return path
+}
+
+// generateRouteSpec documents a discovered route from its Fiber path, which
+// may hold parameter constraints (:id<int>) documented in the parameter
+// schemas. Its texts use the OpenAPI path (/users/{id}).
+func generateRouteSpec(path, method string, cfg GeneratorConfig) map[string]interface{} {
+	constrained := path
+	path = openAPIPath(stripParamConstraints(path))
+	relative := stripBasePath(cfg.BasePath, path)
+	tag := routeTag(path, relative, cfg.GroupTags)
+	summary := generateSummary(relative, method, cfg)
+	description := generateDescription(path, method, cfg)
 
 	spec := map[string]interface{}{
 		"summary":     summary,
@@ -56,19 +278,45 @@ func generateRouteSpec(path, method string) map[string]interface{} {
 		"tags":        []string{tag},
 	}
 
-	if strings.Contains(path, ":") {
-		spec["parameters"] = extractPathParameters(path)
+	if strings.Contains(constrained, ":") {
+		spec["parameters"] = extractPathParameters(constrained, cfg)
 	}
 
 	if method == "POST" || method == "PUT" || method == "PATCH" {
 		spec["requestBody"] = generateRequestBody()
 	}
 
-	spec["responses"] = generateResponses(method)
+	responses := generateResponses(method)
+	if method == "GET" && tag == "System" {
+		responses["200"] = healthResponse(cfg)
+	}
+	spec["responses"] = responses
 
 	return spec
 }
 
+// routeTag tags a discovered route by the route group of groupTags with the
+// longest prefix of path, and otherwise by the first segment of its path
+// relative to the base path.
+func routeTag(path, relative string, groupTags map[string]string) string {
+	var group, tag string
+	for prefix, groupTag := range groupTags {
+		prefix = "/" + strings.Trim(prefix, "/")
+		if (path == prefix || strings.HasPrefix(path, prefix+"/")) && len(prefix) > len(group) {
+			group, tag = prefix, groupTag
+		}
+	}
+
+	switch {
+	case group == "":
+		return determineTag(relative)
+	case tag != "":
+		return tag
+	default:
+		return determineTag(strings.TrimPrefix(path, group))
+	}
+}
+
 func determineTag(path string) string {
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	if len(parts) == 0 || parts[0] == "" {
@@ -87,48 +335,42 @@ func determineTag(path string) string {
 	}
 }
 
-func generateSummary(path, method string) string {
-	parts := strings.Split(strings.Trim(path, "/"), "/")
-	action := ""
-
-	switch method {
-	case "GET":
-		action = "Get"
-	case "POST":
-		action = "Create or execute"
-	case "PUT":
-		action = "Update"
-	case "PATCH":
-		action = "Partially update"
-	case "DELETE":
-		action = "Delete"
-	default:
-		action = method
-	}
+func generateSummary(path, method string, cfg GeneratorConfig) string {
+	return routeTexts(cfg, path, method).summary(OperationTextRoute)
+}
 
-	pathName := strings.Join(parts, " ")
-	pathName = strings.ReplaceAll(pathName, ":", "")
+func generateDescription(path, method string, cfg GeneratorConfig) string {
+	return routeTexts(cfg, path, method).description(OperationTextRoute)
+}
 
-	return fmt.Sprintf("%s %s", action, pathName)
+// routeAction names what a request with method does, for route summaries.
+// Methods without a message are named as is.
+func routeAction(method string, cfg GeneratorConfig) string {
+	if action := message(cfg, messageRouteAction+method); action != "" {
+		return action
+	}
+	return method
 }
 
-func generateDescription(path, method string) string {
-	return fmt.Sprintf("%s %s", method, path)
+// routeWords spells a path as words: /users/:id or /users/{id} -> "users id".
+func routeWords(path string) string {
+	words := strings.Join(strings.Split(strings.Trim(path, "/"), "/"), " ")
+	return strings.NewReplacer(":", "", "{", "", "}", "").Replace(words)
 }
 
-func extractPathParameters(path string) []map[string]interface{} {
+func extractPathParameters(path string, cfg GeneratorConfig) []map[string]interface{} {
 	var params []map[string]interface{}
 
 	parts := strings.Split(path, "/")
 	for _, part := range parts {
 		if strings.HasPrefix(part, ":") {
-			paramName := strings.TrimPrefix(part, ":")
+			paramName, constraints := splitParamConstraints(strings.TrimPrefix(part, ":"))
 			params = append(params, map[string]interface{}{
 				"name":        paramName,
 				"in":          "path",
 				"required":    true,
-				"description": fmt.Sprintf("Path parameter: %s", paramName),
-				"schema":      map[string]string{"type": "string"},
+				"description": strings.ReplaceAll(message(cfg, messagePathParam), "{name}", paramName),
+				"schema":      paramConstraintSchema(constraints),
 			})
 		}
 	}
@@ -136,6 +378,138 @@ func extractPathParameters(path string) []map[string]interface{} {
 	return params
 }
 
+// splitParamConstraints splits a route parameter into its name and fiber
+// constraints: id<int;min(1)> -> id, int;min(1).
+func splitParamConstraints(param string) (name, constraints string) {
+	name, constraints, ok := strings.Cut(param, "<")
+	if !ok {
+		return param, ""
+	}
+	return name, strings.TrimSuffix(constraints, ">")
+}
+
+// stripParamConstraints removes the constraints of the parameters of a route
+// path, keeping optional markers: /users/:id<int>? -> /users/:id?.
+func stripParamConstraints(path string) string {
+	if !strings.Contains(path, "<") {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		optional := strings.HasSuffix(segment, "?")
+		name, _ := splitParamConstraints(strings.TrimSuffix(segment, "?"))
+		segments[i] = name
+		if optional {
+			segments[i] += "?"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// paramConstraintSchema documents the fiber constraints of a route parameter
+// (int, bool, float, guid, alpha, minLen(n), maxLen(n), len(n),
+// betweenLen(a,b), min(n), max(n), range(a,b), regex(expr)) as its schema,
+// a string without constraints. Unknown constraints are ignored.
+func paramConstraintSchema(constraints string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "string"}
+	if constraints == "" {
+		return schema
+	}
+
+	for _, constraint := range splitConstraints(constraints) {
+		name, args, _ := strings.Cut(constraint, "(")
+		args = strings.TrimSuffix(args, ")")
+		switch name {
+		case "int":
+			schema["type"] = "integer"
+		case "bool":
+			schema["type"] = "boolean"
+		case "float":
+			schema["type"] = "number"
+		case "guid":
+			schema["format"] = "uuid"
+		case "alpha":
+			schema["pattern"] = "^[a-zA-Z]+$"
+		case "regex":
+			schema["pattern"] = args
+		case "minLen", "maxLen", "len", "betweenLen":
+			applyLengthConstraint(schema, name, args)
+		case "min", "max", "range":
+			schema["type"] = "integer"
+			applyRangeConstraint(schema, name, args)
+		}
+	}
+	return schema
+}
+
+// splitConstraints splits the constraints of a route parameter on the
+// semicolons separating them, leaving those escaped (\;) or within
+// arguments alone: int;regex(^a;b$) -> int, regex(^a;b$).
+func splitConstraints(constraints string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(constraints); i++ {
+		switch constraints[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth = max(depth-1, 0)
+		case ';':
+			if depth == 0 {
+				parts = append(parts, constraints[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, constraints[start:])
+}
+
+func applyLengthConstraint(schema map[string]interface{}, name, args string) {
+	bounds := constraintInts(args)
+	switch {
+	case name == "minLen" && len(bounds) == 1:
+		schema["minLength"] = bounds[0]
+	case name == "maxLen" && len(bounds) == 1:
+		schema["maxLength"] = bounds[0]
+	case name == "len" && len(bounds) == 1:
+		schema["minLength"], schema["maxLength"] = bounds[0], bounds[0]
+	case name == "betweenLen" && len(bounds) == 2:
+		schema["minLength"], schema["maxLength"] = bounds[0], bounds[1]
+	}
+}
+
+func applyRangeConstraint(schema map[string]interface{}, name, args string) {
+	bounds := constraintInts(args)
+	switch {
+	case name == "min" && len(bounds) == 1:
+		schema["minimum"] = bounds[0]
+	case name == "max" && len(bounds) == 1:
+		schema["maximum"] = bounds[0]
+	case name == "range" && len(bounds) == 2:
+		schema["minimum"], schema["maximum"] = bounds[0], bounds[1]
+	}
+}
+
+// constraintInts parses the comma-separated integer arguments of a
+// constraint, nil when one is not an integer.
+func constraintInts(args string) []int {
+	var values []int
+	for _, arg := range strings.Split(args, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(arg))
+		if err != nil {
+			return nil
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
 func generateRequestBody() map[string]interface{} {
 	return map[string]interface{}{
 		"required": true,
@@ -149,6 +523,39 @@ func generateRequestBody() map[string]interface{} {
 	}
 }
 
+// healthResponse documents the structured payload returned by health checks
+// ({"status":"ok","checks":{...}}). GeneratorConfig.HealthSchema replaces the
+// built-in schema for apps whose health endpoint reports something else.
+func healthResponse(cfg GeneratorConfig) map[string]interface{} {
+	schema := cfg.HealthSchema
+	if schema == nil {
+		schema = defaultHealthSchema()
+	}
+
+	return map[string]interface{}{
+		"description": "Service health status",
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"schema": schema,
+			},
+		},
+	}
+}
+
+func defaultHealthSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"status": map[string]interface{}{"type": "string", "example": "ok"},
+			"checks": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+		},
+		"required": []string{"status"},
+	}
+}
+
 func generateResponses(method string) map[string]interface{} {
 	responses := map[string]interface{}{}
 
@@ -1,7 +1,10 @@
 package openapi
 
 import (
+	"net/http/httptest"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/gofiber/fiber/v3"
@@ -26,6 +29,18 @@ func TestShouldSkipRoute(t *testing.T) {
 			resourcePaths: map[string]bool{},
 			want:          true,
 		},
+		{
+			name:          "skip OpenAPI YAML route",
+			path:          "/openapi.yaml",
+			resourcePaths: map[string]bool{},
+			want:          true,
+		},
+		{
+			name:          "skip resource index route",
+			path:          "/openapi/index",
+			resourcePaths: map[string]bool{},
+			want:          true,
+		},
 		{
 			name:          "skip resource paths",
 			path:          "/users",
@@ -60,13 +75,117 @@ func TestShouldSkipRoute(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := shouldSkipRoute(tt.path, tt.resourcePaths); got != tt.want {
+			if got := shouldSkipRoute(tt.path, tt.resourcePaths, resolveDocPaths("", "")); got != tt.want {
 				t.Errorf("shouldSkipRoute(%q, %v) = %v, want %v", tt.path, tt.resourcePaths, got, tt.want)
 			}
 		})
 	}
 }
 
+func TestResolveDocPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		docsPath string
+		specPath string
+		want     docPaths
+	}{
+		{
+			name: "defaults",
+			want: docPaths{UI: "/openapi", JSON: "/openapi.json", YAML: "/openapi.yaml", Index: "/openapi/index"},
+		},
+		{
+			name:     "custom paths",
+			docsPath: "/docs",
+			specPath: "/docs/spec.json",
+			want:     docPaths{UI: "/docs", JSON: "/docs/spec.json", YAML: "/docs/spec.yaml", Index: "/docs/index"},
+		},
+		{
+			name:     "spec path without extension",
+			docsPath: "/docs/",
+			specPath: "/docs/spec",
+			want:     docPaths{UI: "/docs/", JSON: "/docs/spec", YAML: "/docs/spec.yaml", Index: "/docs/index"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveDocPaths(tt.docsPath, tt.specPath); got != tt.want {
+				t.Errorf("resolveDocPaths(%q, %q) = %+v, want %+v", tt.docsPath, tt.specPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldSkipRoute_ConfiguredDocPaths(t *testing.T) {
+	docs := resolveDocPaths("/docs", "/docs/spec.json")
+	for _, path := range []string{"/docs", "/docs/spec.json", "/docs/spec.yaml", "/docs/index"} {
+		if !shouldSkipRoute(path, map[string]bool{}, docs) {
+			t.Errorf("shouldSkipRoute(%q) = false, want true", path)
+		}
+	}
+	if shouldSkipRoute("/openapi.json", map[string]bool{}, docs) {
+		t.Error("default spec path should be documented once the spec path is moved")
+	}
+}
+
+func TestJoinBasePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		basePath string
+		path     string
+		want     string
+	}{
+		{name: "no base path", basePath: "", path: "/users", want: "/users"},
+		{name: "prefixed", basePath: "/api/v1", path: "/users", want: "/api/v1/users"},
+		{name: "trailing slash", basePath: "/api/v1/", path: "/users", want: "/api/v1/users"},
+		{name: "missing leading slash", basePath: "api", path: "/users", want: "/api/users"},
+		{name: "already prefixed", basePath: "/api/v1", path: "/api/v1/users", want: "/api/v1/users"},
+		{name: "shared prefix only", basePath: "/api", path: "/apiary", want: "/api/apiary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := joinBasePath(tt.basePath, tt.path); got != tt.want {
+				t.Errorf("joinBasePath(%q, %q) = %q, want %q", tt.basePath, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripBasePath(t *testing.T) {
+	tests := []struct {
+		name     string
+		basePath string
+		path     string
+		want     string
+	}{
+		{name: "no base path", basePath: "", path: "/api/v1/auth/login", want: "/api/v1/auth/login"},
+		{name: "stripped", basePath: "/api/v1", path: "/api/v1/auth/login", want: "/auth/login"},
+		{name: "base path itself", basePath: "/api/v1/", path: "/api/v1", want: "/"},
+		{name: "outside base path", basePath: "/api/v1", path: "/health", want: "/health"},
+		{name: "shared prefix only", basePath: "/api", path: "/apiary", want: "/apiary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripBasePath(tt.basePath, tt.path); got != tt.want {
+				t.Errorf("stripBasePath(%q, %q) = %q, want %q", tt.basePath, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateRouteSpec_BasePath(t *testing.T) {
+	spec := generateRouteSpec("/api/v1/health", "GET", GeneratorConfig{BasePath: "/api/v1"})
+
+	if tags := spec["tags"].([]string); tags[0] != "System" {
+		t.Errorf("tags = %v, want [System]", tags)
+	}
+	if spec["description"] != "GET /api/v1/health" {
+		t.Errorf("description = %v, want the full path", spec["description"])
+	}
+}
+
 func TestDetermineTag(t *testing.T) {
 	tests := []struct {
 		name string
@@ -119,6 +238,37 @@ func TestDetermineTag(t *testing.T) {
 	}
 }
 
+func TestRouteTag(t *testing.T) {
+	groupTags := map[string]string{
+		"/admin":      "Administration",
+		"/admin/jobs": "Jobs",
+		"/api/v1/":    "",
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		relative string
+		want     string
+	}{
+		{name: "group tag", path: "/admin/users", relative: "/admin/users", want: "Administration"},
+		{name: "group root", path: "/admin", relative: "/admin", want: "Administration"},
+		{name: "longest prefix wins", path: "/admin/jobs/:id", relative: "/admin/jobs/:id", want: "Jobs"},
+		{name: "segment prefix only", path: "/administrators", relative: "/administrators", want: "Administrators"},
+		{name: "empty tag strips the prefix", path: "/api/v1/users", relative: "/api/v1/users", want: "Users"},
+		{name: "stripped health route", path: "/api/v1/health", relative: "/api/v1/health", want: "System"},
+		{name: "no group uses the relative path", path: "/v2/orders", relative: "/orders", want: "Orders"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := routeTag(tt.path, tt.relative, groupTags); got != tt.want {
+				t.Errorf("routeTag(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGenerateSummary(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -166,7 +316,7 @@ func TestGenerateSummary(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := generateSummary(tt.path, tt.method); got != tt.want {
+			if got := generateSummary(tt.path, tt.method, GeneratorConfig{}); got != tt.want {
 				t.Errorf("generateSummary(%q, %q) = %v, want %v", tt.path, tt.method, got, tt.want)
 			}
 		})
@@ -196,7 +346,7 @@ func TestGenerateDescription(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := generateDescription(tt.path, tt.method); got != tt.want {
+			if got := generateDescription(tt.path, tt.method, GeneratorConfig{}); got != tt.want {
 				t.Errorf("generateDescription(%q, %q) = %v, want %v", tt.path, tt.method, got, tt.want)
 			}
 		})
@@ -218,7 +368,7 @@ func TestExtractPathParameters(t *testing.T) {
 					"in":          "path",
 					"required":    true,
 					"description": "Path parameter: id",
-					"schema":      map[string]string{"type": "string"},
+					"schema":      map[string]interface{}{"type": "string"},
 				},
 			},
 		},
@@ -231,14 +381,14 @@ func TestExtractPathParameters(t *testing.T) {
 					"in":          "path",
 					"required":    true,
 					"description": "Path parameter: userId",
-					"schema":      map[string]string{"type": "string"},
+					"schema":      map[string]interface{}{"type": "string"},
 				},
 				{
 					"name":        "postId",
 					"in":          "path",
 					"required":    true,
 					"description": "Path parameter: postId",
-					"schema":      map[string]string{"type": "string"},
+					"schema":      map[string]interface{}{"type": "string"},
 				},
 			},
 		},
@@ -251,7 +401,7 @@ func TestExtractPathParameters(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := extractPathParameters(tt.path)
+			got := extractPathParameters(tt.path, GeneratorConfig{})
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("extractPathParameters(%q) = %v, want %v", tt.path, got, tt.want)
 			}
@@ -424,7 +574,7 @@ func TestGenerateRouteSpec(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := generateRouteSpec(tt.path, tt.method)
+			got := generateRouteSpec(tt.path, tt.method, GeneratorConfig{})
 
 			// Validate basic structure
 			if _, ok := got["summary"]; !ok {
@@ -491,7 +641,7 @@ func TestDiscoverNonResourceRoutes(t *testing.T) {
 				app.Put("/api/users/:id", func(c fiber.Ctx) error { return nil })
 			},
 			resourcePaths: map[string]bool{},
-			wantPaths:     []string{"/api/users/:id"},
+			wantPaths:     []string{"/api/users/{id}"},
 			skipPaths:     []string{},
 		},
 	}
@@ -501,7 +651,7 @@ func TestDiscoverNonResourceRoutes(t *testing.T) {
 			app := fiber.New()
 			tt.setupRoutes(app)
 
-			got := discoverNonResourceRoutes(app, tt.resourcePaths)
+			got := discoverNonResourceRoutes(app, tt.resourcePaths, GeneratorConfig{})
 
 			// Check wanted paths are present
 			for _, wantPath := range tt.wantPaths {
@@ -538,3 +688,374 @@ func TestDiscoverNonResourceRoutes(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateRouteSpec_HealthSchema(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  GeneratorConfig
+		want map[string]interface{}
+	}{
+		{
+			name: "default health schema",
+			cfg:  GeneratorConfig{},
+			want: defaultHealthSchema(),
+		},
+		{
+			name: "configured health schema",
+			cfg: GeneratorConfig{
+				HealthSchema: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"healthy": map[string]interface{}{"type": "boolean"},
+					},
+				},
+			},
+			want: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"healthy": map[string]interface{}{"type": "boolean"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := generateRouteSpec("/health", "GET", tt.cfg)
+
+			responses := spec["responses"].(map[string]interface{})
+			ok := responses["200"].(map[string]interface{})
+			content := ok["content"].(map[string]interface{})
+			schema := content["application/json"].(map[string]interface{})["schema"]
+
+			if !reflect.DeepEqual(schema, tt.want) {
+				t.Errorf("/health schema = %v, want %v", schema, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateRouteSpec_HealthSchemaOnlyOnSystemGET(t *testing.T) {
+	spec := generateRouteSpec("/auth/me", "GET", GeneratorConfig{})
+	responses := spec["responses"].(map[string]interface{})
+
+	if !reflect.DeepEqual(responses, generateResponses("GET")) {
+		t.Errorf("non-system route responses = %v, want generic GET responses", responses)
+	}
+}
+
+func TestAppRoutes_Mounted(t *testing.T) {
+	newApps := func() (app, billing, lines *fiber.App) {
+		noop := func(c fiber.Ctx) error { return nil }
+		app, billing, lines = fiber.New(), fiber.New(), fiber.New()
+		lines.Get("/", noop)
+		billing.Get("/invoices", noop)
+		billing.Use("/lines", lines)
+		app.Use("/billing", billing)
+		app.Get("/health", noop)
+		return app, billing, lines
+	}
+	getRoutes := func(routes []fiber.Route) []string {
+		var keys []string
+		for _, route := range routes {
+			if route.Method == "GET" {
+				keys = append(keys, route.Path)
+			}
+		}
+		slices.Sort(keys)
+		return keys
+	}
+	want := []string{"/billing/invoices", "/billing/lines/", "/health"}
+
+	t.Run("before startup", func(t *testing.T) {
+		app, billing, lines := newApps()
+		if got := getRoutes(appRoutes(app, nil)); !reflect.DeepEqual(got, []string{"/health"}) {
+			t.Errorf("appRoutes() without mounted apps = %v, want only /health", got)
+		}
+		if got := getRoutes(appRoutes(app, []*fiber.App{billing, lines})); !reflect.DeepEqual(got, want) {
+			t.Errorf("appRoutes() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("after startup", func(t *testing.T) {
+		app, billing, lines := newApps()
+		if _, err := app.Test(httptest.NewRequest("GET", "/health", nil)); err != nil {
+			t.Fatalf("Test request failed: %v", err)
+		}
+		if got := getRoutes(appRoutes(app, []*fiber.App{billing, lines})); !reflect.DeepEqual(got, want) {
+			t.Errorf("appRoutes() = %v, want %v listed once", got, want)
+		}
+	})
+
+	t.Run("mounted on the root", func(t *testing.T) {
+		app, root := fiber.New(), fiber.New()
+		root.Get("/orders", func(c fiber.Ctx) error { return nil })
+		app.Use("/", root)
+		if got := getRoutes(appRoutes(app, []*fiber.App{root})); !reflect.DeepEqual(got, []string{"/orders"}) {
+			t.Errorf("appRoutes() = %v, want /orders", got)
+		}
+	})
+
+	t.Run("not mounted", func(t *testing.T) {
+		app, _, _ := newApps()
+		standalone := fiber.New()
+		standalone.Get("/orders", func(c fiber.Ctx) error { return nil })
+		if got := getRoutes(appRoutes(app, []*fiber.App{standalone})); !reflect.DeepEqual(got, []string{"/health"}) {
+			t.Errorf("appRoutes() = %v, want apps not mounted left out", got)
+		}
+	})
+}
+
+func TestRegisterMountedApps_Spec(t *testing.T) {
+	app, billing := fiber.New(), fiber.New()
+	billing.Post("/invoices", func(c fiber.Ctx) error { return nil })
+	app.Use("/billing", billing)
+
+	p := NewPlugin().(*OpenAPIPlugin)
+	if err := p.Initialize(map[string]interface{}{}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	p.RegisterMountedApps(billing)
+
+	spec, err := generateOpenAPISpec(app, p.generatorConfig())
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+	invoices, ok := spec["paths"].(map[string]interface{})["/billing/invoices"].(map[string]interface{})
+	if !ok || invoices["post"] == nil {
+		t.Errorf("paths = %v, want POST /billing/invoices", sortedKeys(spec["paths"].(map[string]interface{})))
+	}
+}
+
+func TestRegisterMountedApps_BeforeMount(t *testing.T) {
+	plugin, app := setupOpenAPIJSONTest(t)
+	if err := plugin.SetupEndpoints(app); err != nil {
+		t.Fatalf("SetupEndpoints() error = %v", err)
+	}
+	billing := fiber.New()
+	billing.Post("/invoices", func(c fiber.Ctx) error { return nil })
+	plugin.RegisterMountedApps(billing)
+
+	documented := func() bool {
+		doc, err := plugin.cache.static()
+		if err != nil {
+			t.Fatalf("static() error = %v", err)
+		}
+		_, ok := doc["paths"].(map[string]interface{})["/billing/invoices"]
+		return ok
+	}
+	if documented() {
+		t.Fatal("/billing/invoices should not be documented before billing is mounted")
+	}
+
+	app.Use("/billing", billing)
+	if !documented() {
+		t.Error("/billing/invoices should be documented once billing is mounted")
+	}
+}
+
+func TestRoutePathVariants(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		want []string
+	}{
+		{name: "plain", path: "/users/:id", want: []string{"/users/:id"}},
+		{name: "trailing slash kept", path: "/users/", want: []string{"/users/"}},
+		{name: "optional", path: "/users/:id?", want: []string{"/users/:id", "/users"}},
+		{name: "optional root", path: "/:lang?", want: []string{"/:lang", "/"}},
+		{name: "two optionals", path: "/a/:x?/:y?", want: []string{"/a/:x/:y", "/a/:x", "/a/:y", "/a"}},
+		{name: "star wildcard", path: "/files/*", want: []string{"/files/:path"}},
+		{name: "plus wildcard", path: "/files/+", want: []string{"/files/:path"}},
+		{name: "numbered wildcards", path: "/copy/*1/to/*2", want: []string{"/copy/:path1/to/:path2"}},
+		{name: "wildcard within a segment", path: "/files/*.txt", want: []string{"/files/*.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := routePathVariants(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("routePathVariants(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverNonResourceRoutes_OptionalAndWildcard(t *testing.T) {
+	app := fiber.New()
+	app.Get("/reports", func(c fiber.Ctx) error { return nil })
+	app.Get("/reports/:id?", func(c fiber.Ctx) error { return nil })
+	app.Get("/static/*", func(c fiber.Ctx) error { return nil })
+
+	discovered := discoverNonResourceRoutes(app, map[string]bool{}, GeneratorConfig{})
+
+	for _, path := range []string{"/reports", "/reports/{id}", "/static/{path}"} {
+		if discovered[path]["get"] == nil {
+			t.Errorf("discoverNonResourceRoutes() missing GET %s", path)
+		}
+	}
+	for _, path := range []string{"/reports/:id?", "/reports/:id", "/static/*", "/static/:path"} {
+		if _, ok := discovered[path]; ok {
+			t.Errorf("fiber path %s documented verbatim", path)
+		}
+	}
+
+	params := discovered["/static/{path}"]["get"].(map[string]interface{})["parameters"].([]map[string]interface{})
+	if len(params) != 1 || params[0]["name"] != "path" || params[0]["required"] != true {
+		t.Errorf("/static/{path} parameters = %v, want the required path parameter", params)
+	}
+	if _, ok := discovered["/reports"]["get"].(map[string]interface{})["parameters"]; ok {
+		t.Error("the variant without the optional parameter must not document it")
+	}
+}
+
+func TestStripParamConstraints(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/users/:id", want: "/users/:id"},
+		{path: "/users/:id<int>", want: "/users/:id"},
+		{path: "/users/:id<int;min(1)>/posts/:slug<regex(^[a-z-]+$)>", want: "/users/:id/posts/:slug"},
+		{path: "/reports/:year<int>?", want: "/reports/:year?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := stripParamConstraints(tt.path); got != tt.want {
+				t.Errorf("stripParamConstraints(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParamConstraintSchema(t *testing.T) {
+	tests := []struct {
+		constraints string
+		want        map[string]interface{}
+	}{
+		{constraints: "", want: map[string]interface{}{"type": "string"}},
+		{constraints: "int", want: map[string]interface{}{"type": "integer"}},
+		{constraints: "bool", want: map[string]interface{}{"type": "boolean"}},
+		{constraints: "float", want: map[string]interface{}{"type": "number"}},
+		{constraints: "guid", want: map[string]interface{}{"type": "string", "format": "uuid"}},
+		{constraints: "alpha", want: map[string]interface{}{"type": "string", "pattern": "^[a-zA-Z]+$"}},
+		{constraints: `regex(\d{4}-\d{2})`, want: map[string]interface{}{"type": "string", "pattern": `\d{4}-\d{2}`}},
+		{constraints: "minLen(2);maxLen(8)", want: map[string]interface{}{"type": "string", "minLength": 2, "maxLength": 8}},
+		{constraints: "len(3)", want: map[string]interface{}{"type": "string", "minLength": 3, "maxLength": 3}},
+		{constraints: "betweenLen(2,5)", want: map[string]interface{}{"type": "string", "minLength": 2, "maxLength": 5}},
+		{constraints: "int;min(1)", want: map[string]interface{}{"type": "integer", "minimum": 1}},
+		{constraints: "max(10)", want: map[string]interface{}{"type": "integer", "maximum": 10}},
+		{constraints: "range(1, 12)", want: map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 12}},
+		{constraints: "datetime(2006-01-02)", want: map[string]interface{}{"type": "string"}},
+		{constraints: "int;regex(^1;2$)", want: map[string]interface{}{"type": "integer", "pattern": "^1;2$"}},
+		{constraints: `regex(^a\;b$);minLen(3)`, want: map[string]interface{}{"type": "string", "pattern": `^a\;b$`, "minLength": 3}},
+		{constraints: "regex((a|b);c)", want: map[string]interface{}{"type": "string", "pattern": "(a|b);c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraints, func(t *testing.T) {
+			if got := paramConstraintSchema(tt.constraints); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("paramConstraintSchema(%q) = %v, want %v", tt.constraints, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverNonResourceRoutes_ConstrainedParams(t *testing.T) {
+	app := fiber.New()
+	app.Get("/orders/:id<int;min(1)>", func(c fiber.Ctx) error { return nil })
+	app.Get("/archive/:year<regex(\\d{4})>?", func(c fiber.Ctx) error { return nil })
+
+	discovered := discoverNonResourceRoutes(app, map[string]bool{}, GeneratorConfig{})
+
+	for _, path := range []string{"/orders/{id}", "/archive", "/archive/{year}"} {
+		if discovered[path]["get"] == nil {
+			t.Errorf("discoverNonResourceRoutes() missing GET %s, got %v", path, sortedKeys(discovered))
+		}
+	}
+
+	tests := []struct {
+		path string
+		want map[string]interface{}
+	}{
+		{path: "/orders/{id}", want: map[string]interface{}{"type": "integer", "minimum": 1}},
+		{path: "/archive/{year}", want: map[string]interface{}{"type": "string", "pattern": `\d{4}`}},
+	}
+	for _, tt := range tests {
+		op, ok := discovered[tt.path]["get"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		params := op["parameters"].([]map[string]interface{})
+		if len(params) != 1 || !reflect.DeepEqual(params[0]["schema"], tt.want) {
+			t.Errorf("%s parameters = %v, want schema %v", tt.path, params, tt.want)
+		}
+		if strings.Contains(op["summary"].(string), "<") {
+			t.Errorf("%s summary = %q, want the constraints left out", tt.path, op["summary"])
+		}
+	}
+}
+
+func TestDiscoversMethod(t *testing.T) {
+	methods := map[string]bool{"HEAD": false, "options": false, "TRACE": true}
+
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{method: "GET", want: true},
+		{method: "HEAD", want: false},
+		{method: "OPTIONS", want: false},
+		{method: "TRACE", want: true},
+		{method: "CONNECT", want: false},
+		{method: "QUERY", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := discoversMethod(methods, tt.method); got != tt.want {
+				t.Errorf("discoversMethod(%q) = %v, want %v", tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverNonResourceRoutes_Methods(t *testing.T) {
+	app := fiber.New()
+	app.All("/webhooks", func(c fiber.Ctx) error { return nil })
+
+	discovered := discoverNonResourceRoutes(app, map[string]bool{}, GeneratorConfig{
+		DiscoverMethods: map[string]bool{"HEAD": false, "OPTIONS": false, "TRACE": false},
+	})
+
+	for _, method := range []string{"head", "options", "connect", "trace", "query"} {
+		if _, ok := discovered["/webhooks"][method]; ok {
+			t.Errorf("discoverNonResourceRoutes() documented %s /webhooks, want it skipped", method)
+		}
+	}
+	for _, method := range []string{"get", "post", "put", "patch", "delete"} {
+		if _, ok := discovered["/webhooks"][method]; !ok {
+			t.Errorf("discoverNonResourceRoutes() missing %s /webhooks", method)
+		}
+	}
+}
+
+func TestDiscoverNonResourceRoutes_PathTemplates(t *testing.T) {
+	app := fiber.New()
+	app.Get("/users/:userId/posts/:postId", func(c fiber.Ctx) error { return nil })
+
+	discovered := discoverNonResourceRoutes(app, map[string]bool{}, GeneratorConfig{})
+
+	op, ok := discovered["/users/{userId}/posts/{postId}"]["get"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("discoverNonResourceRoutes() paths = %v, want the OpenAPI path template", sortedKeys(discovered))
+	}
+	for _, param := range op["parameters"].([]map[string]interface{}) {
+		if !strings.Contains("/users/{userId}/posts/{postId}", "{"+param["name"].(string)+"}") {
+			t.Errorf("path parameter %v is not referenced by the path template", param["name"])
+		}
+	}
+	if description := op["description"].(string); strings.Contains(description, ":") {
+		t.Errorf("description = %q, want the OpenAPI path", description)
+	}
+}
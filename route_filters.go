@@ -0,0 +1,126 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// routeFilter selects the discovered routes documented in the spec from
+// include and exclude path patterns. Patterns starting with ^ are regular
+// expressions, others globs where * matches within a segment and ** any
+// number of segments (/debug/*, /api/**).
+type routeFilter struct {
+	include []routePattern
+	exclude []routePattern
+}
+
+// routePattern matches a route path against a glob or a regular expression.
+type routePattern struct {
+	glob  []string
+	regex *regexp.Regexp
+}
+
+// newRouteFilter compiles the include and exclude route patterns.
+func newRouteFilter(include, exclude []string) (routeFilter, error) {
+	var filter routeFilter
+	var err error
+	if filter.include, err = compileRoutePatterns(include); err != nil {
+		return routeFilter{}, err
+	}
+	if filter.exclude, err = compileRoutePatterns(exclude); err != nil {
+		return routeFilter{}, err
+	}
+	return filter, nil
+}
+
+func compileRoutePatterns(patterns []string) ([]routePattern, error) {
+	compiled := make([]routePattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		p, err := compileRoutePattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("route pattern %q is invalid: %w", pattern, err)
+		}
+		compiled = append(compiled, p)
+	}
+	return compiled, nil
+}
+
+func compileRoutePattern(pattern string) (routePattern, error) {
+	if strings.HasPrefix(pattern, "^") {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return routePattern{}, err
+		}
+		return routePattern{regex: regex}, nil
+	}
+
+	if !strings.HasPrefix(pattern, "/") {
+		return routePattern{}, errors.New("a glob must start with /, a regular expression with ^")
+	}
+	glob := routeSegments(pattern)
+	for _, segment := range glob {
+		if _, err := path.Match(segment, ""); err != nil {
+			return routePattern{}, err
+		}
+	}
+	return routePattern{glob: glob}, nil
+}
+
+// allows reports whether the route path is documented: matched by an include
+// pattern, if any, and by no exclude pattern.
+func (f routeFilter) allows(route string) bool {
+	if len(f.include) > 0 && !matchesAnyRoutePattern(f.include, route) {
+		return false
+	}
+	return !matchesAnyRoutePattern(f.exclude, route)
+}
+
+func matchesAnyRoutePattern(patterns []routePattern, route string) bool {
+	for _, p := range patterns {
+		if p.matches(route) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p routePattern) matches(route string) bool {
+	if p.regex != nil {
+		return p.regex.MatchString(route)
+	}
+	return matchGlobSegments(p.glob, routeSegments(route))
+}
+
+// matchGlobSegments matches path segments against glob segments, ** standing
+// for any number of them.
+func matchGlobSegments(glob, segments []string) bool {
+	if len(glob) == 0 {
+		return len(segments) == 0
+	}
+	if glob[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchGlobSegments(glob[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(glob[0], segments[0]); !matched {
+		return false
+	}
+	return matchGlobSegments(glob[1:], segments[1:])
+}
+
+func routeSegments(route string) []string {
+	route = strings.Trim(route, "/")
+	if route == "" {
+		return nil
+	}
+	return strings.Split(route, "/")
+}
@@ -0,0 +1,69 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+func TestRouteFilter_Allows(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		path    string
+		want    bool
+	}{
+		{name: "no patterns", path: "/metrics", want: true},
+		{name: "excluded path", exclude: []string{"/metrics"}, path: "/metrics", want: false},
+		{name: "exclude is exact", exclude: []string{"/metrics"}, path: "/metrics/raw", want: true},
+		{name: "single segment wildcard", exclude: []string{"/debug/*"}, path: "/debug/pprof", want: false},
+		{name: "single segment wildcard depth", exclude: []string{"/debug/*"}, path: "/debug/pprof/heap", want: true},
+		{name: "segment glob", exclude: []string{"/health*"}, path: "/healthz", want: false},
+		{name: "included subtree", include: []string{"/api/**"}, path: "/api/v1/users/:id", want: true},
+		{name: "included subtree root", include: []string{"/api/**"}, path: "/api", want: true},
+		{name: "not included", include: []string{"/api/**"}, path: "/metrics", want: false},
+		{name: "inner double wildcard", include: []string{"/api/**/export"}, path: "/api/v1/users/export", want: true},
+		{name: "regular expression", exclude: []string{`^/internal/.*`}, path: "/internal/jobs", want: false},
+		{name: "exclude wins over include", include: []string{"/api/**"}, exclude: []string{"/api/debug"}, path: "/api/debug", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newRouteFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("newRouteFilter() error = %v", err)
+			}
+			if got := filter.allows(tt.path); got != tt.want {
+				t.Errorf("allows(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRouteFilter_InvalidPatterns(t *testing.T) {
+	for _, pattern := range []string{"metrics", "^/debug/(", "/[api"} {
+		t.Run(pattern, func(t *testing.T) {
+			if _, err := newRouteFilter(nil, []string{pattern}); err == nil {
+				t.Errorf("newRouteFilter(%q) error = nil, want an invalid pattern error", pattern)
+			}
+		})
+	}
+}
+
+func TestDiscoverNonResourceRoutes_Filters(t *testing.T) {
+	app := fiber.New()
+	app.Get("/api/v1/orders", func(c fiber.Ctx) error { return nil })
+	app.Get("/api/debug", func(c fiber.Ctx) error { return nil })
+	app.Get("/metrics", func(c fiber.Ctx) error { return nil })
+	app.Get("/debug/pprof", func(c fiber.Ctx) error { return nil })
+
+	discovered := discoverNonResourceRoutes(app, map[string]bool{}, GeneratorConfig{
+		IncludeRoutes: []string{"/api/**"},
+		ExcludeRoutes: []string{"/api/debug"},
+	})
+
+	if got := sortedKeys(discovered); len(got) != 1 || got[0] != "/api/v1/orders" {
+		t.Errorf("discoverNonResourceRoutes() paths = %v, want [/api/v1/orders]", got)
+	}
+}
@@ -0,0 +1,248 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/nicolasbonnici/gorest/logger"
+)
+
+// Operation documents a route discovered from the app, replacing what
+// generateRouteSpec infers from its path and method. Empty fields keep the
+// inferred documentation.
+type Operation struct {
+	Summary     string
+	Description string
+	Tags        []string
+	// Query is a params struct whose fields tagged `query:"name"` document
+	// the query parameters of the route.
+	Query interface{}
+	// Request is the model of the JSON request body, documented by
+	// reflection like plugin resource models.
+	Request interface{}
+	// Responses documents the responses by status code, replacing the
+	// generic ones.
+	Responses map[int]Response
+}
+
+// Response documents a response of an Operation. A nil Model documents a
+// response without body; an empty Description uses the status text.
+type Response struct {
+	Description string
+	Model       interface{}
+}
+
+// Describe documents the route registered on the app with method and path
+// (the fiber path as registered, e.g. /users/:id<int>), replacing the generic summary,
+// description, tags, request body and responses of discovered routes, and
+// adding the query parameters of its params struct. The cached spec is
+// regenerated.
+func (p *OpenAPIPlugin) Describe(method, path string, op Operation) error {
+	return p.updateRouteOperation(method, path, func(described *Operation) { *described = op })
+}
+
+// RouteOption binds a model to a route documented with Bind.
+type RouteOption func(*Operation)
+
+// WithRequest documents the JSON request body of the route with the schema
+// of model.
+func WithRequest(model interface{}) RouteOption {
+	return func(op *Operation) { op.Request = model }
+}
+
+// WithResponse documents the status response of the route with the schema
+// of model, nil for a response without body. Routes with bound responses
+// lose their generic ones.
+func WithResponse(status int, model interface{}) RouteOption {
+	return func(op *Operation) {
+		response := op.Responses[status]
+		response.Model = model
+		if op.Responses == nil {
+			op.Responses = make(map[int]Response)
+		}
+		op.Responses[status] = response
+	}
+}
+
+// WithQuery documents the query parameters of the route from a params
+// struct, like Operation.Query.
+func WithQuery(params interface{}) RouteOption {
+	return func(op *Operation) { op.Query = params }
+}
+
+// Bind binds request, response and query models to the route registered on
+// the app with method and path, on top of what Describe documented for it,
+// so custom endpoints get real schemas instead of bare objects. The cached
+// spec is regenerated.
+func (p *OpenAPIPlugin) Bind(method, path string, opts ...RouteOption) error {
+	return p.updateRouteOperation(method, path, func(op *Operation) {
+		op.Responses = maps.Clone(op.Responses)
+		for _, opt := range opts {
+			opt(op)
+		}
+	})
+}
+
+// updateRouteOperation applies update to the documented operation of a
+// route, after validating the route and the resulting operation.
+func (p *OpenAPIPlugin) updateRouteOperation(method, path string, update func(*Operation)) error {
+	method = strings.ToUpper(method)
+	if !slices.Contains(fiber.DefaultMethods, method) {
+		return fmt.Errorf("route %s %s: unknown HTTP method", method, path)
+	}
+	if !strings.HasPrefix(path, "/") {
+		return errors.New("route path must start with /")
+	}
+
+	keys := routeOperationKeys(method, path)
+	updated := make(map[string]Operation, len(keys))
+	p.routeOperationsMu.Lock()
+	for _, key := range keys {
+		op := p.routeOperations[key]
+		update(&op)
+		if op.Query != nil && modelType(op.Query) == nil {
+			p.routeOperationsMu.Unlock()
+			return fmt.Errorf("route %s %s: query params must be a struct or a pointer to a struct, got %T", method, path, op.Query)
+		}
+		updated[key] = op
+	}
+	if p.routeOperations == nil {
+		p.routeOperations = make(map[string]Operation)
+	}
+	maps.Copy(p.routeOperations, updated)
+	p.routeOperationsMu.Unlock()
+
+	p.Invalidate()
+	return nil
+}
+
+// routeOperationKeys returns the "METHOD /path" keys of the operations
+// discovery documents a route with: constraints stripped, with and without
+// optional parameters, wildcards as parameters (/files/* -> /files/:path).
+func routeOperationKeys(method, path string) []string {
+	variants := routePathVariants(path)
+	keys := make([]string, len(variants))
+	for i, variant := range variants {
+		keys[i] = method + " " + stripParamConstraints(variant)
+	}
+	return keys
+}
+
+// describedRoutes returns the operations documented with Describe, by
+// "METHOD /path".
+func (p *OpenAPIPlugin) describedRoutes() map[string]Operation {
+	p.routeOperationsMu.RLock()
+	defer p.routeOperationsMu.RUnlock()
+	return maps.Clone(p.routeOperations)
+}
+
+// applyRouteOperations documents the operations listed by "METHOD /path"
+// with their described Operation. Described routes matching no operation,
+// not registered or left out of the spec, are logged.
+func applyRouteOperations(paths map[string]interface{}, operations map[string]Operation, models *modelSchemaBuilder) {
+	if len(operations) == 0 {
+		return
+	}
+
+	applied := make(map[string]bool, len(operations))
+
+	forEachOperation(paths, func(path, method string, op map[string]interface{}) {
+		key := strings.ToUpper(method) + " " + fiberPath(path)
+		described, ok := operations[key]
+		if !ok {
+			return
+		}
+		applied[key] = true
+
+		if described.Summary != "" {
+			op["summary"] = described.Summary
+		}
+		if described.Description != "" {
+			op["description"] = described.Description
+		}
+		if len(described.Tags) > 0 {
+			op["tags"] = described.Tags
+		}
+		if described.Query != nil {
+			op["parameters"] = mergeParameters(op["parameters"], queryParameters(described.Query))
+		}
+		if described.Request != nil {
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  jsonContent(models.modelSchema(described.Request)),
+			}
+		}
+		if len(described.Responses) > 0 {
+			responses := make(map[string]interface{}, len(described.Responses))
+			for status, response := range described.Responses {
+				responses[strconv.Itoa(status)] = routeResponse(status, response, models)
+			}
+			op["responses"] = responses
+		}
+	})
+
+	for _, key := range sortedKeys(operations) {
+		if !applied[key] {
+			logger.Log.Warn("Described route matches no documented operation", "route", key)
+		}
+	}
+}
+
+// routeResponse documents a described response.
+func routeResponse(status int, response Response, models *modelSchemaBuilder) map[string]interface{} {
+	description := response.Description
+	if description == "" {
+		description = http.StatusText(status)
+	}
+
+	documented := map[string]interface{}{"description": description}
+	if response.Model != nil {
+		documented["content"] = jsonContent(models.modelSchema(response.Model))
+	}
+	return documented
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+// modelSchema documents a request or response model: a resource model by
+// reference, another struct inline, and slices of them as arrays.
+func (b *modelSchemaBuilder) modelSchema(model interface{}) map[string]interface{} {
+	return b.modelTypeSchema(reflect.TypeOf(model))
+}
+
+func (b *modelSchemaBuilder) modelTypeSchema(t reflect.Type) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		return b.modelTypeSchema(t.Elem())
+	}
+	if _, ok := b.refs[t]; ok {
+		return b.propertySchema(t)
+	}
+	if _, ok := libraryTypeSchema(t.String()); ok || t == reflect.TypeOf(time.Time{}) {
+		return b.propertySchema(t)
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return b.structSchema(t)
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			return map[string]interface{}{
+				"type":  "array",
+				"items": b.modelTypeSchema(t.Elem()),
+			}
+		}
+	}
+	return b.propertySchema(t)
+}
@@ -0,0 +1,260 @@
+package openapi
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type loginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	Token string `json:"token"`
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		wantKeys []string
+		wantErr  bool
+	}{
+		{name: "registered", method: "POST", path: "/auth/login", wantKeys: []string{"POST /auth/login"}},
+		{name: "lower case method", method: "get", path: "/users/:id", wantKeys: []string{"GET /users/:id"}},
+		{name: "constraints", method: "GET", path: "/orders/:id<int;min(1)>", wantKeys: []string{"GET /orders/:id"}},
+		{name: "optional parameter", method: "GET", path: "/archive/:year<int>?", wantKeys: []string{"GET /archive/:year", "GET /archive"}},
+		{name: "wildcard", method: "GET", path: "/files/*", wantKeys: []string{"GET /files/:path"}},
+		{name: "unknown method", method: "FETCH", path: "/auth/login", wantErr: true},
+		{name: "relative path", method: "POST", path: "auth/login", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &OpenAPIPlugin{}
+			err := p.Describe(tt.method, tt.path, Operation{Summary: "Sign in"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Describe() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			routes := p.describedRoutes()
+			if tt.wantErr {
+				if len(routes) != 0 {
+					t.Errorf("describedRoutes() = %v, want none after an error", routes)
+				}
+				return
+			}
+			if got := sortedKeys(routes); !reflect.DeepEqual(got, slices.Sorted(slices.Values(tt.wantKeys))) {
+				t.Errorf("describedRoutes() = %v, want %v", got, tt.wantKeys)
+			}
+			for _, key := range tt.wantKeys {
+				if routes[key].Summary != "Sign in" {
+					t.Errorf("describedRoutes()[%s] = %v, want it described", key, routes[key])
+				}
+			}
+		})
+	}
+}
+
+func TestDescribe_Spec(t *testing.T) {
+	app := fiber.New()
+	app.Post("/auth/login", func(c fiber.Ctx) error { return nil })
+	app.Post("/auth/logout", func(c fiber.Ctx) error { return nil })
+
+	p := NewPlugin().(*OpenAPIPlugin)
+	if err := p.Initialize(map[string]interface{}{}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	err := p.Describe("POST", "/auth/login", Operation{
+		Summary:     "Sign in",
+		Description: "Exchanges credentials for a session token.",
+		Tags:        []string{"Session"},
+		Request:     loginRequest{},
+		Responses: map[int]Response{
+			200: {Description: "Signed in", Model: &loginResponse{}},
+			401: {},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(app, p.generatorConfig())
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+	paths := spec["paths"].(map[string]interface{})
+
+	login := paths["/auth/login"].(map[string]interface{})["post"].(map[string]interface{})
+	if login["summary"] != "Sign in" || login["description"] != "Exchanges credentials for a session token." {
+		t.Errorf("login summary = %v, description = %v, want the described ones", login["summary"], login["description"])
+	}
+	if tags := login["tags"]; !reflect.DeepEqual(tags, []string{"Session"}) {
+		t.Errorf("login tags = %v, want [Session]", tags)
+	}
+
+	body := login["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	properties := body["properties"].(map[string]interface{})
+	for _, name := range []string{"email", "password"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("login request schema missing %q", name)
+		}
+	}
+
+	responses := login["responses"].(map[string]interface{})
+	if len(responses) != 2 {
+		t.Errorf("login responses = %v, want 200 and 401", sortedKeys(responses))
+	}
+	ok := responses["200"].(map[string]interface{})
+	okSchema := ok["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	if ok["description"] != "Signed in" || okSchema["properties"].(map[string]interface{})["token"] == nil {
+		t.Errorf("login 200 = %v, want the described token response", ok)
+	}
+	if want := map[string]interface{}{"description": "Unauthorized"}; !reflect.DeepEqual(responses["401"], want) {
+		t.Errorf("login 401 = %v, want %v", responses["401"], want)
+	}
+
+	logout := paths["/auth/logout"].(map[string]interface{})["post"].(map[string]interface{})
+	if logout["summary"] == "Sign in" {
+		t.Error("undescribed routes must keep their inferred documentation")
+	}
+}
+
+func TestDescribe_ConstrainedRouteSpec(t *testing.T) {
+	app := fiber.New()
+	app.Get("/orders/:id<int>", func(c fiber.Ctx) error { return nil })
+
+	p := NewPlugin().(*OpenAPIPlugin)
+	if err := p.Initialize(map[string]interface{}{}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if err := p.Describe("GET", "/orders/:id<int>", Operation{Summary: "Show an order"}); err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(app, p.generatorConfig())
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+	op := spec["paths"].(map[string]interface{})["/orders/{id}"].(map[string]interface{})["get"].(map[string]interface{})
+	if op["summary"] != "Show an order" {
+		t.Errorf("GET /orders/{id} summary = %v, want the described one", op["summary"])
+	}
+}
+
+func TestBind(t *testing.T) {
+	p := &OpenAPIPlugin{}
+	if err := p.Describe("POST", "/auth/login", Operation{
+		Summary:   "Sign in",
+		Responses: map[int]Response{200: {Description: "Signed in"}},
+	}); err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	described := p.describedRoutes()["POST /auth/login"]
+
+	if err := p.Bind("post", "/auth/login",
+		WithRequest(loginRequest{}),
+		WithResponse(200, loginResponse{}),
+		WithResponse(401, nil),
+		WithQuery(pageParams{}),
+	); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+
+	want := Operation{
+		Summary: "Sign in",
+		Query:   pageParams{},
+		Request: loginRequest{},
+		Responses: map[int]Response{
+			200: {Description: "Signed in", Model: loginResponse{}},
+			401: {},
+		},
+	}
+	if got := p.describedRoutes()["POST /auth/login"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("bound operation = %+v, want %+v", got, want)
+	}
+	if len(described.Responses) != 1 {
+		t.Errorf("Bind() modified the described responses: %v", described.Responses)
+	}
+
+	if err := p.Bind("GET", "/search", WithQuery("q")); err == nil {
+		t.Error("Bind() with non-struct query params should fail")
+	}
+	if _, ok := p.describedRoutes()["GET /search"]; ok {
+		t.Error("a failed Bind() must not document the route")
+	}
+}
+
+func TestBind_Spec(t *testing.T) {
+	app := fiber.New()
+	app.Post("/auth/token", func(c fiber.Ctx) error { return nil })
+
+	p := NewPlugin().(*OpenAPIPlugin)
+	if err := p.Initialize(map[string]interface{}{}); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if err := p.Bind("POST", "/auth/token", WithRequest(loginRequest{}), WithResponse(201, loginResponse{})); err != nil {
+		t.Fatalf("Bind() error = %v", err)
+	}
+
+	spec, err := generateOpenAPISpec(app, p.generatorConfig())
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	op := spec["paths"].(map[string]interface{})["/auth/token"].(map[string]interface{})["post"].(map[string]interface{})
+	body := op["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	if _, ok := body["properties"].(map[string]interface{})["email"]; !ok {
+		t.Errorf("request schema = %v, want the loginRequest properties", body)
+	}
+	responses := op["responses"].(map[string]interface{})
+	created, ok := responses["201"].(map[string]interface{})
+	if !ok || created["description"] != "Created" {
+		t.Fatalf("responses = %v, want a 201 described by its status text", responses)
+	}
+	schema := created["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	if _, ok := schema["properties"].(map[string]interface{})["token"]; !ok {
+		t.Errorf("201 schema = %v, want the loginResponse properties", schema)
+	}
+	if op["summary"] == "" {
+		t.Error("bound routes must keep their inferred summary")
+	}
+}
+
+func TestModelSchemaBuilder_ModelSchema(t *testing.T) {
+	refs := map[reflect.Type]string{reflect.TypeOf(registeredInvoice{}): "Invoice"}
+	b := newModelSchemaBuilder(refs)
+	ref := map[string]interface{}{"$ref": "#/components/schemas/Invoice"}
+
+	tests := []struct {
+		name  string
+		model interface{}
+		want  map[string]interface{}
+	}{
+		{name: "resource model", model: &registeredInvoice{}, want: ref},
+		{name: "slice of resource models", model: []registeredInvoice{}, want: map[string]interface{}{"type": "array", "items": ref}},
+		{name: "scalar", model: "", want: map[string]interface{}{"type": "string"}},
+		{
+			name:  "inline struct",
+			model: loginResponse{},
+			want: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"token": map[string]interface{}{"type": "string"}},
+				"required":   []string{"token"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := b.modelSchema(tt.model); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("modelSchema(%T) = %v, want %v", tt.model, got, tt.want)
+			}
+		})
+	}
+}
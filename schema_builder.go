@@ -1,50 +1,661 @@
 package openapi
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
 
-func buildSchemaPropertiesFromDTO(fields []structField) map[string]interface{} {
+// buildSchemaFromDTO builds the object schema of a DTO, with hidden fields
+// removed. With EmbeddedAllOf, embedded structs documented by a component
+// schema are composed through allOf instead of being flattened.
+func buildSchemaFromDTO(fields []structField, cfg GeneratorConfig) map[string]interface{} {
+	var refs map[string]string
+	if cfg.EmbeddedStructs == EmbeddedAllOf {
+		refs = cfg.dtoRefs
+	}
+	fields, bases := promoteEmbeddedFields(fields, refs)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": buildSchemaPropertiesFromDTO(fields, cfg),
+	}
+
+	if required := getRequiredFieldsFromDTO(fields, cfg); len(required) > 0 {
+		schema["required"] = required
+	}
+	hideSchemaFields(schema, cfg.HideFields)
+
+	if len(bases) == 0 {
+		return schema
+	}
+	allOf := make([]interface{}, 0, len(bases)+1)
+	for _, base := range bases {
+		allOf = append(allOf, map[string]interface{}{"$ref": "#/components/schemas/" + base})
+	}
+	return map[string]interface{}{"allOf": append(allOf, schema)}
+}
+
+// promoteEmbeddedFields replaces embedded fields by their members, the way
+// encoding/json promotes them: fields of the enclosing struct win over
+// promoted ones, and shallower embeddings over deeper ones. Embedded structs
+// listed in refs are kept out and returned as bases, by component name.
+func promoteEmbeddedFields(fields []structField, refs map[string]string) (promoted []structField, bases []string) {
+	seen := make(map[string]bool)
+	for _, field := range fields {
+		if !field.Embedded {
+			seen[fieldJSONName(field)] = true
+			promoted = append(promoted, field)
+		}
+	}
+	for _, field := range fields {
+		if !field.Embedded {
+			continue
+		}
+		if component, ok := refs[field.Type]; ok {
+			bases = append(bases, component)
+			continue
+		}
+		members, _ := promoteEmbeddedFields(field.Fields, nil)
+		for _, member := range members {
+			if name := fieldJSONName(member); !seen[name] {
+				seen[name] = true
+				promoted = append(promoted, member)
+			}
+		}
+	}
+	return promoted, bases
+}
+
+// fieldJSONName is the property name of a field: its json tag, or its
+// lowercased Go name.
+func fieldJSONName(field structField) string {
+	if field.JSONTag != "" {
+		return field.JSONTag
+	}
+	return strings.ToLower(field.Name)
+}
+
+func buildSchemaPropertiesFromDTO(fields []structField, cfg GeneratorConfig) map[string]interface{} {
 	properties := make(map[string]interface{})
 
+	fields, _ = promoteEmbeddedFields(fields, nil)
 	for _, field := range fields {
-		typ, format := goTypeToOpenAPIType(field.Type)
-		prop := map[string]interface{}{
-			"type": typ,
+		prop := fieldTypeSchema(field.Type, field.Fields, cfg)
+		if len(field.UnionTypes) > 0 {
+			prop = unionSchema(field.Type, field.UnionKeyword, field.UnionTypes, cfg)
+		}
+		// nullable defaults to false; the open schema already admits null
+		if field.IsPointer && len(prop) > 0 {
+			prop["nullable"] = true
+		}
+		applyValidationRules(prop, field.ValidateTag)
+		applyEnumLabels(prop, field.EnumLabelsTag)
+		applyNullableEnum(prop)
+		applyOpenAPITag(prop, field.OpenAPITag)
+		if field.DescriptionTag != "" {
+			prop["description"] = field.DescriptionTag
 		}
+		if field.Deprecated {
+			prop["deprecated"] = true
+		}
+		applyDefaultTag(prop, field.DefaultTag)
+		applyXMLTag(prop, field.XMLTag)
+		applyAccessConvention(prop, fieldJSONName(field))
+
+		properties[fieldJSONName(field)] = wrapRefSiblings(prop)
+	}
 
-		if format != "" {
-			prop["format"] = format
+	return properties
+}
+
+// fieldTypeSchema builds the schema of a parsed Go type, composing slices
+// ("[]T"), maps (objects whose additionalProperties describe the values) and
+// inline structs recursively. DTO types are referenced by $ref.
+func fieldTypeSchema(goType string, nested []structField, cfg GeneratorConfig) map[string]interface{} {
+	if goType == "[]byte" {
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	}
+
+	if elem, ok := strings.CutPrefix(goType, "[]"); ok {
+		return map[string]interface{}{
+			"type":  "array",
+			"items": fieldTypeSchema(elem, nested, cfg),
 		}
+	}
 
-		prop["nullable"] = field.IsPointer
+	if value, ok := mapValueType(goType); ok {
+		schema := map[string]interface{}{"type": "object"}
+		// Interface values admit anything, which an absent
+		// additionalProperties already says
+		if !isInterfaceType(value) {
+			schema["additionalProperties"] = fieldTypeSchema(value, nested, cfg)
+		}
+		return schema
+	}
 
-		jsonName := field.JSONTag
-		if jsonName == "" {
-			jsonName = strings.ToLower(field.Name)
+	if goType == "struct" {
+		schema := map[string]interface{}{
+			"type":       "object",
+			"properties": buildSchemaPropertiesFromDTO(nested, cfg),
+		}
+		if required := getRequiredFieldsFromDTO(nested, cfg); len(required) > 0 {
+			schema["required"] = required
 		}
+		return schema
+	}
 
-		properties[jsonName] = prop
+	if component, ok := cfg.dtoRefs[goType]; ok {
+		return map[string]interface{}{"$ref": "#/components/schemas/" + component}
 	}
 
-	return properties
+	if cfg.InterfaceSchema == InterfaceSchemaAny && isInterfaceType(goType) {
+		return map[string]interface{}{}
+	}
+
+	if named, ok := cfg.namedTypes[goType]; ok {
+		// A recursive type (type Tree []Tree) is expanded once
+		inner := cfg
+		inner.namedTypes = maps.Clone(cfg.namedTypes)
+		delete(inner.namedTypes, goType)
+		if named.Fields != nil {
+			nested = named.Fields
+		}
+		schema := applyTypeFormat(fieldTypeSchema(named.Underlying, nested, inner), goType, cfg)
+		if len(named.Enum) > 0 {
+			schema["enum"] = named.Enum
+		}
+		return schema
+	}
+
+	if schema, ok := libraryTypeSchema(goType); ok {
+		return applyTypeFormat(schema, goType, cfg)
+	}
+
+	typ, format := goTypeToOpenAPIType(goType)
+	schema := map[string]interface{}{"type": typ}
+	if format != "" {
+		schema["format"] = format
+	}
+	return applyTypeFormat(schema, goType, cfg)
 }
 
-func getRequiredFieldsFromDTO(fields []structField) []string {
-	var required []string
+// applyTypeFormat sets the format configured for goType in TypeFormats, on
+// schemas carrying a type.
+func applyTypeFormat(schema map[string]interface{}, goType string, cfg GeneratorConfig) map[string]interface{} {
+	if format, ok := cfg.TypeFormats[goType]; ok && schema["type"] != nil {
+		schema["format"] = format
+	}
+	return schema
+}
+
+// nestedDTOSchemas finds the DTO types used as field types by other DTOs.
+// refs maps each to its component schema: the resource schema when it is the
+// main DTO of a resource, and otherwise one named after the type without its
+// DTO suffix (AuthorDTO -> Author), returned in schemas for registration.
+// Names the generator gives other components (Error, UserListItem,
+// CreateUserRequest...) are never reused: the type name is used instead, with
+// a numeric suffix if needed.
+//
+// With composeEmbedded, embedded structs are collected too, so EmbeddedAllOf
+// can reference them; those declared outside the DTOs are named after their
+// type (Base).
+//
+// A nested DTO declared differently by several resources (dtos/user.go and
+// dtos/billing/user.go) cannot be told apart by its type name, and is
+// reported as an error.
+func nestedDTOSchemas(resources map[string]resourceDTOs, mainDTOs, variants map[string]string, mainPatterns []string, composeEmbedded bool) (refs map[string]string, schemas map[string]dtoSchema, err error) {
+	dtos := make(map[string]dtoSchema)
+	declaredBy := make(map[string][]string)
+	conflicts := make(map[string]bool)
+	resourceSchemas := make(map[string]string)
+	taken := map[string]bool{"Error": true}
+	for _, key := range sortedKeys(resources) {
+		resource := resources[key]
+		for _, name := range sortedKeys(resource.DTOs) {
+			dto := resource.DTOs[name]
+			declaredBy[name] = append(declaredBy[name], key)
+			if existing, ok := dtos[name]; ok {
+				if !reflect.DeepEqual(existing, dto) {
+					conflicts[name] = true
+				}
+				continue
+			}
+			dtos[name] = dto
+		}
+
+		schemaName := strings.ToUpper(resource.Name[:1]) + resource.Name[1:]
+		for _, generated := range []string{schemaName, schemaName + "ListItem", requestSchemaName(DTOVariantCreate, schemaName), requestSchemaName(DTOVariantUpdate, schemaName)} {
+			taken[generated] = true
+		}
+		if main := resource.resolveMainDTO(mainDTOs[resource.Name], variants, mainPatterns); main != nil {
+			resourceSchemas[main.Name] = schemaName
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, dto := range dtos {
+		collectFieldTypes(dto.Fields, used, composeEmbedded)
+	}
+	if composeEmbedded {
+		for _, dto := range slices.Collect(maps.Values(dtos)) {
+			collectEmbeddedStructs(dto.Fields, dtos)
+		}
+	}
+
+	var errs []error
+	for _, name := range sortedKeys(conflicts) {
+		if used[name] {
+			errs = append(errs, fmt.Errorf("nested DTO %s is declared differently by resources %s", name, strings.Join(declaredBy[name], ", ")))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	refs = make(map[string]string)
+	schemas = make(map[string]dtoSchema)
+	for _, name := range sortedKeys(used) {
+		dto, ok := dtos[name]
+		if !ok {
+			continue
+		}
+		if schemaName, ok := resourceSchemas[name]; ok {
+			refs[name] = schemaName
+			continue
+		}
+		component := strings.TrimSuffix(name, "DTO")
+		if component == "" || taken[component] {
+			component = name
+		}
+		for n := 2; taken[component]; n++ {
+			component = fmt.Sprintf("%s%d", name, n)
+		}
+		taken[component] = true
+		refs[name] = component
+		schemas[component] = dto
+	}
+	return refs, schemas, nil
+}
+
+// collectFieldTypes records the element types of fields, slice and map
+// values and inline structs included. Embedded structs are only recorded
+// with embedded set, their members always.
+func collectFieldTypes(fields []structField, types map[string]bool, embedded bool) {
+	for _, field := range fields {
+		if !field.Embedded || embedded {
+			types[elementType(field.Type)] = true
+		}
+		for _, member := range field.UnionTypes {
+			types[member] = true
+		}
+		collectFieldTypes(field.Fields, types, embedded)
+	}
+}
 
+// collectEmbeddedStructs adds the resolved embedded structs of fields that
+// are not DTOs themselves to dtos, recursively.
+func collectEmbeddedStructs(fields []structField, dtos map[string]dtoSchema) {
 	for _, field := range fields {
-		jsonName := field.JSONTag
-		if jsonName == "" {
-			jsonName = strings.ToLower(field.Name)
+		if field.Embedded && field.Fields != nil {
+			if _, ok := dtos[field.Type]; !ok {
+				dtos[field.Type] = dtoSchema{Name: field.Type, Fields: field.Fields}
+			}
+		}
+		collectEmbeddedStructs(field.Fields, dtos)
+	}
+}
+
+// Struct tags declaring a field the union of the listed types, documented with
+// oneOf (exactly one matches) or anyOf (any number match).
+const (
+	oneOfTypesTag = "oneof_types"
+	anyOfTypesTag = "anyof_types"
+)
+
+// unionTag reads the oneof_types and anyof_types struct tag values, returning
+// the schema keyword and the member type names. oneof_types wins when both
+// are set.
+func unionTag(oneOf, anyOf string) (keyword string, members []string) {
+	keyword, tag := "oneOf", oneOf
+	if tag == "" {
+		keyword, tag = "anyOf", anyOf
+	}
+	for _, member := range strings.Split(tag, ",") {
+		if member = strings.TrimSpace(member); member != "" {
+			members = append(members, member)
+		}
+	}
+	if len(members) == 0 {
+		return "", nil
+	}
+	return keyword, members
+}
+
+// unionSchema documents a field of goType as the union of the member types
+// under keyword, keeping the slices and maps around it: a []interface{} field
+// becomes an array of the union. DTO members are referenced by $ref.
+func unionSchema(goType, keyword string, members []string, cfg GeneratorConfig) map[string]interface{} {
+	if elem, ok := strings.CutPrefix(goType, "[]"); ok {
+		return map[string]interface{}{
+			"type":  "array",
+			"items": unionSchema(elem, keyword, members, cfg),
+		}
+	}
+	if value, ok := mapValueType(goType); ok {
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": unionSchema(value, keyword, members, cfg),
+		}
+	}
+
+	schemas := make([]interface{}, 0, len(members))
+	for _, member := range members {
+		schemas = append(schemas, fieldTypeSchema(member, nil, cfg))
+	}
+	return map[string]interface{}{keyword: schemas}
+}
+
+// elementType strips the slice and map layers of a parsed Go type:
+// []map[string]AddressDTO -> AddressDTO.
+func elementType(goType string) string {
+	for {
+		if elem, ok := strings.CutPrefix(goType, "[]"); ok {
+			goType = elem
+		} else if value, ok := mapValueType(goType); ok {
+			goType = value
+		} else {
+			return goType
+		}
+	}
+}
+
+// mapValueType returns the value type of a parsed map type
+// (map[string]int64 -> int64).
+func mapValueType(goType string) (string, bool) {
+	rest, ok := strings.CutPrefix(goType, "map[")
+	if !ok {
+		return "", false
+	}
+	depth := 1
+	for i, r := range rest {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return rest[i+1:], true
+			}
 		}
+	}
+	return "", false
+}
+
+func getRequiredFieldsFromDTO(fields []structField, cfg GeneratorConfig) []string {
+	var required []string
+
+	fields, _ = promoteEmbeddedFields(fields, nil)
+	for _, field := range fields {
+		jsonName := fieldJSONName(field)
 
 		if jsonName == "id" || jsonName == "created_at" || jsonName == "updated_at" {
 			continue
 		}
 
-		if !field.IsPointer {
-			required = append(required, jsonName)
+		if field.IsPointer {
+			continue
+		}
+
+		// A missing bool decodes to false, so it may be treated as optional
+		if cfg.BooleansOptional && field.Type == "bool" && !hasValidateRule(field.ValidateTag, "required") {
+			continue
 		}
+
+		required = append(required, jsonName)
 	}
 
 	return required
 }
+
+// hideSchemaFields removes the properties matching any of the given names or
+// path.Match patterns from an object schema, along with their required entry.
+func hideSchemaFields(schema map[string]interface{}, hidden []string) {
+	if len(hidden) == 0 {
+		return
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name := range properties {
+		if isHiddenField(name, hidden) {
+			delete(properties, name)
+		}
+	}
+
+	required, ok := schema["required"].([]string)
+	if !ok {
+		return
+	}
+	kept := required[:0]
+	for _, name := range required {
+		if !isHiddenField(name, hidden) {
+			kept = append(kept, name)
+		}
+	}
+	if len(kept) == 0 {
+		delete(schema, "required")
+	} else {
+		schema["required"] = kept
+	}
+}
+
+func isHiddenField(name string, hidden []string) bool {
+	for _, pattern := range hidden {
+		if pattern == name {
+			return true
+		}
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func hasValidateRule(validateTag, rule string) bool {
+	for _, r := range strings.Split(validateTag, ",") {
+		if strings.TrimSpace(r) == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOpenAPITag splits an `openapi:"key=value,flag"` struct tag into its
+// options. Flags without a value map to an empty string.
+func parseOpenAPITag(tag string) map[string]string {
+	options := make(map[string]string)
+	for _, option := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(option, "=")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		options[key] = strings.TrimSpace(value)
+	}
+	return options
+}
+
+// applyOpenAPITag applies the options of an `openapi` struct tag to a
+// property schema.
+func applyOpenAPITag(property map[string]interface{}, tag string) {
+	if tag == "" {
+		return
+	}
+
+	options := parseOpenAPITag(tag)
+	if typ := options["type"]; typ != "" && typ != property["type"] {
+		// The inferred schema no longer applies
+		for _, keyword := range []string{"$ref", "items", "properties", "additionalProperties", "required", "format"} {
+			delete(property, keyword)
+		}
+		property["type"] = typ
+	}
+	if format := options["format"]; format != "" {
+		property["format"] = format
+	}
+	if unit := options["unit"]; unit != "" {
+		property["x-unit"] = unit
+	}
+	if _, ok := options["filterable"]; ok {
+		property["x-filterable"] = true
+	}
+	if _, ok := options["sortable"]; ok {
+		property["x-sortable"] = true
+	}
+	if _, ok := options["internal"]; ok {
+		property[internalMarker] = true
+	}
+	if _, ok := options["readonly"]; ok {
+		property["readOnly"] = true
+	}
+	if _, ok := options["writeonly"]; ok {
+		property["writeOnly"] = true
+	}
+}
+
+// applyDefaultTag sets the default of a property from a `default` struct tag,
+// converted to the property type. Values not matching the type, and defaults
+// of arrays or objects, are ignored.
+func applyDefaultTag(property map[string]interface{}, tag string) {
+	if tag == "" {
+		return
+	}
+
+	var value interface{}
+	var err error
+	switch property["type"] {
+	case "string":
+		value = tag
+	case "integer":
+		value, err = strconv.ParseInt(tag, 10, 64)
+	case "number":
+		value, err = strconv.ParseFloat(tag, 64)
+	case "boolean":
+		value, err = strconv.ParseBool(tag)
+	default:
+		return
+	}
+	if err == nil {
+		property["default"] = value
+	}
+}
+
+// applyXMLTag documents the XML representation set by an `xml` struct tag:
+// the element name, attributes (xml:"id,attr") and wrapped arrays
+// (xml:"tags>tag", a <tags> element holding one <tag> per item).
+func applyXMLTag(property map[string]interface{}, tag string) {
+	if tag == "" || tag == "-" {
+		return
+	}
+
+	name, options, _ := strings.Cut(tag, ",")
+	xml := make(map[string]interface{})
+	if wrapper, element, ok := strings.Cut(name, ">"); ok && property["type"] == "array" {
+		xml["name"] = wrapper
+		xml["wrapped"] = true
+		if items, ok := property["items"].(map[string]interface{}); ok && element != "" {
+			items = maps.Clone(items)
+			items["xml"] = map[string]interface{}{"name": element[strings.LastIndex(element, ">")+1:]}
+			property["items"] = wrapRefSiblings(items)
+		}
+	} else if name != "" {
+		xml["name"] = name[strings.LastIndex(name, ">")+1:]
+	}
+	if slices.Contains(strings.Split(options, ","), "attr") {
+		xml["attribute"] = true
+	}
+
+	if len(xml) > 0 {
+		property["xml"] = xml
+	}
+}
+
+// Properties conventionally set by the server, documented readOnly, and
+// secrets only ever sent by clients, documented writeOnly. The
+// openapi:"readonly" and openapi:"writeonly" tags mark any other field.
+var (
+	readOnlyProperties  = []string{"id", "created_at", "updated_at"}
+	writeOnlyProperties = []string{"password"}
+)
+
+// applyAccessConvention marks a property readOnly or writeOnly by name,
+// unless a tag already set its access.
+func applyAccessConvention(property map[string]interface{}, name string) {
+	if property["readOnly"] != nil || property["writeOnly"] != nil {
+		return
+	}
+	switch {
+	case slices.Contains(readOnlyProperties, name):
+		property["readOnly"] = true
+	case slices.Contains(writeOnlyProperties, name):
+		property["writeOnly"] = true
+	}
+}
+
+// wrapRefSiblings moves the keywords set next to a $ref into an allOf
+// wrapper, since $ref siblings are ignored in OpenAPI 3.0.
+func wrapRefSiblings(property map[string]interface{}) map[string]interface{} {
+	ref, ok := property["$ref"]
+	if !ok || len(property) == 1 {
+		return property
+	}
+	wrapped := map[string]interface{}{"allOf": []interface{}{map[string]interface{}{"$ref": ref}}}
+	for key, value := range property {
+		if key != "$ref" {
+			wrapped[key] = value
+		}
+	}
+	return wrapped
+}
+
+// stripReadOnlyProperties removes the readOnly properties of an object
+// schema, along with their required entry, for request bodies. The inline
+// members of an allOf composition are stripped too; referenced components are
+// shared with the responses and keep theirs, which OpenAPI already leaves out
+// of requests.
+func stripReadOnlyProperties(schema map[string]interface{}) {
+	members, _ := schema["allOf"].([]interface{})
+	for _, member := range members {
+		if member, ok := member.(map[string]interface{}); ok {
+			stripReadOnlyProperties(member)
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, property := range properties {
+		if readOnly, _ := asSchema(property)["readOnly"].(bool); readOnly {
+			delete(properties, name)
+		}
+	}
+
+	required, ok := schema["required"].([]string)
+	if !ok {
+		return
+	}
+	kept := required[:0]
+	for _, name := range required {
+		if _, ok := properties[name]; ok {
+			kept = append(kept, name)
+		}
+	}
+	if len(kept) == 0 {
+		delete(schema, "required")
+	} else {
+		schema["required"] = kept
+	}
+}
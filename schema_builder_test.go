@@ -2,6 +2,7 @@ package openapi
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -22,15 +23,13 @@ func TestBuildSchemaPropertiesFromDTO(t *testing.T) {
 				"id": map[string]interface{}{
 					"type":     "integer",
 					"format":   "int64",
-					"nullable": false,
+					"readOnly": true,
 				},
 				"name": map[string]interface{}{
-					"type":     "string",
-					"nullable": false,
+					"type": "string",
 				},
 				"email": map[string]interface{}{
-					"type":     "string",
-					"nullable": false,
+					"type": "string",
 				},
 			},
 		},
@@ -60,12 +59,10 @@ func TestBuildSchemaPropertiesFromDTO(t *testing.T) {
 			},
 			want: map[string]interface{}{
 				"username": map[string]interface{}{
-					"type":     "string",
-					"nullable": false,
+					"type": "string",
 				},
 				"active": map[string]interface{}{
-					"type":     "boolean",
-					"nullable": false,
+					"type": "boolean",
 				},
 			},
 		},
@@ -79,12 +76,13 @@ func TestBuildSchemaPropertiesFromDTO(t *testing.T) {
 				"created_at": map[string]interface{}{
 					"type":     "string",
 					"format":   "date-time",
-					"nullable": false,
+					"readOnly": true,
 				},
 				"updated_at": map[string]interface{}{
 					"type":     "string",
 					"format":   "date-time",
 					"nullable": true,
+					"readOnly": true,
 				},
 			},
 		},
@@ -100,20 +98,17 @@ func TestBuildSchemaPropertiesFromDTO(t *testing.T) {
 				"id": map[string]interface{}{
 					"type":     "integer",
 					"format":   "int64",
-					"nullable": false,
+					"readOnly": true,
 				},
 				"price": map[string]interface{}{
-					"type":     "number",
-					"format":   "double",
-					"nullable": false,
+					"type":   "number",
+					"format": "double",
 				},
 				"available": map[string]interface{}{
-					"type":     "boolean",
-					"nullable": false,
+					"type": "boolean",
 				},
 				"metadata": map[string]interface{}{
-					"type":     "object",
-					"nullable": false,
+					"type": "object",
 				},
 			},
 		},
@@ -126,7 +121,7 @@ func TestBuildSchemaPropertiesFromDTO(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildSchemaPropertiesFromDTO(tt.fields)
+			got := buildSchemaPropertiesFromDTO(tt.fields, GeneratorConfig{})
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("buildSchemaPropertiesFromDTO() = %v, want %v", got, tt.want)
 			}
@@ -206,7 +201,7 @@ func TestGetRequiredFieldsFromDTO(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getRequiredFieldsFromDTO(tt.fields)
+			got := getRequiredFieldsFromDTO(tt.fields, GeneratorConfig{})
 			// Handle nil vs empty slice comparison
 			if len(got) == 0 && len(tt.want) == 0 {
 				return
@@ -217,3 +212,1016 @@ func TestGetRequiredFieldsFromDTO(t *testing.T) {
 		})
 	}
 }
+
+func TestHideSchemaFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		hidden []string
+		want   map[string]interface{}
+	}{
+		{
+			name:   "exact name removed from properties and required",
+			hidden: []string{"internalNotes"},
+			want: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name":       map[string]interface{}{"type": "string"},
+					"secretHash": map[string]interface{}{"type": "string"},
+				},
+				"required": []string{"name", "secretHash"},
+			},
+		},
+		{
+			name:   "pattern matches several fields",
+			hidden: []string{"internal*", "secret*"},
+			want: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name": map[string]interface{}{"type": "string"},
+				},
+				"required": []string{"name"},
+			},
+		},
+		{
+			name:   "required dropped when emptied",
+			hidden: []string{"*"},
+			want: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name":          map[string]interface{}{"type": "string"},
+					"internalNotes": map[string]interface{}{"type": "string"},
+					"secretHash":    map[string]interface{}{"type": "string"},
+				},
+				"required": []string{"name", "internalNotes", "secretHash"},
+			}
+
+			hideSchemaFields(schema, tt.hidden)
+			if !reflect.DeepEqual(schema, tt.want) {
+				t.Errorf("hideSchemaFields() = %v, want %v", schema, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_ArrayOfInlineStruct(t *testing.T) {
+	fields := []structField{
+		{
+			Name:    "Lines",
+			Type:    "[]struct",
+			JSONTag: "lines",
+			Fields: []structField{
+				{Name: "Qty", Type: "int", JSONTag: "qty"},
+				{Name: "SKU", Type: "string", JSONTag: "sku"},
+				{Name: "Note", Type: "string", JSONTag: "note", IsPointer: true},
+			},
+		},
+		{Name: "Tags", Type: "[]string", JSONTag: "tags"},
+	}
+
+	want := map[string]interface{}{
+		"lines": map[string]interface{}{
+			"type": "array",
+			"items": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"qty":  map[string]interface{}{"type": "integer", "format": "int32"},
+					"sku":  map[string]interface{}{"type": "string"},
+					"note": map[string]interface{}{"type": "string", "nullable": true},
+				},
+				"required": []string{"qty", "sku"},
+			},
+		},
+		"tags": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"type": "string"},
+		},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, GeneratorConfig{})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildSchemaPropertiesFromDTO() = %v, want %v", got, want)
+	}
+}
+
+func TestParseOpenAPITag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want map[string]string
+	}{
+		{tag: "unit=grams", want: map[string]string{"unit": "grams"}},
+		{tag: "unit=cents, flag", want: map[string]string{"unit": "cents", "flag": ""}},
+		{tag: "", want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := parseOpenAPITag(tt.tag); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseOpenAPITag(%q) = %v, want %v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_Unit(t *testing.T) {
+	fields := []structField{
+		{Name: "WeightGrams", Type: "int", JSONTag: "weightGrams", OpenAPITag: "unit=grams"},
+		{Name: "Name", Type: "string", JSONTag: "name"},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, GeneratorConfig{})
+
+	weight := got["weightGrams"].(map[string]interface{})
+	if weight["x-unit"] != "grams" {
+		t.Errorf("weightGrams x-unit = %v, want grams", weight["x-unit"])
+	}
+	if _, ok := got["name"].(map[string]interface{})["x-unit"]; ok {
+		t.Error("name should not carry x-unit")
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_Description(t *testing.T) {
+	cfg := GeneratorConfig{dtoRefs: map[string]string{"AddressDTO": "Address"}}
+	fields := []structField{
+		{Name: "Email", Type: "string", JSONTag: "email", DescriptionTag: "Sign-in email"},
+		{Name: "Address", Type: "AddressDTO", JSONTag: "address", DescriptionTag: "Shipping address"},
+		{Name: "Name", Type: "string", JSONTag: "name"},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, cfg)
+
+	if description := got["email"].(map[string]interface{})["description"]; description != "Sign-in email" {
+		t.Errorf("email description = %v, want Sign-in email", description)
+	}
+	wantAddress := map[string]interface{}{
+		"allOf":       []interface{}{map[string]interface{}{"$ref": "#/components/schemas/Address"}},
+		"description": "Shipping address",
+	}
+	if !reflect.DeepEqual(got["address"], wantAddress) {
+		t.Errorf("address = %v, want %v", got["address"], wantAddress)
+	}
+	if _, ok := got["name"].(map[string]interface{})["description"]; ok {
+		t.Error("name should not carry a description")
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_AccessMode(t *testing.T) {
+	cfg := GeneratorConfig{dtoRefs: map[string]string{"UserDTO": "User"}}
+	fields := []structField{
+		{Name: "ID", Type: "int64", JSONTag: "id"},
+		{Name: "Password", Type: "string", JSONTag: "password"},
+		{Name: "Slug", Type: "string", JSONTag: "slug", OpenAPITag: "readonly"},
+		{Name: "Token", Type: "string", JSONTag: "token", OpenAPITag: "writeonly"},
+		{Name: "CreatedAt", Type: "time.Time", JSONTag: "created_at", OpenAPITag: "writeonly"},
+		{Name: "Owner", Type: "UserDTO", JSONTag: "owner", OpenAPITag: "readonly"},
+		{Name: "Name", Type: "string", JSONTag: "name"},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, cfg)
+
+	tests := []struct {
+		property      string
+		wantReadOnly  bool
+		wantWriteOnly bool
+	}{
+		{property: "id", wantReadOnly: true},
+		{property: "password", wantWriteOnly: true},
+		{property: "slug", wantReadOnly: true},
+		{property: "token", wantWriteOnly: true},
+		{property: "created_at", wantWriteOnly: true},
+		{property: "owner", wantReadOnly: true},
+		{property: "name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.property, func(t *testing.T) {
+			prop := got[tt.property].(map[string]interface{})
+			if readOnly, _ := prop["readOnly"].(bool); readOnly != tt.wantReadOnly {
+				t.Errorf("readOnly = %v, want %v", readOnly, tt.wantReadOnly)
+			}
+			if writeOnly, _ := prop["writeOnly"].(bool); writeOnly != tt.wantWriteOnly {
+				t.Errorf("writeOnly = %v, want %v", writeOnly, tt.wantWriteOnly)
+			}
+		})
+	}
+
+	wantOwner := map[string]interface{}{
+		"allOf":    []interface{}{map[string]interface{}{"$ref": "#/components/schemas/User"}},
+		"readOnly": true,
+	}
+	if !reflect.DeepEqual(got["owner"], wantOwner) {
+		t.Errorf("owner = %v, want %v", got["owner"], wantOwner)
+	}
+}
+
+func TestStripReadOnlyProperties(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"id":       map[string]interface{}{"type": "integer", "readOnly": true},
+			"slug":     map[string]interface{}{"type": "string", "readOnly": true},
+			"password": map[string]interface{}{"type": "string", "writeOnly": true},
+		},
+		"required": []string{"slug", "password"},
+	}
+
+	stripReadOnlyProperties(schema)
+
+	want := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"password": map[string]interface{}{"type": "string", "writeOnly": true},
+		},
+		"required": []string{"password"},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Errorf("stripReadOnlyProperties() = %v, want %v", schema, want)
+	}
+}
+
+func TestStripReadOnlyProperties_AllOf(t *testing.T) {
+	schema := map[string]interface{}{
+		"allOf": []interface{}{
+			map[string]interface{}{"$ref": "#/components/schemas/BaseModel"},
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"slug":  map[string]interface{}{"type": "string", "readOnly": true},
+					"title": map[string]interface{}{"type": "string"},
+				},
+				"required": []string{"slug", "title"},
+			},
+		},
+	}
+
+	stripReadOnlyProperties(schema)
+
+	want := map[string]interface{}{
+		"allOf": []interface{}{
+			map[string]interface{}{"$ref": "#/components/schemas/BaseModel"},
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"title": map[string]interface{}{"type": "string"},
+				},
+				"required": []string{"title"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(schema, want) {
+		t.Errorf("stripReadOnlyProperties() = %v, want %v", schema, want)
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_Deprecated(t *testing.T) {
+	fields := []structField{
+		{Name: "Nickname", Type: "string", JSONTag: "nickname", Deprecated: true},
+		{Name: "Name", Type: "string", JSONTag: "name"},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, GeneratorConfig{})
+
+	if deprecated := got["nickname"].(map[string]interface{})["deprecated"]; deprecated != true {
+		t.Errorf("nickname deprecated = %v, want true", deprecated)
+	}
+	if _, ok := got["name"].(map[string]interface{})["deprecated"]; ok {
+		t.Error("name should not be deprecated")
+	}
+}
+
+func TestApplyDefaultTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		property map[string]interface{}
+		tag      string
+		want     interface{}
+	}{
+		{name: "string", property: map[string]interface{}{"type": "string"}, tag: "draft", want: "draft"},
+		{name: "integer", property: map[string]interface{}{"type": "integer"}, tag: "20", want: int64(20)},
+		{name: "number", property: map[string]interface{}{"type": "number"}, tag: "0.5", want: 0.5},
+		{name: "boolean", property: map[string]interface{}{"type": "boolean"}, tag: "true", want: true},
+		{name: "invalid integer", property: map[string]interface{}{"type": "integer"}, tag: "many"},
+		{name: "array", property: map[string]interface{}{"type": "array"}, tag: "a,b"},
+		{name: "no tag", property: map[string]interface{}{"type": "string"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applyDefaultTag(tt.property, tt.tag)
+			if got := tt.property["default"]; got != tt.want {
+				t.Errorf("default = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnionTag(t *testing.T) {
+	tests := []struct {
+		name        string
+		oneOf       string
+		anyOf       string
+		wantKeyword string
+		wantMembers []string
+	}{
+		{name: "none"},
+		{name: "oneOf", oneOf: "CardPaymentDTO, BankPaymentDTO", wantKeyword: "oneOf", wantMembers: []string{"CardPaymentDTO", "BankPaymentDTO"}},
+		{name: "anyOf", anyOf: "string,int64", wantKeyword: "anyOf", wantMembers: []string{"string", "int64"}},
+		{name: "oneOf wins", oneOf: "A", anyOf: "B", wantKeyword: "oneOf", wantMembers: []string{"A"}},
+		{name: "blank members", oneOf: " , "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyword, members := unionTag(tt.oneOf, tt.anyOf)
+			if keyword != tt.wantKeyword || !reflect.DeepEqual(members, tt.wantMembers) {
+				t.Errorf("unionTag(%q, %q) = %q, %v, want %q, %v", tt.oneOf, tt.anyOf, keyword, members, tt.wantKeyword, tt.wantMembers)
+			}
+		})
+	}
+}
+
+func TestUnionSchema(t *testing.T) {
+	cfg := GeneratorConfig{dtoRefs: map[string]string{"CardPaymentDTO": "CardPayment"}}
+	card := map[string]interface{}{"$ref": "#/components/schemas/CardPayment"}
+	text := map[string]interface{}{"type": "string"}
+
+	tests := []struct {
+		name   string
+		goType string
+		want   map[string]interface{}
+	}{
+		{
+			name:   "interface",
+			goType: "interface{}",
+			want:   map[string]interface{}{"oneOf": []interface{}{card, text}},
+		},
+		{
+			name:   "slice",
+			goType: "[]interface{}",
+			want: map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"oneOf": []interface{}{card, text}},
+			},
+		},
+		{
+			name:   "map",
+			goType: "map[string]interface{}",
+			want: map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"oneOf": []interface{}{card, text}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unionSchema(tt.goType, "oneOf", []string{"CardPaymentDTO", "string"}, cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unionSchema(%q) = %v, want %v", tt.goType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyXMLTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		property map[string]interface{}
+		tag      string
+		want     map[string]interface{}
+	}{
+		{
+			name:     "element name",
+			property: map[string]interface{}{"type": "string"},
+			tag:      "full_name",
+			want:     map[string]interface{}{"type": "string", "xml": map[string]interface{}{"name": "full_name"}},
+		},
+		{
+			name:     "attribute",
+			property: map[string]interface{}{"type": "integer"},
+			tag:      "id,attr",
+			want:     map[string]interface{}{"type": "integer", "xml": map[string]interface{}{"name": "id", "attribute": true}},
+		},
+		{
+			name:     "unnamed attribute",
+			property: map[string]interface{}{"type": "string"},
+			tag:      ",attr",
+			want:     map[string]interface{}{"type": "string", "xml": map[string]interface{}{"attribute": true}},
+		},
+		{
+			name:     "wrapped array",
+			property: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			tag:      "tags>tag",
+			want: map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string", "xml": map[string]interface{}{"name": "tag"}},
+				"xml":   map[string]interface{}{"name": "tags", "wrapped": true},
+			},
+		},
+		{
+			name:     "wrapped array of references",
+			property: map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/components/schemas/Line"}},
+			tag:      "lines>line",
+			want: map[string]interface{}{
+				"type": "array",
+				"items": map[string]interface{}{
+					"allOf": []interface{}{map[string]interface{}{"$ref": "#/components/schemas/Line"}},
+					"xml":   map[string]interface{}{"name": "line"},
+				},
+				"xml": map[string]interface{}{"name": "lines", "wrapped": true},
+			},
+		},
+		{
+			name:     "nested element path",
+			property: map[string]interface{}{"type": "string"},
+			tag:      "address>city",
+			want:     map[string]interface{}{"type": "string", "xml": map[string]interface{}{"name": "city"}},
+		},
+		{name: "ignored field", property: map[string]interface{}{"type": "string"}, tag: "-", want: map[string]interface{}{"type": "string"}},
+		{name: "chardata", property: map[string]interface{}{"type": "string"}, tag: ",chardata", want: map[string]interface{}{"type": "string"}},
+		{name: "no tag", property: map[string]interface{}{"type": "string"}, want: map[string]interface{}{"type": "string"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applyXMLTag(tt.property, tt.tag)
+			if !reflect.DeepEqual(tt.property, tt.want) {
+				t.Errorf("applyXMLTag(%q) = %v, want %v", tt.tag, tt.property, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_TypeOverride(t *testing.T) {
+	cfg := GeneratorConfig{dtoRefs: map[string]string{"MoneyDTO": "Money"}}
+	fields := []structField{
+		{Name: "Email", Type: "string", JSONTag: "email", OpenAPITag: "format=email"},
+		{Name: "ID", Type: "UserID", JSONTag: "user_id", OpenAPITag: "type=string,format=uuid"},
+		{Name: "Price", Type: "MoneyDTO", JSONTag: "price", OpenAPITag: "type=string", IsPointer: true},
+		{Name: "Avatar", Type: "[]byte", JSONTag: "avatar", OpenAPITag: "format=base64"},
+		{Name: "Count", Type: "int64", JSONTag: "count", OpenAPITag: "type=integer"},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, cfg)
+
+	want := map[string]interface{}{
+		"email":   map[string]interface{}{"type": "string", "format": "email"},
+		"user_id": map[string]interface{}{"type": "string", "format": "uuid"},
+		"price":   map[string]interface{}{"type": "string", "nullable": true},
+		"avatar":  map[string]interface{}{"type": "string", "format": "base64"},
+		"count":   map[string]interface{}{"type": "integer", "format": "int64"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildSchemaPropertiesFromDTO() = %v, want %v", got, want)
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_FilterableSortable(t *testing.T) {
+	fields := []structField{
+		{Name: "Status", Type: "string", JSONTag: "status", OpenAPITag: "filterable,sortable"},
+		{Name: "CreatedAt", Type: "time.Time", JSONTag: "createdAt", OpenAPITag: "sortable"},
+		{Name: "Notes", Type: "string", JSONTag: "notes"},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, GeneratorConfig{})
+
+	tests := []struct {
+		field          string
+		wantFilterable bool
+		wantSortable   bool
+	}{
+		{field: "status", wantFilterable: true, wantSortable: true},
+		{field: "createdAt", wantFilterable: false, wantSortable: true},
+		{field: "notes", wantFilterable: false, wantSortable: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			prop := got[tt.field].(map[string]interface{})
+			if _, ok := prop["x-filterable"]; ok != tt.wantFilterable {
+				t.Errorf("%s x-filterable present = %v, want %v", tt.field, ok, tt.wantFilterable)
+			}
+			if _, ok := prop["x-sortable"]; ok != tt.wantSortable {
+				t.Errorf("%s x-sortable present = %v, want %v", tt.field, ok, tt.wantSortable)
+			}
+		})
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_Internal(t *testing.T) {
+	fields := []structField{
+		{Name: "Notes", Type: "string", JSONTag: "notes", OpenAPITag: "internal"},
+		{Name: "Name", Type: "string", JSONTag: "name"},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, GeneratorConfig{})
+
+	if got["notes"].(map[string]interface{})["x-internal"] != true {
+		t.Error("notes should carry x-internal")
+	}
+	if _, ok := got["name"].(map[string]interface{})["x-internal"]; ok {
+		t.Error("name should not carry x-internal")
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_InterfaceSchema(t *testing.T) {
+	fields := []structField{
+		{Name: "Value", Type: "interface{}", JSONTag: "value"},
+		{Name: "Meta", Type: "any", JSONTag: "meta", IsPointer: true},
+		{Name: "Items", Type: "[]interface{}", JSONTag: "items"},
+	}
+
+	tests := []struct {
+		name      string
+		mode      string
+		wantValue map[string]interface{}
+		wantMeta  map[string]interface{}
+		wantItems map[string]interface{}
+	}{
+		{
+			name:      "object by default",
+			mode:      "",
+			wantValue: map[string]interface{}{"type": "object"},
+			wantMeta:  map[string]interface{}{"type": "object", "nullable": true},
+			wantItems: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}},
+		},
+		{
+			name:      "explicit object",
+			mode:      InterfaceSchemaObject,
+			wantValue: map[string]interface{}{"type": "object"},
+			wantMeta:  map[string]interface{}{"type": "object", "nullable": true},
+			wantItems: map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "object"}},
+		},
+		{
+			name:      "open schema",
+			mode:      InterfaceSchemaAny,
+			wantValue: map[string]interface{}{},
+			wantMeta:  map[string]interface{}{},
+			wantItems: map[string]interface{}{"type": "array", "items": map[string]interface{}{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildSchemaPropertiesFromDTO(fields, GeneratorConfig{InterfaceSchema: tt.mode})
+
+			if !reflect.DeepEqual(got["value"], tt.wantValue) {
+				t.Errorf("value = %v, want %v", got["value"], tt.wantValue)
+			}
+			if !reflect.DeepEqual(got["meta"], tt.wantMeta) {
+				t.Errorf("meta = %v, want %v", got["meta"], tt.wantMeta)
+			}
+			if !reflect.DeepEqual(got["items"], tt.wantItems) {
+				t.Errorf("items = %v, want %v", got["items"], tt.wantItems)
+			}
+		})
+	}
+}
+
+func TestGetRequiredFieldsFromDTO_BooleansOptional(t *testing.T) {
+	fields := []structField{
+		{Name: "Name", Type: "string", JSONTag: "name"},
+		{Name: "Active", Type: "bool", JSONTag: "active"},
+		{Name: "Accepted", Type: "bool", JSONTag: "accepted", ValidateTag: "required"},
+	}
+
+	tests := []struct {
+		name string
+		cfg  GeneratorConfig
+		want []string
+	}{
+		{
+			name: "booleans required by default",
+			cfg:  GeneratorConfig{},
+			want: []string{"name", "active", "accepted"},
+		},
+		{
+			name: "booleans optional unless explicitly required",
+			cfg:  GeneratorConfig{BooleansOptional: true},
+			want: []string{"name", "accepted"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getRequiredFieldsFromDTO(fields, tt.cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getRequiredFieldsFromDTO() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_DTORefs(t *testing.T) {
+	fields := []structField{
+		{Name: "Author", Type: "AuthorDTO", JSONTag: "author"},
+		{Name: "Editor", Type: "AuthorDTO", JSONTag: "editor", IsPointer: true},
+		{Name: "Reviewers", Type: "[]AuthorDTO", JSONTag: "reviewers"},
+		{Name: "Label", Type: "LabelDTO", JSONTag: "label"},
+	}
+	ref := map[string]interface{}{"$ref": "#/components/schemas/Author"}
+
+	want := map[string]interface{}{
+		"author": ref,
+		"editor": map[string]interface{}{
+			"allOf":    []interface{}{ref},
+			"nullable": true,
+		},
+		"reviewers": map[string]interface{}{"type": "array", "items": ref},
+		// Unknown types keep the scalar fallback
+		"label": map[string]interface{}{"type": "string"},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, GeneratorConfig{dtoRefs: map[string]string{"AuthorDTO": "Author"}})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildSchemaPropertiesFromDTO() = %v, want %v", got, want)
+	}
+}
+
+func TestNestedDTOSchemas(t *testing.T) {
+	resources := map[string]resourceDTOs{
+		"post": {Name: "post", DTOs: map[string]dtoSchema{
+			"PostDTO": {Name: "PostDTO", Fields: []structField{
+				{Name: "Author", Type: "AuthorDTO"},
+				{Name: "Owner", Type: "UserDTO"},
+				{Name: "Meta", Type: "struct", Fields: []structField{{Name: "Tags", Type: "[]TagDTO"}}},
+			}},
+			"AuthorDTO": {Name: "AuthorDTO"},
+			"TagDTO":    {Name: "TagDTO"},
+		}},
+		"user": {Name: "user", DTOs: map[string]dtoSchema{"UserDTO": {Name: "UserDTO"}}},
+		"tag":  {Name: "tag", DTOs: map[string]dtoSchema{"LabelDTO": {Name: "LabelDTO"}}},
+	}
+
+	refs, schemas, err := nestedDTOSchemas(resources, map[string]string{"post": "PostDTO"}, dtoVariantPatterns(nil), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// TagDTO would be named Tag, which the tag resource schema already uses
+	wantRefs := map[string]string{"AuthorDTO": "Author", "UserDTO": "User", "TagDTO": "TagDTO"}
+	if !reflect.DeepEqual(refs, wantRefs) {
+		t.Errorf("refs = %v, want %v", refs, wantRefs)
+	}
+	if len(schemas) != 2 || schemas["Author"].Name != "AuthorDTO" || schemas["TagDTO"].Name != "TagDTO" {
+		t.Errorf("schemas = %v, want Author and TagDTO", schemas)
+	}
+}
+
+func TestNestedDTOSchemas_ReservedNames(t *testing.T) {
+	resources := map[string]resourceDTOs{
+		"user": {Name: "user", DTOs: map[string]dtoSchema{
+			"UserDTO": {Name: "UserDTO", Fields: []structField{
+				{Name: "Problem", Type: "ErrorDTO"},
+				{Name: "Summary", Type: "UserListItemDTO"},
+				{Name: "Draft", Type: "CreateUserRequestDTO"},
+				{Name: "Patch", Type: "UpdateUserRequestDTO"},
+			}},
+			"ErrorDTO":             {Name: "ErrorDTO"},
+			"UserListItemDTO":      {Name: "UserListItemDTO"},
+			"CreateUserRequestDTO": {Name: "CreateUserRequestDTO"},
+			"UpdateUserRequestDTO": {Name: "UpdateUserRequestDTO"},
+		}},
+	}
+
+	refs, _, err := nestedDTOSchemas(resources, map[string]string{"user": "UserDTO"}, dtoVariantPatterns(nil), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"ErrorDTO":             "ErrorDTO",
+		"UserListItemDTO":      "UserListItemDTO",
+		"CreateUserRequestDTO": "CreateUserRequestDTO",
+		"UpdateUserRequestDTO": "UpdateUserRequestDTO",
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Errorf("refs = %v, want %v", refs, want)
+	}
+}
+
+func TestNestedDTOSchemas_Duplicates(t *testing.T) {
+	address := func(fields ...string) dtoSchema {
+		dto := dtoSchema{Name: "AddressDTO"}
+		for _, field := range fields {
+			dto.Fields = append(dto.Fields, structField{Name: field, Type: "string"})
+		}
+		return dto
+	}
+	resources := func(billing, shipping dtoSchema) map[string]resourceDTOs {
+		return map[string]resourceDTOs{
+			"invoice": {Name: "invoice", DTOs: map[string]dtoSchema{
+				"InvoiceDTO": {Name: "InvoiceDTO", Fields: []structField{{Name: "Address", Type: "AddressDTO"}}},
+				"AddressDTO": billing,
+			}},
+			"order": {Name: "order", DTOs: map[string]dtoSchema{
+				"OrderDTO":   {Name: "OrderDTO", Fields: []structField{{Name: "Address", Type: "AddressDTO"}}},
+				"AddressDTO": shipping,
+			}},
+		}
+	}
+
+	mainDTOs := map[string]string{"invoice": "InvoiceDTO", "order": "OrderDTO"}
+
+	// identical declarations are the same schema
+	refs, schemas, err := nestedDTOSchemas(resources(address("City"), address("City")), mainDTOs, dtoVariantPatterns(nil), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refs["AddressDTO"] != "Address" || len(schemas) != 1 {
+		t.Errorf("refs = %v, schemas = %v, want a single Address schema", refs, schemas)
+	}
+
+	for i := 0; i < 10; i++ {
+		_, _, err := nestedDTOSchemas(resources(address("City"), address("Street")), mainDTOs, dtoVariantPatterns(nil), nil, false)
+		if err == nil || !strings.Contains(err.Error(), "nested DTO AddressDTO is declared differently by resources invoice, order") {
+			t.Fatalf("err = %v, want the AddressDTO conflict", err)
+		}
+	}
+}
+
+func TestFieldTypeSchema_Maps(t *testing.T) {
+	cfg := GeneratorConfig{dtoRefs: map[string]string{"AddressDTO": "Address"}}
+
+	tests := []struct {
+		goType string
+		want   map[string]interface{}
+	}{
+		{
+			goType: "map[string]interface{}",
+			want:   map[string]interface{}{"type": "object"},
+		},
+		{
+			goType: "map[string]int64",
+			want: map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "integer", "format": "int64"},
+			},
+		},
+		{
+			goType: "map[string]AddressDTO",
+			want: map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"$ref": "#/components/schemas/Address"},
+			},
+		},
+		{
+			goType: "map[string][]string",
+			want: map[string]interface{}{
+				"type": "object",
+				"additionalProperties": map[string]interface{}{
+					"type":  "array",
+					"items": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			if got := fieldTypeSchema(tt.goType, nil, cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fieldTypeSchema(%q) = %v, want %v", tt.goType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldTypeSchema_LibraryTypes(t *testing.T) {
+	tests := []struct {
+		goType string
+		cfg    GeneratorConfig
+		want   map[string]interface{}
+	}{
+		{goType: "uuid.UUID", want: map[string]interface{}{"type": "string", "format": "uuid"}},
+		{goType: "[]byte", want: map[string]interface{}{"type": "string", "format": "byte"}},
+		{goType: "json.RawMessage", want: map[string]interface{}{"type": "object"}},
+		{goType: "json.RawMessage", cfg: GeneratorConfig{InterfaceSchema: InterfaceSchemaAny}, want: map[string]interface{}{}},
+		{goType: "sql.NullString", want: map[string]interface{}{"type": "string", "nullable": true}},
+		{goType: "pgtype.Date", want: map[string]interface{}{"type": "string", "format": "date", "nullable": true}},
+		{
+			goType: "decimal.Decimal",
+			cfg:    GeneratorConfig{TypeFormats: map[string]string{"decimal.Decimal": "money"}},
+			want:   map[string]interface{}{"type": "string", "format": "money"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			if got := fieldTypeSchema(tt.goType, nil, tt.cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fieldTypeSchema(%q) = %v, want %v", tt.goType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldTypeSchema_NamedTypes(t *testing.T) {
+	cfg := GeneratorConfig{
+		namedTypes: map[string]namedType{
+			"Email":  {Underlying: "string"},
+			"Status": {Underlying: "int"},
+			"Amount": {Underlying: "Cents"},
+			"Cents":  {Underlying: "int64"},
+			"Tags":   {Underlying: "[]Email"},
+			"Tree":   {Underlying: "[]Tree"},
+		},
+		TypeFormats: map[string]string{"Email": "email", "uuid.UUID": "uuid"},
+	}
+
+	tests := []struct {
+		goType string
+		want   map[string]interface{}
+	}{
+		{
+			goType: "Email",
+			want:   map[string]interface{}{"type": "string", "format": "email"},
+		},
+		{
+			goType: "Status",
+			want:   map[string]interface{}{"type": "integer", "format": "int32"},
+		},
+		{
+			goType: "Amount",
+			want:   map[string]interface{}{"type": "integer", "format": "int64"},
+		},
+		{
+			goType: "Tags",
+			want: map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string", "format": "email"},
+			},
+		},
+		{
+			goType: "Tree",
+			want: map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		},
+		{
+			goType: "uuid.UUID",
+			want:   map[string]interface{}{"type": "string", "format": "uuid"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			if got := fieldTypeSchema(tt.goType, nil, cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fieldTypeSchema(%q) = %v, want %v", tt.goType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_Enums(t *testing.T) {
+	cfg := GeneratorConfig{
+		namedTypes: map[string]namedType{
+			"Status": {Underlying: "string", Enum: []interface{}{"active", "archived"}},
+		},
+	}
+	fields := []structField{
+		{Name: "Status", Type: "Status", JSONTag: "status"},
+		{Name: "Previous", Type: "Status", JSONTag: "previous", IsPointer: true},
+		{Name: "History", Type: "[]Status", JSONTag: "history"},
+	}
+
+	properties := buildSchemaPropertiesFromDTO(fields, cfg)
+
+	status := map[string]interface{}{"type": "string", "enum": []interface{}{"active", "archived"}}
+	if !reflect.DeepEqual(properties["status"], status) {
+		t.Errorf("status = %v, want %v", properties["status"], status)
+	}
+	previous := map[string]interface{}{"type": "string", "enum": []interface{}{"active", "archived", nil}, "nullable": true}
+	if !reflect.DeepEqual(properties["previous"], previous) {
+		t.Errorf("previous = %v, want %v", properties["previous"], previous)
+	}
+	if items := properties["history"].(map[string]interface{})["items"]; !reflect.DeepEqual(items, status) {
+		t.Errorf("history items = %v, want %v", items, status)
+	}
+	if enum := cfg.namedTypes["Status"].Enum; len(enum) != 2 {
+		t.Errorf("named type enum modified: %v", enum)
+	}
+}
+
+func TestElementType(t *testing.T) {
+	tests := map[string]string{
+		"AddressDTO":                "AddressDTO",
+		"[]AddressDTO":              "AddressDTO",
+		"map[string]AddressDTO":     "AddressDTO",
+		"[]map[string][]AddressDTO": "AddressDTO",
+		"map[string]interface{}":    "interface{}",
+	}
+	for goType, want := range tests {
+		if got := elementType(goType); got != want {
+			t.Errorf("elementType(%q) = %q, want %q", goType, got, want)
+		}
+	}
+}
+
+func TestBuildSchemaFromDTO_Embedded(t *testing.T) {
+	base := structField{Name: "Base", Type: "Base", Embedded: true, Fields: []structField{
+		{Name: "ID", Type: "int64", JSONTag: "id"},
+		{Name: "Name", Type: "int", JSONTag: "name"},
+		{Name: "Audit", Type: "Audit", Embedded: true, Fields: []structField{
+			{Name: "CreatedBy", Type: "string", JSONTag: "created_by"},
+		}},
+	}}
+	fields := []structField{
+		base,
+		{Name: "Name", Type: "string", JSONTag: "name"},
+	}
+
+	t.Run("flatten", func(t *testing.T) {
+		want := map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"id":         map[string]interface{}{"type": "integer", "format": "int64", "readOnly": true},
+				"name":       map[string]interface{}{"type": "string"},
+				"created_by": map[string]interface{}{"type": "string"},
+			},
+			"required": []string{"name", "created_by"},
+		}
+		if got := buildSchemaFromDTO(fields, GeneratorConfig{}); !reflect.DeepEqual(got, want) {
+			t.Errorf("buildSchemaFromDTO() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("allOf", func(t *testing.T) {
+		cfg := GeneratorConfig{EmbeddedStructs: EmbeddedAllOf, dtoRefs: map[string]string{"Base": "Base"}}
+		want := map[string]interface{}{
+			"allOf": []interface{}{
+				map[string]interface{}{"$ref": "#/components/schemas/Base"},
+				map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name": map[string]interface{}{"type": "string"},
+					},
+					"required": []string{"name"},
+				},
+			},
+		}
+		if got := buildSchemaFromDTO(fields, cfg); !reflect.DeepEqual(got, want) {
+			t.Errorf("buildSchemaFromDTO() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestBuildSchemaPropertiesFromDTO_EnumLabels(t *testing.T) {
+	cfg := GeneratorConfig{namedTypes: map[string]namedType{
+		"Priority": {Underlying: "int", Enum: []interface{}{1, 2}},
+	}}
+	fields := []structField{
+		{Name: "Status", Type: "string", JSONTag: "status", EnumLabelsTag: "draft=Draft,live=Published"},
+		{Name: "Priority", Type: "Priority", JSONTag: "priority", IsPointer: true, EnumLabelsTag: "1=Low,2=High"},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, cfg)
+
+	want := map[string]interface{}{
+		"status": map[string]interface{}{
+			"type":                "string",
+			"enum":                []string{"draft", "live"},
+			"x-enum-descriptions": []string{"Draft", "Published"},
+		},
+		"priority": map[string]interface{}{
+			"type":                "integer",
+			"format":              "int32",
+			"nullable":            true,
+			"enum":                []interface{}{1, 2, nil},
+			"x-enum-descriptions": []string{"Low", "High", ""},
+		},
+	}
+	for name, prop := range want {
+		if !reflect.DeepEqual(got[name], prop) {
+			t.Errorf("%s = %#v, want %#v", name, got[name], prop)
+		}
+	}
+	if enum := cfg.namedTypes["Priority"].Enum; len(enum) != 2 {
+		t.Errorf("Priority enum = %v, should not be modified", enum)
+	}
+}
+
+func TestBuildSchemaPropertiesFromDTO_ValidateRules(t *testing.T) {
+	fields := []structField{
+		{Name: "Email", Type: "string", JSONTag: "email", ValidateTag: "required,email"},
+		{Name: "Status", Type: "string", JSONTag: "status", ValidateTag: "oneof=draft live"},
+		{Name: "Tags", Type: "[]string", JSONTag: "tags", ValidateTag: "unique"},
+		{Name: "Quantity", Type: "int", JSONTag: "quantity", ValidateTag: "min=1,max=99"},
+	}
+
+	got := buildSchemaPropertiesFromDTO(fields, GeneratorConfig{})
+
+	want := map[string]interface{}{
+		"email":    map[string]interface{}{"type": "string", "format": "email"},
+		"status":   map[string]interface{}{"type": "string", "enum": []string{"draft", "live"}},
+		"tags":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "uniqueItems": true},
+		"quantity": map[string]interface{}{"type": "integer", "format": "int32", "minimum": 1, "maximum": 99},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("buildSchemaPropertiesFromDTO() = %#v, want %#v", got, want)
+	}
+}
@@ -1,68 +1,158 @@
 package openapi
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
 	"maps"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 
+	"github.com/andybalholm/brotli"
 	"github.com/gofiber/fiber/v3"
+	"gopkg.in/yaml.v3"
 )
 
 // maxCachedServerURLs bounds the marshalled-bytes cache. The servers block is
 // the only request-dependent part of the spec and is derived from the Host
 // header, so a hostile client could otherwise grow the cache without limit.
 // Real deployments answer on a handful of hostnames, so once the bound is hit
-// further hosts fall back to marshalling without being cached.
+// further hosts fall back to marshalling without being cached. Each cached
+// host holds its few variants (format, spec version, compression) apart, so
+// compressed and file documents do not use up the bound.
 const maxCachedServerURLs = 32
 
 type specEncoder func(any) ([]byte, error)
 
-// specCache builds the static portion of the OpenAPI spec exactly once and
-// memoises the fully marshalled JSON per server URL, so steady-state requests
-// avoid both regeneration (route/DTO/reflection walks) and re-marshalling.
+// Formats the spec can be served in.
+const (
+	specFormatJSON = "json"
+	specFormatYAML = "yaml"
+)
+
+// specCache builds the static portion of the OpenAPI spec once and memoises
+// the fully marshalled document per format, spec version and server URL, so
+// steady-state requests avoid both regeneration (route/DTO/reflection walks)
+// and re-marshalling. invalidate drops both so the next request rebuilds.
 type specCache struct {
 	build func() (map[string]interface{}, error)
 
-	once      sync.Once
+	buildMu   sync.Mutex
+	built     bool
 	staticDoc map[string]interface{}
 	buildErr  error
 
-	mu       sync.RWMutex
-	byServer map[string][]byte
+	mu sync.RWMutex
+	// byServer holds the marshalled documents by server URL, then by variant
+	// (compression, format and spec version).
+	byServer   map[string]map[string][]byte
+	generation uint64
 }
 
 func newSpecCache(build func() (map[string]interface{}, error)) *specCache {
 	return &specCache{
 		build:    build,
-		byServer: make(map[string][]byte),
+		byServer: make(map[string]map[string][]byte),
 	}
 }
 
 func (c *specCache) static() (map[string]interface{}, error) {
-	c.once.Do(func() {
-		c.staticDoc, c.buildErr = c.build()
-	})
-	return c.staticDoc, c.buildErr
+	doc, _, err := c.staticGeneration()
+	return doc, err
 }
 
-// bytes returns the marshalled spec for the given server URL, generating and
-// caching it on first use. The returned slice is owned by the cache and must
-// not be mutated by callers.
-func (c *specCache) bytes(serverURL string, encode specEncoder) ([]byte, error) {
+// staticGeneration returns the built spec along with the generation it
+// belongs to, read under the same lock invalidate resets them with.
+func (c *specCache) staticGeneration() (map[string]interface{}, uint64, error) {
+	c.buildMu.Lock()
+	defer c.buildMu.Unlock()
+	if !c.built {
+		c.staticDoc, c.buildErr = c.build()
+		c.built = true
+	}
+
 	c.mu.RLock()
-	cached, ok := c.byServer[serverURL]
+	generation := c.generation
 	c.mu.RUnlock()
-	if ok {
+	return c.staticDoc, generation, c.buildErr
+}
+
+// invalidate discards the built spec and every marshalled document. The
+// build is reset before the generation is bumped, both under buildMu, so a
+// document marshalled from the previous build is served but never cached
+// under the new generation.
+func (c *specCache) invalidate() {
+	c.buildMu.Lock()
+	defer c.buildMu.Unlock()
+	c.built, c.staticDoc, c.buildErr = false, nil, nil
+
+	c.mu.Lock()
+	c.byServer = make(map[string]map[string][]byte)
+	c.generation++
+	c.mu.Unlock()
+}
+
+// cached returns the marshalled variant of the spec for serverURL, along with
+// the current generation.
+func (c *specCache) cached(serverURL, variant string) ([]byte, uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	raw, ok := c.byServer[serverURL][variant]
+	return raw, c.generation, ok
+}
+
+// store caches a marshalled variant of the spec for serverURL, unless it was
+// built for an earlier generation or maxCachedServerURLs other server URLs
+// are already cached.
+func (c *specCache) store(serverURL, variant string, raw []byte, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.generation != generation {
+		return
+	}
+	variants, ok := c.byServer[serverURL]
+	if !ok {
+		if len(c.byServer) >= maxCachedServerURLs {
+			return
+		}
+		variants = make(map[string][]byte)
+		c.byServer[serverURL] = variants
+	}
+	variants[variant] = raw
+}
+
+// bytes returns the spec marshalled by encode for the given server URL and
+// normalized spec version, generating and caching it on first use. format
+// names the encoding and keys the cache. The returned slice is owned by the
+// cache and must not be mutated by callers.
+func (c *specCache) bytes(serverURL, version, format string, encode specEncoder) ([]byte, error) {
+	variant := format + " " + version
+	if cached, _, ok := c.cached(serverURL, variant); ok {
 		return cached, nil
 	}
 
-	static, err := c.static()
+	static, generation, err := c.staticGeneration()
 	if err != nil {
 		return nil, err
 	}
 
 	doc := maps.Clone(static)
-	doc["servers"] = []map[string]string{
-		{"url": serverURL, "description": "Development server"},
+	if _, configured := static["servers"]; !configured {
+		doc["servers"] = []map[string]string{
+			{"url": serverURL, "description": "Development server"},
+		}
+	}
+
+	if version == SpecVersion31 {
+		if doc, err = convertToOpenAPI31(doc); err != nil {
+			return nil, err
+		}
 	}
 
 	raw, err := encode(doc)
@@ -70,16 +160,188 @@ func (c *specCache) bytes(serverURL string, encode specEncoder) ([]byte, error)
 		return nil, err
 	}
 
-	c.mu.Lock()
-	if len(c.byServer) < maxCachedServerURLs {
-		c.byServer[serverURL] = raw
+	c.store(serverURL, variant, raw, generation)
+	return raw, nil
+}
+
+const (
+	jsonContentType = "application/json; charset=utf-8"
+	yamlContentType = "application/yaml; charset=utf-8"
+)
+
+// specMediaTypes lists the media types the JSON spec route negotiates through
+// Accept, in server preference order. application/vnd.oai.openapi is the
+// registered OpenAPI type, YAML encoded; its +json suffix selects JSON.
+var specMediaTypes = []struct {
+	mediaType string
+	format    string
+}{
+	{mediaType: "application/json", format: specFormatJSON},
+	{mediaType: "application/vnd.oai.openapi+json", format: specFormatJSON},
+	{mediaType: "application/yaml", format: specFormatYAML},
+	{mediaType: "application/vnd.oai.openapi", format: specFormatYAML},
+	{mediaType: "application/x-yaml", format: specFormatYAML},
+	{mediaType: "text/yaml", format: specFormatYAML},
+}
+
+// negotiateSpecFormat picks the spec format and content type from the
+// request's Accept header. Requests without one, or accepting none of
+// specMediaTypes, get JSON.
+func negotiateSpecFormat(c fiber.Ctx) (format, contentType string) {
+	c.Vary(fiber.HeaderAccept)
+	if c.Get(fiber.HeaderAccept) == "" {
+		return specFormatJSON, jsonContentType
 	}
-	c.mu.Unlock()
 
-	return raw, nil
+	offers := make([]string, len(specMediaTypes))
+	for i, candidate := range specMediaTypes {
+		offers[i] = candidate.mediaType
+	}
+	accepted := c.Accepts(offers...)
+	for _, candidate := range specMediaTypes {
+		if candidate.mediaType == accepted {
+			return candidate.format, candidate.mediaType + "; charset=utf-8"
+		}
+	}
+	return specFormatJSON, jsonContentType
+}
+
+// Content codings the spec is pre-compressed with, in server preference order.
+const (
+	specEncodingBrotli = "br"
+	specEncodingGzip   = "gzip"
+)
+
+// encoded returns the spec like bytes, compressed with contentEncoding
+// (specEncodingBrotli or specEncodingGzip). The compressed document is cached
+// alongside the plain one so it is compressed once per key.
+func (c *specCache) encoded(serverURL, version, format, contentEncoding string, encode specEncoder) ([]byte, error) {
+	variant := contentEncoding + " " + format + " " + version
+	cached, generation, ok := c.cached(serverURL, variant)
+	if ok {
+		return cached, nil
+	}
+
+	raw, err := c.bytes(serverURL, version, format, encode)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := compressSpec(raw, contentEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(serverURL, variant, compressed, generation)
+	return compressed, nil
+}
+
+func compressSpec(raw []byte, contentEncoding string) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch contentEncoding {
+	case specEncodingBrotli:
+		w = brotli.NewWriterLevel(&buf, brotli.BestCompression)
+	case specEncodingGzip:
+		gz, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		w = gz
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", contentEncoding)
+	}
+
+	if _, err := w.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// negotiateSpecEncoding picks the pre-compressed variant accepted by the
+// request's Accept-Encoding, or "" to send the spec uncompressed.
+func negotiateSpecEncoding(c fiber.Ctx) string {
+	if c.Get(fiber.HeaderAcceptEncoding) == "" {
+		return ""
+	}
+	return c.AcceptsEncodings(specEncodingBrotli, specEncodingGzip)
+}
+
+// specETag returns a strong entity tag derived from the marshalled spec.
+func specETag(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value lists etag, using
+// the weak comparison RFC 9110 prescribes for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeCanonicalJSON marshals the spec with encoding/json, which writes map
+// keys in sorted order, so repeated generations are byte-identical whatever
+// JSON encoder the app is configured with. The result is cached, so the
+// stdlib encoder's cost is paid once per cache key.
+func encodeCanonicalJSON(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func encodeIndentedJSON(v any) ([]byte, error) {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(raw, '\n'), nil
+}
+
+// writeSpecFile writes the spec for serverURL to path, as YAML for .yaml/.yml
+// files and as indented JSON otherwise.
+func writeSpecFile(cache *specCache, path, serverURL string) error {
+	format, encode := specFormatJSON, specEncoder(encodeIndentedJSON)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format, encode = specFormatYAML, encodeYAML
+	}
+
+	raw, err := cache.bytes(serverURL, SpecVersion30, format+" file", encode)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0644)
 }
 
-func encoderFrom(c fiber.Ctx) specEncoder {
-	enc := c.App().Config().JSONEncoder
-	return func(v any) ([]byte, error) { return enc(v) }
+// encodeYAML renders the spec as YAML. The document is normalized through JSON
+// first so struct-free maps of any element type marshal alike and keys follow
+// their JSON names.
+func encodeYAML(v any) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
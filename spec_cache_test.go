@@ -0,0 +1,85 @@
+package openapi
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSpecCache_InvalidateDuringBuild(t *testing.T) {
+	var builds atomic.Int64
+	cache := newSpecCache(func() (map[string]interface{}, error) {
+		return map[string]interface{}{"build": builds.Add(1)}, nil
+	})
+	encode := func(v any) ([]byte, error) {
+		return []byte(strconv.FormatInt(v.(map[string]interface{})["build"].(int64), 10)), nil
+	}
+
+	for i := 0; i < 500; i++ {
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := cache.bytes("http://localhost", SpecVersion30, specFormatJSON, encode); err != nil {
+					t.Errorf("bytes() error = %v", err)
+				}
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cache.invalidate()
+		}()
+		wg.Wait()
+
+		raw, err := cache.bytes("http://localhost", SpecVersion30, specFormatJSON, encode)
+		if err != nil {
+			t.Fatalf("bytes() error = %v", err)
+		}
+		static, err := cache.static()
+		if err != nil {
+			t.Fatalf("static() error = %v", err)
+		}
+		if want := strconv.FormatInt(static["build"].(int64), 10); string(raw) != want {
+			t.Fatalf("iteration %d: cached spec from build %s, want the current build %s", i, raw, want)
+		}
+	}
+}
+
+func TestSpecCache_ServerURLBound(t *testing.T) {
+	cache := newSpecCache(func() (map[string]interface{}, error) {
+		return map[string]interface{}{}, nil
+	})
+
+	serve := func(serverURL string) {
+		for _, version := range []string{SpecVersion30, SpecVersion31} {
+			for _, format := range []string{specFormatJSON, specFormatYAML} {
+				if _, err := cache.bytes(serverURL, version, format, encodeCanonicalJSON); err != nil {
+					t.Fatalf("bytes() error = %v", err)
+				}
+				for _, encoding := range []string{specEncodingBrotli, specEncodingGzip} {
+					if _, err := cache.encoded(serverURL, version, format, encoding, encodeCanonicalJSON); err != nil {
+						t.Fatalf("encoded() error = %v", err)
+					}
+				}
+			}
+		}
+	}
+
+	// every variant of the bound's worth of hosts is cached
+	for i := 0; i < maxCachedServerURLs; i++ {
+		serve("http://host" + strconv.Itoa(i))
+	}
+	for i := 0; i < maxCachedServerURLs; i++ {
+		if got := len(cache.byServer["http://host"+strconv.Itoa(i)]); got != 12 {
+			t.Errorf("host%d has %d cached variants, want 12", i, got)
+		}
+	}
+
+	serve("http://extra")
+	if _, ok := cache.byServer["http://extra"]; ok || len(cache.byServer) != maxCachedServerURLs {
+		t.Errorf("cached %d server URLs, want the bound of %d", len(cache.byServer), maxCachedServerURLs)
+	}
+}
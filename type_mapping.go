@@ -16,34 +16,211 @@ func goTypeToOpenAPIType(goType string) (string, string) {
 		"bool":        {"boolean", ""},
 		"time.Time":   {"string", "date-time"},
 		"interface{}": {"object", ""},
+		// Named interfaces from the standard library serialize as arbitrary JSON
+		"any":            {"object", ""},
+		"json.Marshaler": {"object", ""},
 	}
 
 	if mapping, ok := typeMap[goType]; ok {
 		return mapping.typ, mapping.format
 	}
+	if mapping, ok := libraryTypes[goType]; ok {
+		return mapping.typ, mapping.format
+	}
 	return "string", ""
 }
 
-func pluralize(word string) string {
-	if strings.HasSuffix(word, "y") && !isVowel(word[len(word)-2]) {
+// libraryType is the schema of a common library type, serialized as a JSON
+// scalar or, for nullable ones, null.
+type libraryType struct {
+	typ, format string
+	nullable    bool
+}
+
+// libraryTypes maps common library types, spelled with their package name,
+// to the schema of their JSON form.
+var libraryTypes = map[string]libraryType{
+	"uuid.UUID":       {typ: "string", format: "uuid"},
+	"time.Duration":   {typ: "integer", format: "int64"},
+	"json.RawMessage": {typ: "object"},
+	// json.RawMessage aliases it with encoding/json/v2, as reflection names it
+	"jsontext.Value":  {typ: "object"},
+	"decimal.Decimal": {typ: "string", format: "decimal"},
+
+	"sql.NullString":  {typ: "string", nullable: true},
+	"sql.NullBool":    {typ: "boolean", nullable: true},
+	"sql.NullByte":    {typ: "integer", nullable: true},
+	"sql.NullInt16":   {typ: "integer", format: "int32", nullable: true},
+	"sql.NullInt32":   {typ: "integer", format: "int32", nullable: true},
+	"sql.NullInt64":   {typ: "integer", format: "int64", nullable: true},
+	"sql.NullFloat64": {typ: "number", format: "double", nullable: true},
+	"sql.NullTime":    {typ: "string", format: "date-time", nullable: true},
+
+	"pgtype.Text":        {typ: "string", nullable: true},
+	"pgtype.Bool":        {typ: "boolean", nullable: true},
+	"pgtype.Int2":        {typ: "integer", format: "int32", nullable: true},
+	"pgtype.Int4":        {typ: "integer", format: "int32", nullable: true},
+	"pgtype.Int8":        {typ: "integer", format: "int64", nullable: true},
+	"pgtype.Float4":      {typ: "number", format: "float", nullable: true},
+	"pgtype.Float8":      {typ: "number", format: "double", nullable: true},
+	"pgtype.Numeric":     {typ: "number", nullable: true},
+	"pgtype.Date":        {typ: "string", format: "date", nullable: true},
+	"pgtype.Timestamp":   {typ: "string", format: "date-time", nullable: true},
+	"pgtype.Timestamptz": {typ: "string", format: "date-time", nullable: true},
+	"pgtype.UUID":        {typ: "string", format: "uuid", nullable: true},
+}
+
+// libraryTypeSchema returns the schema of a common library type.
+func libraryTypeSchema(goType string) (map[string]interface{}, bool) {
+	mapping, ok := libraryTypes[strings.TrimPrefix(goType, "*")]
+	if !ok {
+		return nil, false
+	}
+	schema := map[string]interface{}{"type": mapping.typ}
+	if mapping.format != "" {
+		schema["format"] = mapping.format
+	}
+	if mapping.nullable {
+		schema["nullable"] = true
+	}
+	return schema, true
+}
+
+// isInterfaceType reports whether a Go type serializes as arbitrary JSON.
+func isInterfaceType(goType string) bool {
+	switch strings.TrimPrefix(goType, "*") {
+	case "interface{}", "any", "json.Marshaler", "json.RawMessage":
+		return true
+	}
+	return false
+}
+
+// irregularPlurals lists the nouns whose plural the suffix rules get wrong:
+// irregular and uncountable nouns, f/fe and o words that take a plain -s or
+// -es, and the few z words that double their final consonant.
+var irregularPlurals = map[string]string{
+	"person":      "people",
+	"man":         "men",
+	"woman":       "women",
+	"child":       "children",
+	"mouse":       "mice",
+	"goose":       "geese",
+	"foot":        "feet",
+	"tooth":       "teeth",
+	"ox":          "oxen",
+	"criterion":   "criteria",
+	"phenomenon":  "phenomena",
+	"analysis":    "analyses",
+	"crisis":      "crises",
+	"thesis":      "theses",
+	"roof":        "roofs",
+	"proof":       "proofs",
+	"chief":       "chiefs",
+	"belief":      "beliefs",
+	"chef":        "chefs",
+	"cliff":       "cliffs",
+	"safe":        "safes",
+	"hero":        "heroes",
+	"echo":        "echoes",
+	"potato":      "potatoes",
+	"tomato":      "tomatoes",
+	"quiz":        "quizzes",
+	"fez":         "fezzes",
+	"whiz":        "whizzes",
+	"status":      "statuses",
+	"alias":       "aliases",
+	"bus":         "buses",
+	"virus":       "viruses",
+	"campus":      "campuses",
+	"data":        "data",
+	"metadata":    "metadata",
+	"information": "information",
+	"equipment":   "equipment",
+	"feedback":    "feedback",
+	"news":        "news",
+	"series":      "series",
+	"species":     "species",
+	"sheep":       "sheep",
+	"fish":        "fish",
+}
+
+// irregularSingulars reverses irregularPlurals.
+var irregularSingulars = func() map[string]string {
+	singulars := make(map[string]string, len(irregularPlurals))
+	for singular, plural := range irregularPlurals {
+		singulars[plural] = singular
+	}
+	return singulars
+}()
+
+// pluralize returns the plural of word. overrides (singular -> plural) is
+// looked up first for the whole word; the irregular table and the suffix
+// rules then apply to its last _ or - separated part, so sales_person
+// becomes sales_people.
+func pluralize(word string, overrides map[string]string) string {
+	if plural, ok := overrides[word]; ok {
+		return plural
+	}
+	prefix, last := splitLastWord(word)
+	if plural, ok := irregularPlurals[last]; ok {
+		return prefix + plural
+	}
+	return prefix + pluralizeRegular(last)
+}
+
+func pluralizeRegular(word string) string {
+	if strings.HasSuffix(word, "y") && len(word) > 1 && !isVowel(word[len(word)-2]) {
 		return word[:len(word)-1] + "ies"
 	}
 	if strings.HasSuffix(word, "fe") {
 		return word[:len(word)-2] + "ves"
 	}
-	if strings.HasSuffix(word, "f") {
+	if strings.HasSuffix(word, "f") && !strings.HasSuffix(word, "ff") {
 		return word[:len(word)-1] + "ves"
 	}
-	if strings.HasSuffix(word, "z") {
-		return word + "zes"
-	}
-	if strings.HasSuffix(word, "s") || strings.HasSuffix(word, "x") ||
+	if strings.HasSuffix(word, "s") || strings.HasSuffix(word, "x") || strings.HasSuffix(word, "z") ||
 		strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "sh") {
 		return word + "es"
 	}
 	return word + "s"
 }
 
+// singularize reverses pluralize: overrides (singular -> plural) and the
+// irregular table first, then the common English suffixes.
+func singularize(word string, overrides map[string]string) string {
+	for singular, plural := range overrides {
+		if plural == word {
+			return singular
+		}
+	}
+	prefix, last := splitLastWord(word)
+	if singular, ok := irregularSingulars[last]; ok {
+		return prefix + singular
+	}
+	return prefix + singularizeRegular(last)
+}
+
+func singularizeRegular(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "ves"):
+		return word[:len(word)-3] + "f"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "zes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// splitLastWord splits word after its last _ or - separator.
+func splitLastWord(word string) (prefix, last string) {
+	i := strings.LastIndexAny(word, "_-")
+	return word[:i+1], word[i+1:]
+}
+
 func isVowel(c byte) bool {
 	return c == 'a' || c == 'e' || c == 'i' || c == 'o' || c == 'u'
 }
@@ -77,6 +77,18 @@ func TestGoTypeToOpenAPIType(t *testing.T) {
 			wantType:   "object",
 			wantFormat: "",
 		},
+		{
+			name:       "any maps to object with no format",
+			goType:     "any",
+			wantType:   "object",
+			wantFormat: "",
+		},
+		{
+			name:       "json.Marshaler maps to object with no format",
+			goType:     "json.Marshaler",
+			wantType:   "object",
+			wantFormat: "",
+		},
 		// Pointer types (should strip * prefix)
 		{
 			name:       "pointer to string",
@@ -96,6 +108,37 @@ func TestGoTypeToOpenAPIType(t *testing.T) {
 			wantType:   "string",
 			wantFormat: "date-time",
 		},
+		// Common library types
+		{
+			name:       "uuid.UUID maps to string with uuid format",
+			goType:     "uuid.UUID",
+			wantType:   "string",
+			wantFormat: "uuid",
+		},
+		{
+			name:       "time.Duration maps to integer nanoseconds",
+			goType:     "time.Duration",
+			wantType:   "integer",
+			wantFormat: "int64",
+		},
+		{
+			name:       "decimal.Decimal maps to string with decimal format",
+			goType:     "decimal.Decimal",
+			wantType:   "string",
+			wantFormat: "decimal",
+		},
+		{
+			name:       "sql.NullInt64 maps to integer with int64 format",
+			goType:     "sql.NullInt64",
+			wantType:   "integer",
+			wantFormat: "int64",
+		},
+		{
+			name:       "pgtype.Timestamptz maps to string with date-time format",
+			goType:     "*pgtype.Timestamptz",
+			wantType:   "string",
+			wantFormat: "date-time",
+		},
 		// Unknown types default to string
 		{
 			name:       "unknown type defaults to string",
@@ -124,6 +167,28 @@ func TestGoTypeToOpenAPIType(t *testing.T) {
 	}
 }
 
+func TestIsInterfaceType(t *testing.T) {
+	tests := []struct {
+		goType string
+		want   bool
+	}{
+		{goType: "interface{}", want: true},
+		{goType: "any", want: true},
+		{goType: "*json.Marshaler", want: true},
+		{goType: "json.RawMessage", want: true},
+		{goType: "string", want: false},
+		{goType: "map[string]string", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			if got := isInterfaceType(tt.goType); got != tt.want {
+				t.Errorf("isInterfaceType(%q) = %v, want %v", tt.goType, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPluralize(t *testing.T) {
 	tests := []struct {
 		name string
@@ -257,15 +322,15 @@ func TestPluralize(t *testing.T) {
 			want: "as",
 		},
 		{
-			name: "two letters",
+			name: "irregular two letters",
 			word: "ox",
-			want: "oxes",
+			want: "oxen",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := pluralize(tt.word); got != tt.want {
+			if got := pluralize(tt.word, nil); got != tt.want {
 				t.Errorf("pluralize(%q) = %v, want %v", tt.word, got, tt.want)
 			}
 		})
@@ -299,3 +364,65 @@ func TestIsVowel(t *testing.T) {
 		})
 	}
 }
+
+func TestSingularize(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{word: "users", want: "user"},
+		{word: "categories", want: "category"},
+		{word: "leaves", want: "leaf"},
+		{word: "boxes", want: "box"},
+		{word: "churches", want: "church"},
+		{word: "dishes", want: "dish"},
+		{word: "quizes", want: "quiz"},
+		{word: "quizzes", want: "quiz"},
+		{word: "waltzes", want: "waltz"},
+		{word: "classes", want: "class"},
+		{word: "houses", want: "house"},
+		{word: "address", want: "address"},
+		{word: "health", want: "health"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word, func(t *testing.T) {
+			if got := singularize(tt.word, nil); got != tt.want {
+				t.Errorf("singularize(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPluralizeIrregular(t *testing.T) {
+	overrides := map[string]string{"staff_member": "staff", "product": "catalog"}
+
+	tests := []struct {
+		singular string
+		plural   string
+	}{
+		{singular: "person", plural: "people"},
+		{singular: "sales_person", plural: "sales_people"},
+		{singular: "child", plural: "children"},
+		{singular: "status", plural: "statuses"},
+		{singular: "quiz", plural: "quizzes"},
+		{singular: "waltz", plural: "waltzes"},
+		{singular: "roof", plural: "roofs"},
+		{singular: "cliff", plural: "cliffs"},
+		{singular: "hero", plural: "heroes"},
+		{singular: "metadata", plural: "metadata"},
+		{singular: "staff_member", plural: "staff"},
+		{singular: "product", plural: "catalog"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.singular, func(t *testing.T) {
+			if got := pluralize(tt.singular, overrides); got != tt.plural {
+				t.Errorf("pluralize(%q) = %q, want %q", tt.singular, got, tt.plural)
+			}
+			if got := singularize(tt.plural, overrides); got != tt.singular {
+				t.Errorf("singularize(%q) = %q, want %q", tt.plural, got, tt.singular)
+			}
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nicolasbonnici/gorest/logger"
+)
+
+// wellKnownImports are the packages whose types the DTO schemas map by name
+// (time.Time, json.RawMessage, uuid.UUID, sql.NullString...), so importing
+// only them does not require type-checking the DTO package.
+var wellKnownImports = []string{
+	"time",
+	"encoding/json",
+	"database/sql",
+	"github.com/google/uuid",
+	"github.com/shopspring/decimal",
+	"github.com/jackc/pgx/v5/pgtype",
+}
+
+// importedTypeResolver documents the types a DTO package imports from other
+// packages (shared value objects, enums, nested models) as named types, keyed
+// like the DTO fields spell them (money.Amount).
+type importedTypeResolver struct {
+	types map[string]namedType
+	// keys holds the key each named type was registered under, so types
+	// referring to a package imported with an alias (m "example.com/money")
+	// use the key the DTO fields spell, not the package name.
+	keys map[*types.Named]string
+	// expanding holds the structs being converted, so a struct embedding
+	// itself stops.
+	expanding map[*types.Struct]bool
+}
+
+// resolveImportedTypes type-checks the files of a DTO package, loading its
+// imports from the export data the go command compiles for them within the
+// module of the DTOs directory, and adds the imported types
+// its files refer to. Types already known (declared in the DTOs directory)
+// are kept. Type errors only leave the affected types unresolved, falling
+// back to the string mapping.
+func resolveImportedTypes(fset *token.FileSet, files []*ast.File, known map[string]namedType) {
+	if !importsPackages(files) {
+		return
+	}
+
+	dir := filepath.Dir(fset.Position(files[0].Package).Filename)
+	exports, err := exportDataFiles(dir, fileImports(files))
+	if err != nil {
+		logger.Log.Warn("Failed to load the packages imported by DTOs", "directory", dir, "error", err)
+		return
+	}
+
+	info := &types.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+			file, ok := exports[path]
+			if !ok {
+				return nil, fmt.Errorf("no export data for %s", path)
+			}
+			return os.Open(file)
+		}),
+		Error: func(error) {},
+	}
+	pkg, err := conf.Check(files[0].Name.Name, fset, files, info)
+	if err != nil {
+		logger.Log.Warn("DTO types left unresolved", "directory", dir, "error", err)
+	}
+	if pkg == nil {
+		return
+	}
+
+	r := importedTypeResolver{types: known, keys: make(map[*types.Named]string), expanding: make(map[*types.Struct]bool)}
+	for _, file := range files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			sel, ok := node.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			ident, ok := sel.X.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			if _, ok := info.Uses[ident].(*types.PkgName); !ok {
+				return true
+			}
+			if tv, ok := info.Types[sel]; ok && tv.IsType() {
+				r.register(ident.Name+"."+sel.Sel.Name, tv.Type)
+			}
+			return false
+		})
+	}
+}
+
+// importsPackages reports whether files import packages besides the
+// well-known ones.
+func importsPackages(files []*ast.File) bool {
+	for _, path := range fileImports(files) {
+		if !slices.Contains(wellKnownImports, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// fileImports lists the import paths of files, sorted and deduplicated.
+func fileImports(files []*ast.File) []string {
+	var paths []string
+	for _, file := range files {
+		for _, imp := range file.Imports {
+			paths = append(paths, strings.Trim(imp.Path.Value, `"`))
+		}
+	}
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// exportDataFiles compiles the given packages and their dependencies with
+// go list, run from dir so the enclosing module resolves them, and returns
+// their export data files by import path. Packages failing to load are left
+// out; an error means go list itself could not run.
+func exportDataFiles(dir string, imports []string) (map[string]string, error) {
+	args := append([]string{"list", "-e", "-export", "-deps", "-f", "{{.ImportPath}} {{.Export}}", "--"}, imports...)
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("go list: %w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("go list: %w", err)
+	}
+
+	files := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if path, file, ok := strings.Cut(line, " "); ok && file != "" {
+			files[path] = file
+		}
+	}
+	return files, nil
+}
+
+// register documents a named type of another package under key, along with
+// the types it refers to. Types marshaling themselves (time.Time, uuid.UUID)
+// and the common library types keep their mapping by name, since their JSON
+// form is not their structure.
+func (r importedTypeResolver) register(key string, t types.Type) {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || marshalsItself(named) {
+		return
+	}
+	if _, ok := libraryTypes[key]; ok {
+		return
+	}
+	if _, ok := r.keys[named]; !ok {
+		r.keys[named] = key
+	}
+	if _, ok := r.types[key]; ok {
+		return
+	}
+
+	// Registered before its underlying type so self-references stop here
+	r.types[key] = namedType{}
+	var resolved namedType
+	if st, ok := named.Underlying().(*types.Struct); ok {
+		resolved = namedType{Underlying: "struct", Fields: r.structFields(st)}
+	} else {
+		resolved = namedType{Underlying: r.typeName(named.Underlying()), Enum: constEnum(named)}
+		if elem, ok := inlineStruct(named.Underlying()); ok {
+			resolved.Fields = r.structFields(elem)
+		}
+	}
+	if resolved.Underlying == "" {
+		delete(r.types, key)
+		return
+	}
+	r.types[key] = resolved
+}
+
+// typeName renders a type as the type string understood by the schema
+// builder, registering the named types it refers to.
+func (r importedTypeResolver) typeName(t types.Type) string {
+	switch t := types.Unalias(t).(type) {
+	case *types.Named:
+		if key, ok := r.keys[t]; ok {
+			return key
+		}
+		name := t.Obj().Name()
+		if pkg := t.Obj().Pkg(); pkg != nil {
+			name = pkg.Name() + "." + name
+			r.register(name, t)
+		}
+		return name
+	case *types.Basic:
+		return t.Name()
+	case *types.Pointer:
+		return r.typeName(t.Elem())
+	case *types.Slice:
+		return "[]" + r.typeName(t.Elem())
+	case *types.Array:
+		return "[]" + r.typeName(t.Elem())
+	case *types.Map:
+		return "map[" + r.typeName(t.Key()) + "]" + r.typeName(t.Elem())
+	case *types.Interface:
+		return "interface{}"
+	case *types.Struct:
+		return "struct"
+	}
+	return ""
+}
+
+// structFields converts the exported fields of a struct to parsed fields,
+// the way extractStructFieldsFromAST reads them from source.
+func (r importedTypeResolver) structFields(st *types.Struct) []structField {
+	if r.expanding[st] {
+		return nil
+	}
+	r.expanding[st] = true
+	defer delete(r.expanding, st)
+
+	var fields []structField
+	for i := range st.NumFields() {
+		v := st.Field(i)
+		if !v.Exported() && !v.Embedded() {
+			continue
+		}
+
+		typ := types.Unalias(v.Type())
+		ptr, isPointer := typ.(*types.Pointer)
+		if isPointer {
+			typ = ptr.Elem()
+		}
+
+		field := structField{
+			Name:      v.Name(),
+			Type:      r.typeName(typ),
+			IsPointer: isPointer,
+		}
+		applyFieldTags(&field, st.Tag(i))
+		if field.JSONTag == "-" {
+			continue
+		}
+
+		if nested, ok := typ.Underlying().(*types.Struct); ok && (field.Type == "struct" || v.Embedded()) {
+			field.Fields = r.structFields(nested)
+		}
+		if elem, ok := inlineStruct(typ); ok && field.Fields == nil {
+			field.Fields = r.structFields(elem)
+		}
+		field.Embedded = v.Embedded() && field.JSONTag == ""
+
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// inlineStruct returns the anonymous struct a slice, array or map holds,
+// possibly through pointers and further containers ([]map[string]struct{...}),
+// the way typeName spells it "struct".
+func inlineStruct(t types.Type) (*types.Struct, bool) {
+	switch t := types.Unalias(t).(type) {
+	case *types.Struct:
+		return t, true
+	case *types.Pointer:
+		return inlineStruct(t.Elem())
+	case *types.Slice:
+		return inlineStruct(t.Elem())
+	case *types.Array:
+		return inlineStruct(t.Elem())
+	case *types.Map:
+		return inlineStruct(t.Elem())
+	}
+	return nil, false
+}
+
+// constEnum returns the values of the constants declared with a named type
+// in its package, in declaration order.
+func constEnum(named *types.Named) []interface{} {
+	scope := named.Obj().Pkg().Scope()
+	var consts []*types.Const
+	for _, name := range scope.Names() {
+		if c, ok := scope.Lookup(name).(*types.Const); ok && c.Exported() && types.Identical(c.Type(), named) {
+			consts = append(consts, c)
+		}
+	}
+	slices.SortFunc(consts, func(a, b *types.Const) int { return int(a.Pos() - b.Pos()) })
+
+	var enum []interface{}
+	for _, c := range consts {
+		if value, ok := constantValue(c.Val()); ok {
+			enum = append(enum, value)
+		}
+	}
+	return enum
+}
+
+// marshalsItself reports whether a type, or a pointer to it, implements
+// json.Marshaler or encoding.TextMarshaler.
+func marshalsItself(named *types.Named) bool {
+	for _, t := range []types.Type{named, types.NewPointer(named)} {
+		methods := types.NewMethodSet(t)
+		for _, name := range []string{"MarshalJSON", "MarshalText"} {
+			if methods.Lookup(named.Obj().Pkg(), name) != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
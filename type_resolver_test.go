@@ -0,0 +1,199 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeModule lays out a Go module in a temporary directory from file
+// contents keyed by slash-separated path, and returns its root.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	files["go.mod"] = "module example.com/app\n\ngo 1.26\n"
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+	return root
+}
+
+func TestLoadNamedTypes_Imported(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"shared/money.go": `package shared
+
+import "time"
+
+type Currency string
+
+const (
+	EUR Currency = "EUR"
+	USD Currency = "USD"
+)
+
+type Money struct {
+	Amount   int64    ` + "`json:\"amount\"`" + `
+	Currency Currency ` + "`json:\"currency\"`" + `
+	secret   string
+}
+
+type Audit struct {
+	At time.Time ` + "`json:\"at\"`" + `
+	By *string   ` + "`json:\"by\"`" + `
+}
+
+type Lines map[string][]struct {
+	SKU string ` + "`json:\"sku\"`" + `
+}
+
+type Node struct {
+	*Node
+	Children []Node ` + "`json:\"children\"`" + `
+}
+`,
+		"dtos/order.go": `package dto
+
+import (
+	"time"
+
+	money "example.com/app/shared"
+)
+
+type OrderDTO struct {
+	Total    money.Money    ` + "`json:\"total\"`" + `
+	Currency money.Currency ` + "`json:\"currency\"`" + `
+	Tree     money.Node     ` + "`json:\"tree\"`" + `
+	Lines    money.Lines    ` + "`json:\"lines\"`" + `
+	Placed   time.Time      ` + "`json:\"placed\"`" + `
+	money.Audit
+}
+`,
+	})
+
+	types, err := loadNamedTypesFrom(diskDTOSource(filepath.Join(root, "dtos")), false, true)
+	if err != nil {
+		t.Fatalf("loadNamedTypesFrom() error = %v", err)
+	}
+
+	wantCurrency := namedType{Underlying: "string", Enum: []interface{}{"EUR", "USD"}}
+	if !reflect.DeepEqual(types["money.Currency"], wantCurrency) {
+		t.Errorf("money.Currency = %+v, want %+v", types["money.Currency"], wantCurrency)
+	}
+	if _, ok := types["time.Time"]; ok {
+		t.Error("time.Time marshals itself and must keep its mapping")
+	}
+
+	cfg := GeneratorConfig{namedTypes: types}
+	wantTotal := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"amount":   map[string]interface{}{"type": "integer", "format": "int64"},
+			"currency": map[string]interface{}{"type": "string", "enum": []interface{}{"EUR", "USD"}},
+		},
+		"required": []string{"amount", "currency"},
+	}
+	if got := fieldTypeSchema("money.Money", nil, cfg); !reflect.DeepEqual(got, wantTotal) {
+		t.Errorf("fieldTypeSchema(money.Money) = %v, want %v", got, wantTotal)
+	}
+
+	audit := types["money.Audit"]
+	if len(audit.Fields) != 2 || audit.Fields[0].Type != "time.Time" || !audit.Fields[1].IsPointer {
+		t.Errorf("money.Audit fields = %+v, want at time.Time and by *string", audit.Fields)
+	}
+
+	wantLines := namedType{Underlying: "map[string][]struct", Fields: []structField{{Name: "SKU", Type: "string", JSONTag: "sku"}}}
+	if !reflect.DeepEqual(types["money.Lines"], wantLines) {
+		t.Errorf("money.Lines = %+v, want %+v", types["money.Lines"], wantLines)
+	}
+
+	node := types["money.Node"]
+	// Self-references use the key the DTO fields spell, under the import alias
+	if len(node.Fields) != 2 || !node.Fields[0].Embedded || node.Fields[1].Type != "[]money.Node" {
+		t.Errorf("money.Node fields = %+v, want the embedded Node and children", node.Fields)
+	}
+}
+
+func TestLoadNamedTypes_WellKnownImportsOnly(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"dtos/event.go": "package dto\n\nimport \"time\"\n\ntype EventDTO struct {\n\tAt time.Time `json:\"at\"`\n}\n",
+	})
+
+	types, err := loadNamedTypesFrom(diskDTOSource(filepath.Join(root, "dtos")), false, true)
+	if err != nil {
+		t.Fatalf("loadNamedTypesFrom() error = %v", err)
+	}
+	if len(types) != 0 {
+		t.Errorf("loadNamedTypesFrom() = %v, want no types", types)
+	}
+}
+
+func TestLoadNamedTypes_UnresolvableImport(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"dtos/user.go": "package dto\n\nimport \"example.com/missing\"\n\ntype UserDTO struct {\n\tRole missing.Role `json:\"role\"`\n}\n",
+	})
+
+	types, err := loadNamedTypesFrom(diskDTOSource(filepath.Join(root, "dtos")), false, true)
+	if err != nil {
+		t.Fatalf("loadNamedTypesFrom() error = %v", err)
+	}
+	if _, ok := types["missing.Role"]; ok {
+		t.Error("unresolvable imported type must fall back to the string mapping")
+	}
+}
+
+func TestGenerateOpenAPISpec_ImportedTypes(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"shared/audit.go": "package shared\n\ntype Audit struct {\n\tCreatedBy string `json:\"created_by\"`\n}\n\ntype Quantity int\n",
+		"dtos/order.go": `package dto
+
+import "example.com/app/shared"
+
+type OrderDTO struct {
+	shared.Audit
+	Quantity shared.Quantity ` + "`json:\"quantity\"`" + `
+}
+`,
+	})
+	_, app, cfg := setupSpecWithMultipleResources(t)
+	cfg.DTOsDirectory = filepath.Join(root, "dtos")
+	cfg.ResolveImportedTypes = true
+
+	spec, err := generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	properties := schemas["Order"].(map[string]interface{})["properties"].(map[string]interface{})
+	if want := map[string]interface{}{"type": "string"}; !reflect.DeepEqual(properties["created_by"], want) {
+		t.Errorf("created_by = %v, want the promoted %v", properties["created_by"], want)
+	}
+	if want := map[string]interface{}{"type": "integer", "format": "int32"}; !reflect.DeepEqual(properties["quantity"], want) {
+		t.Errorf("quantity = %v, want %v", properties["quantity"], want)
+	}
+
+	cfg.ResolveImportedTypes = false
+	spec, err = generateOpenAPISpec(app, cfg)
+	if err != nil {
+		t.Fatalf("generateOpenAPISpec() error = %v", err)
+	}
+	schemas = spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	properties = schemas["Order"].(map[string]interface{})["properties"].(map[string]interface{})
+	if want := map[string]interface{}{"type": "string"}; !reflect.DeepEqual(properties["quantity"], want) {
+		t.Errorf("quantity without ResolveImportedTypes = %v, want the string fallback %v", properties["quantity"], want)
+	}
+}
+
+func TestExportDataFiles_WithoutGoCommand(t *testing.T) {
+	t.Setenv("PATH", "")
+	if _, err := exportDataFiles(t.TempDir(), []string{"example.com/app/shared"}); err == nil {
+		t.Error("exportDataFiles() without the go command should report the go list failure")
+	}
+}
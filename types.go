@@ -1,34 +1,198 @@
 package openapi
 
+import (
+	"path"
+	"strings"
+)
+
 type structField struct {
-	Name      string
-	Type      string
-	JSONTag   string
-	DBTag     string
-	DTOTag    string
-	IsPointer bool
+	Name    string
+	Type    string
+	JSONTag string
+	DBTag   string
+	// DTOTag holds the `dto:"create,update"` struct tag listing the operation
+	// contexts the field belongs to; see inDTOContext.
+	DTOTag string
+	// OpenAPITag holds the raw `openapi:"..."` struct tag options.
+	OpenAPITag  string
+	ValidateTag string
+	// DescriptionTag holds the `description:"..."` struct tag documenting
+	// the property.
+	DescriptionTag string
+	// Deprecated is set by a `deprecated:"true"` struct tag.
+	Deprecated bool
+	// DefaultTag holds the `default:"..."` struct tag value, typed after the
+	// property schema.
+	DefaultTag string
+	// XMLTag holds the `xml:"..."` struct tag naming the XML element or
+	// attribute of the property.
+	XMLTag string
+	// EnumLabelsTag holds the `enum_labels:"value=Label,..."` struct tag
+	// describing the enum values.
+	EnumLabelsTag string
+	// UnionKeyword and UnionTypes hold a `oneof_types:"A,B"` (oneOf) or
+	// `anyof_types:"A,B"` (anyOf) struct tag declaring the field a union of
+	// the listed types.
+	UnionKeyword string
+	UnionTypes   []string
+	IsPointer    bool
+	// Fields holds the members of an inline struct type ("struct" or "[]struct"),
+	// or of the struct an embedded field refers to, once resolved.
+	Fields []structField
+	// Embedded marks an embedded struct without a json name, whose fields are
+	// promoted into the enclosing object. Name and Type hold the struct type.
+	Embedded bool
 }
 
-type dtoSchema struct {
-	Name   string
+// namedType is a type defined in the DTOs directory that is neither a struct
+// nor an interface (type Email string), or a type imported from another
+// package, documented as its underlying type.
+type namedType struct {
+	Underlying string
+	// Enum holds the values of the constants declared with the type, in
+	// declaration order.
+	Enum []interface{}
+	// Fields holds the members of a struct type imported from another
+	// package, whose Underlying is "struct".
 	Fields []structField
 }
 
+// dtoContextRead is the response context of the dto struct tag, next to the
+// DTOVariantCreate and DTOVariantUpdate request ones.
+const dtoContextRead = "read"
+
+// inDTOContext reports whether the field belongs to the schema of the given
+// operation context: fields without a dto tag belong to every context.
+func (f structField) inDTOContext(context string) bool {
+	if f.DTOTag == "" {
+		return true
+	}
+	for _, listed := range strings.Split(f.DTOTag, ",") {
+		if strings.TrimSpace(listed) == context {
+			return true
+		}
+	}
+	return false
+}
+
+// dtoContextFields keeps the fields, nested ones included, belonging to the
+// given operation context.
+func dtoContextFields(fields []structField, context string) []structField {
+	var kept []structField
+	for _, field := range fields {
+		if !field.inDTOContext(context) {
+			continue
+		}
+		if field.Fields != nil {
+			field.Fields = dtoContextFields(field.Fields, context)
+		}
+		kept = append(kept, field)
+	}
+	return kept
+}
+
+// hasDTOContexts reports whether any field, nested ones included, carries a
+// dto tag.
+func hasDTOContexts(fields []structField) bool {
+	for _, field := range fields {
+		if field.DTOTag != "" || hasDTOContexts(field.Fields) {
+			return true
+		}
+	}
+	return false
+}
+
+type dtoSchema struct {
+	Name string
+	// Description is the struct's doc comment.
+	Description string
+	Fields      []structField
+}
+
 type resourceDTOs struct {
 	Name       string
 	PluralName string
-	DTOs       map[string]dtoSchema
+	// Dir is the slash-separated subdirectory of the DTOs directory holding
+	// the resource file, empty at the top level.
+	Dir  string
+	DTOs map[string]dtoSchema
+}
+
+// defaultDTOVariants maps each DTO variant role to the name pattern
+// (path.Match syntax) recognizing it.
+var defaultDTOVariants = map[string]string{
+	DTOVariantCreate: "*Create*",
+	DTOVariantUpdate: "*Update*",
+	DTOVariantList:   "*ListItem*",
+}
+
+// dtoVariantPatterns merges configured variant patterns over the defaults.
+func dtoVariantPatterns(overrides map[string]string) map[string]string {
+	patterns := make(map[string]string, len(defaultDTOVariants))
+	for role, pattern := range defaultDTOVariants {
+		patterns[role] = pattern
+	}
+	for role, pattern := range overrides {
+		patterns[role] = pattern
+	}
+	return patterns
 }
 
 func (r *resourceDTOs) getMainDTO() *dtoSchema {
-	for name, dto := range r.DTOs {
-		if !containsSubstr(name, "Create") && !containsSubstr(name, "Update") {
+	return r.resolveMainDTO("", dtoVariantPatterns(nil), nil)
+}
+
+// resolveMainDTO returns the DTO named by override when the resource declares
+// it. Otherwise the name patterns are tried in priority order, the first DTO,
+// by name, matching the earliest one winning, before falling back to the
+// first DTO, by name, matching no variant pattern.
+func (r *resourceDTOs) resolveMainDTO(override string, variants map[string]string, patterns []string) *dtoSchema {
+	if override != "" {
+		if dto, ok := r.DTOs[override]; ok {
+			return &dto
+		}
+	}
+
+	for _, pattern := range patterns {
+		for _, name := range sortedKeys(r.DTOs) {
+			if matched, _ := path.Match(pattern, name); matched {
+				dto := r.DTOs[name]
+				return &dto
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(r.DTOs) {
+		if dtoVariantRole(name, variants) == "" {
+			dto := r.DTOs[name]
+			return &dto
+		}
+	}
+	return nil
+}
+
+// variantDTO returns the first DTO, by name, playing the given variant role.
+func (r *resourceDTOs) variantDTO(role string, variants map[string]string) *dtoSchema {
+	for _, name := range sortedKeys(r.DTOs) {
+		if dtoVariantRole(name, variants) == role {
+			dto := r.DTOs[name]
 			return &dto
 		}
 	}
 	return nil
 }
 
+// dtoVariantRole classifies a DTO name against the variant patterns, in
+// sorted role order, returning "" for the main DTO.
+func dtoVariantRole(name string, variants map[string]string) string {
+	for _, role := range sortedKeys(variants) {
+		if matched, _ := path.Match(variants[role], name); matched {
+			return role
+		}
+	}
+	return ""
+}
+
 func containsSubstr(s, substr string) bool {
 	for i := 0; i+len(substr) <= len(s); i++ {
 		if s[i:i+len(substr)] == substr {
@@ -1,6 +1,7 @@
 package openapi
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -103,6 +104,205 @@ func TestResourceDTOs_getMainDTO(t *testing.T) {
 	}
 }
 
+func TestResourceDTOs_resolveMainDTO(t *testing.T) {
+	resource := resourceDTOs{
+		Name:       "user",
+		PluralName: "users",
+		DTOs: map[string]dtoSchema{
+			"UserDTO":         {Name: "UserDTO"},
+			"UserResponseDTO": {Name: "UserResponseDTO"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		override string
+		want     []string
+	}{
+		{
+			name:     "override selects the named DTO",
+			override: "UserResponseDTO",
+			want:     []string{"UserResponseDTO"},
+		},
+		{
+			name:     "unknown override falls back to heuristic",
+			override: "MissingDTO",
+			want:     []string{"UserDTO", "UserResponseDTO"},
+		},
+		{
+			name:     "empty override falls back to heuristic",
+			override: "",
+			want:     []string{"UserDTO", "UserResponseDTO"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resource.resolveMainDTO(tt.override, dtoVariantPatterns(nil), nil)
+			if got == nil {
+				t.Fatal("resolveMainDTO() = nil, want a DTO")
+			}
+			found := false
+			for _, name := range tt.want {
+				if got.Name == name {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("resolveMainDTO(%q).Name = %v, want one of %v", tt.override, got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceDTOs_resolveMainDTO_Patterns(t *testing.T) {
+	resource := resourceDTOs{
+		Name: "user",
+		DTOs: map[string]dtoSchema{
+			"UserDTO":         {Name: "UserDTO"},
+			"UserDetailDTO":   {Name: "UserDetailDTO"},
+			"UserResponseDTO": {Name: "UserResponseDTO"},
+			"CreateUserDTO":   {Name: "CreateUserDTO"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		override string
+		patterns []string
+		want     string
+	}{
+		{name: "first pattern wins", patterns: []string{"*ResponseDTO", "*DetailDTO"}, want: "UserResponseDTO"},
+		{name: "later pattern when the first matches nothing", patterns: []string{"*ViewDTO", "*DetailDTO"}, want: "UserDetailDTO"},
+		{name: "patterns may select a variant", patterns: []string{"Create*"}, want: "CreateUserDTO"},
+		{name: "no match falls back to heuristic", patterns: []string{"*ViewDTO"}, want: "UserDTO"},
+		{name: "override wins over patterns", override: "UserDTO", patterns: []string{"*ResponseDTO"}, want: "UserDTO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resource.resolveMainDTO(tt.override, dtoVariantPatterns(nil), tt.patterns)
+			if got == nil || got.Name != tt.want {
+				t.Errorf("resolveMainDTO(%q, %v) = %v, want %s", tt.override, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceDTOs_getMainDTO_ListNames(t *testing.T) {
+	tests := []struct {
+		name string
+		dtos []string
+		want string
+	}{
+		{name: "listing", dtos: []string{"ListingDTO", "ListingListItemDTO"}, want: "ListingDTO"},
+		{name: "wish list", dtos: []string{"WishListDTO", "WishListListItemDTO"}, want: "WishListDTO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := resourceDTOs{Name: tt.name, DTOs: map[string]dtoSchema{}}
+			for _, name := range tt.dtos {
+				resource.DTOs[name] = dtoSchema{Name: name}
+			}
+			if got := resource.getMainDTO(); got == nil || got.Name != tt.want {
+				t.Errorf("getMainDTO() = %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceDTOs_variantDTO(t *testing.T) {
+	resource := resourceDTOs{
+		Name:       "user",
+		PluralName: "users",
+		DTOs: map[string]dtoSchema{
+			"UserDTO":         {Name: "UserDTO"},
+			"UserCreateDTO":   {Name: "UserCreateDTO"},
+			"UserUpdateDTO":   {Name: "UserUpdateDTO"},
+			"UserListItemDTO": {Name: "UserListItemDTO"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		variants map[string]string
+		role     string
+		want     string
+	}{
+		{name: "create variant", role: DTOVariantCreate, want: "UserCreateDTO"},
+		{name: "update variant", role: DTOVariantUpdate, want: "UserUpdateDTO"},
+		{name: "list variant", role: DTOVariantList, want: "UserListItemDTO"},
+		{
+			name:     "custom pattern without match",
+			variants: map[string]string{DTOVariantList: "*Summary*"},
+			role:     DTOVariantList,
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resource.variantDTO(tt.role, dtoVariantPatterns(tt.variants))
+			gotName := ""
+			if got != nil {
+				gotName = got.Name
+			}
+			if gotName != tt.want {
+				t.Errorf("variantDTO(%q) = %q, want %q", tt.role, gotName, tt.want)
+			}
+		})
+	}
+
+	if main := resource.resolveMainDTO("", dtoVariantPatterns(nil), nil); main == nil || main.Name != "UserDTO" {
+		t.Errorf("resolveMainDTO() = %v, want UserDTO", main)
+	}
+}
+
+func TestDTOContextFields(t *testing.T) {
+	fields := []structField{
+		{Name: "ID", JSONTag: "id", DTOTag: "read"},
+		{Name: "Email", JSONTag: "email"},
+		{Name: "Password", JSONTag: "password", DTOTag: "create"},
+		{Name: "Nickname", JSONTag: "nickname", DTOTag: "create, update"},
+		{Name: "Profile", Type: "struct", JSONTag: "profile", Fields: []structField{
+			{Name: "Bio", JSONTag: "bio"},
+			{Name: "Verified", JSONTag: "verified", DTOTag: "read"},
+		}},
+	}
+
+	tests := []struct {
+		context string
+		want    []string
+	}{
+		{context: dtoContextRead, want: []string{"id", "email", "profile", "profile.bio", "profile.verified"}},
+		{context: DTOVariantCreate, want: []string{"email", "password", "nickname", "profile", "profile.bio"}},
+		{context: DTOVariantUpdate, want: []string{"email", "nickname", "profile", "profile.bio"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.context, func(t *testing.T) {
+			var got []string
+			for _, field := range dtoContextFields(fields, tt.context) {
+				got = append(got, field.JSONTag)
+				for _, nested := range field.Fields {
+					got = append(got, field.JSONTag+"."+nested.JSONTag)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dtoContextFields(%q) = %v, want %v", tt.context, got, tt.want)
+			}
+		})
+	}
+
+	if !hasDTOContexts(fields[4:]) {
+		t.Error("hasDTOContexts() = false, want true for a nested dto tag")
+	}
+	if hasDTOContexts(fields[1:2]) {
+		t.Error("hasDTOContexts() = true, want false without dto tags")
+	}
+}
+
 func TestContainsSubstr(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"html/template"
+	"strings"
+)
+
+// UIRenderer renders the interactive documentation page served at the UI
+// path. Implementations let hosts plug in another documentation frontend.
+type UIRenderer interface {
+	// RenderUI returns the page HTML loading the spec served at specURL.
+	RenderUI(specURL string) (string, error)
+	// CSPDirectives returns the Content-Security-Policy directives the page
+	// needs (e.g. "script-src 'self' https://cdn.example.com").
+	CSPDirectives() []string
+}
+
+// ScalarUIRenderer renders the Scalar API reference, loaded from jsDelivr.
+// It is the default UIRenderer.
+type ScalarUIRenderer struct{}
+
+func (ScalarUIRenderer) RenderUI(specURL string) (string, error) {
+	return `<!DOCTYPE html>
+<html>
+<head>
+    <title>GoREST API Documentation</title>
+    <meta charset="utf-8"/>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <style>
+        body {
+            margin: 0;
+            padding: 0;
+        }
+    </style>
+</head>
+<body>
+    <script id="api-reference" data-url="` + template.HTMLEscapeString(specURL) + `"></script>
+    <script src="https://cdn.jsdelivr.net/npm/@scalar/api-reference"></script>
+</body>
+</html>`, nil
+}
+
+func (ScalarUIRenderer) CSPDirectives() []string {
+	return []string{
+		"default-src 'self'",
+		"script-src 'self' 'unsafe-inline' 'unsafe-eval' https://cdn.jsdelivr.net",
+		"style-src 'self' 'unsafe-inline' https://cdn.jsdelivr.net",
+		"font-src 'self' https://cdn.jsdelivr.net data:",
+		"img-src 'self' data: https:",
+		"connect-src 'self' https:",
+	}
+}
+
+// OfflineScalarUIRenderer renders the Scalar API reference from a bundle the
+// application serves itself at BundleURL, so the page needs no CDN.
+type OfflineScalarUIRenderer struct {
+	BundleURL string
+}
+
+func (r OfflineScalarUIRenderer) RenderUI(specURL string) (string, error) {
+	return `<!DOCTYPE html>
+<html>
+<head>
+    <title>GoREST API Documentation</title>
+    <meta charset="utf-8"/>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <style>
+        body {
+            margin: 0;
+            padding: 0;
+        }
+    </style>
+</head>
+<body>
+    <script id="api-reference" data-url="` + template.HTMLEscapeString(specURL) + `"></script>
+    <script src="` + template.HTMLEscapeString(r.BundleURL) + `"></script>
+</body>
+</html>`, nil
+}
+
+func (OfflineScalarUIRenderer) CSPDirectives() []string {
+	return []string{
+		"default-src 'self'",
+		"script-src 'self' 'unsafe-inline' 'unsafe-eval'",
+		"style-src 'self' 'unsafe-inline'",
+		"font-src 'self' data:",
+		"img-src 'self' data: https:",
+		"connect-src 'self' https:",
+	}
+}
+
+// contentSecurityPolicy joins CSP directives into a header value.
+func contentSecurityPolicy(directives []string) string {
+	if len(directives) == 0 {
+		return ""
+	}
+	return strings.Join(directives, "; ") + ";"
+}
@@ -0,0 +1,74 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScalarUIRenderer_RenderUI(t *testing.T) {
+	tests := []struct {
+		name    string
+		specURL string
+		want    string
+	}{
+		{name: "default spec path", specURL: "/openapi.json", want: `data-url="/openapi.json"`},
+		{name: "escapes spec url", specURL: `/spec.json?a="b"`, want: `data-url="/spec.json?a=&#34;b&#34;"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html, err := ScalarUIRenderer{}.RenderUI(tt.specURL)
+			if err != nil {
+				t.Fatalf("RenderUI() error = %v", err)
+			}
+			if !strings.Contains(html, tt.want) {
+				t.Errorf("RenderUI() should contain %s", tt.want)
+			}
+		})
+	}
+}
+
+func TestOfflineScalarUIRenderer(t *testing.T) {
+	renderer := OfflineScalarUIRenderer{BundleURL: "/docs/scalar.js"}
+
+	html, err := renderer.RenderUI("/docs/spec.json")
+	if err != nil {
+		t.Fatalf("RenderUI() error = %v", err)
+	}
+	if !strings.Contains(html, `<script src="/docs/scalar.js"></script>`) {
+		t.Error("RenderUI() should load the locally served bundle")
+	}
+	if strings.Contains(html, "cdn.jsdelivr.net") {
+		t.Error("RenderUI() should not reference the CDN")
+	}
+
+	for _, directive := range renderer.CSPDirectives() {
+		if strings.Contains(directive, "cdn.jsdelivr.net") {
+			t.Errorf("CSP directive %q should not allow the CDN", directive)
+		}
+	}
+}
+
+func TestContentSecurityPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		directives []string
+		want       string
+	}{
+		{name: "none", directives: nil, want: ""},
+		{name: "single", directives: []string{"default-src 'self'"}, want: "default-src 'self';"},
+		{
+			name:       "several",
+			directives: []string{"default-src 'self'", "img-src data:"},
+			want:       "default-src 'self'; img-src data:;",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contentSecurityPolicy(tt.directives); got != tt.want {
+				t.Errorf("contentSecurityPolicy(%v) = %q, want %q", tt.directives, got, tt.want)
+			}
+		})
+	}
+}